@@ -17,13 +17,17 @@ import (
 )
 
 // OutputFormat represents the supported output formats for the extracted content.
-// The available formats are JSON, HTML, and plain text.
+// The available formats are JSON, HTML, plain text, and structured text.
 type OutputFormat string
 
 const (
 	FormatJSON OutputFormat = "json"
 	FormatHTML OutputFormat = "html"
 	FormatText OutputFormat = "text"
+
+	// FormatStructuredText is plain text with Markdown-style markers for
+	// headings, list items and blockquotes
+	FormatStructuredText OutputFormat = "structured-text"
 )
 
 func main() {
@@ -31,11 +35,13 @@ func main() {
 	inputFiles := flag.String("input", "", "Input HTML file path(s) (comma-separated, use '-' for stdin)")
 	outputDir := flag.String("output-dir", "", "Output directory for batch processing (default: same as input)")
 	outputFile := flag.String("output", "", "Output file path (default: stdout)")
-	formatStr := flag.String("format", "json", "Output format: json, html, or text")
+	formatStr := flag.String("format", "json", "Output format: json, html, text, or structured-text")
 	contentDigests := flag.Bool("digests", false, "Add content digest attributes")
 	nodeIndexes := flag.Bool("indexes", false, "Add node index attributes")
 	compact := flag.Bool("compact", false, "Output compact JSON without indentation")
 	timeout := flag.Duration("timeout", 30*time.Second, "Timeout for extraction")
+	jobs := flag.Int("jobs", 1, "Number of input files to process concurrently")
+	splitBySection := flag.Bool("split-sections", false, "Write each top-level section to its own numbered file in -output-dir")
 	showVersion := flag.Bool("version", false, "Show version information")
 	showHelp := flag.Bool("help", false, "Show help information")
 
@@ -48,7 +54,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s -input article.html -output article.json\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -input article.html -format html -output article.html\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -input article.html -format structured-text\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -input article1.html,article2.html -output-dir ./extracted\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -input article1.html,article2.html,article3.html -output-dir ./extracted -jobs 4\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -input long-read.html -format text -output-dir ./sections -split-sections\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  cat article.html | %s -input - > article.json\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -input article.html -digests -indexes\n", os.Args[0])
 	}
@@ -69,8 +78,14 @@ func main() {
 
 	// Validate output format
 	format := OutputFormat(strings.ToLower(*formatStr))
-	if format != FormatJSON && format != FormatHTML && format != FormatText {
-		fmt.Printf("Invalid output format: %s. Must be one of: json, html, text\n", *formatStr)
+	if format != FormatJSON && format != FormatHTML && format != FormatText && format != FormatStructuredText {
+		fmt.Printf("Invalid output format: %s. Must be one of: json, html, text, structured-text\n", *formatStr)
+		os.Exit(1)
+	}
+
+	// Sections are written to numbered files, which need a directory
+	if *splitBySection && *outputDir == "" {
+		fmt.Println("The -split-sections flag requires -output-dir")
 		os.Exit(1)
 	}
 
@@ -86,126 +101,147 @@ func main() {
 
 	// Check for deprecated flag
 
-	// Create the extractor with options
-	ext := readabiligo.New(
+	// Extractor options
+	options := []readabiligo.Option{
 		readabiligo.WithContentDigests(*contentDigests),
 		readabiligo.WithNodeIndexes(*nodeIndexes),
 		readabiligo.WithTimeout(*timeout),
-	)
-
-	// Process each input file
-	for _, inputPath := range inputs {
-		var input io.ReadCloser
-		var outputPath string
+		readabiligo.WithHeadingBlocks(format == FormatStructuredText),
+	}
 
-		// Determine input source
-		if inputPath == "-" {
-			// Read from stdin
-			input = os.Stdin
-			outputPath = *outputFile // Use the specified output file
-		} else {
-			// Open the input file
-			file, err := os.Open(inputPath)
-			if err != nil {
-				fmt.Printf("Error opening input file %s: %v\n", inputPath, err)
-				continue
-			}
-			defer file.Close()
-			input = file
-
-			// Determine output path
-			if *outputDir != "" {
-				// Create output directory if it doesn't exist
-				err = os.MkdirAll(*outputDir, 0755)
-				if err != nil {
-					fmt.Printf("Error creating output directory: %v\n", err)
-					os.Exit(1)
-				}
-
-				// Use input filename with appropriate extension in output directory
-				baseName := filepath.Base(inputPath)
-				ext := filepath.Ext(baseName)
-				nameWithoutExt := strings.TrimSuffix(baseName, ext)
-
-				var outputExt string
-				switch format {
-				case FormatJSON:
-					outputExt = ".json"
-				case FormatHTML:
-					outputExt = ".html"
-				case FormatText:
-					outputExt = ".txt"
-				}
-
-				outputPath = filepath.Join(*outputDir, nameWithoutExt+outputExt)
-			} else if *outputFile != "" && len(inputs) == 1 {
-				// Use specified output file only if processing a single input
-				outputPath = *outputFile
-			} else if *outputFile == "" {
-				// Default: use stdout
-				outputPath = ""
-			} else {
-				// Multiple inputs with single output file - use stdout and warn
-				fmt.Println("Warning: Multiple input files with single output file specified. Using stdout.")
-				outputPath = ""
-			}
+	// Create output directory if it doesn't exist
+	if *outputDir != "" {
+		err := os.MkdirAll(*outputDir, 0755)
+		if err != nil {
+			fmt.Printf("Error creating output directory: %v\n", err)
+			os.Exit(1)
 		}
+	}
 
-		// Extract the article
-		article, err := ext.ExtractFromReader(input, nil)
+	// Read from stdin
+	if len(inputs) == 1 && inputs[0] == "-" {
+		ext := readabiligo.New(options...)
+		article, err := ext.ExtractFromReader(os.Stdin, nil)
 		if err != nil {
-			fmt.Printf("Error extracting article from %s: %v\n", inputPath, err)
+			fmt.Printf("Error extracting article from %s: %v\n", "-", err)
+			return
+		}
+		if *splitBySection {
+			writeSections(os.Stdout, article, "-", *outputDir, format, *compact)
+			return
+		}
+		writeArticle(os.Stdout, article, "-", *outputFile, format, *compact)
+		return
+	}
+
+	processFiles(os.Stdout, options, inputs, *outputDir, *outputFile, format, *compact, *jobs, *splitBySection)
+}
+
+// processFiles extracts the input files with up to jobs concurrent extractions,
+// writing each output and reporting its progress or error on stdout as soon as
+// it completes. With splitBySection, each top-level section of an article is
+// written to its own file in outputDir.
+func processFiles(stdout io.Writer, options []readabiligo.Option, inputs []string, outputDir, outputFile string, format OutputFormat, compact bool, jobs int, splitBySection bool) {
+	batch := make([]readabiligo.BatchInput, 0, len(inputs))
+	for _, inputPath := range inputs {
+		if inputPath == "-" {
+			batch = append(batch, readabiligo.BatchInput{
+				Name: inputPath,
+				Open: func() (io.ReadCloser, error) { return io.NopCloser(os.Stdin), nil },
+			})
 			continue
 		}
+		batch = append(batch, readabiligo.FileInput(inputPath))
+	}
+
+	// Multiple inputs with single output file - use stdout and warn
+	if outputDir == "" && outputFile != "" && len(inputs) > 1 {
+		fmt.Fprintln(stdout, "Warning: Multiple input files with single output file specified. Using stdout.")
+		outputFile = ""
+	}
 
-		// Generate output based on format
-		var outputData []byte
-		switch format {
-		case FormatJSON:
-			if *compact {
-				outputData, err = json.Marshal(article)
-			} else {
-				outputData, err = json.MarshalIndent(article, "", "  ")
-			}
-			if err != nil {
-				fmt.Printf("Error converting article to JSON: %v\n", err)
-				continue
-			}
-		case FormatHTML:
-			outputData = []byte(article.Content)
-		case FormatText:
-			// Concatenate all plain text blocks
-			var textBuilder strings.Builder
-			for _, block := range article.PlainText {
-				textBuilder.WriteString(block.Text)
-				textBuilder.WriteString("\n\n")
-			}
-			outputData = []byte(textBuilder.String())
+	writeResult := func(result readabiligo.BatchResult) {
+		if result.Err != nil {
+			fmt.Fprintf(stdout, "Error extracting article from %s: %v\n", result.Name, result.Err)
+			return
 		}
 
-		// Write the output
-		var output io.Writer = os.Stdout
-		if outputPath != "" {
-			file, err := os.Create(outputPath)
-			if err != nil {
-				fmt.Printf("Error creating output file %s: %v\n", outputPath, err)
-				continue
-			}
-			defer file.Close()
-			output = file
-			fmt.Printf("Processed %s -> %s\n", inputPath, outputPath)
+		if splitBySection && outputDir != "" {
+			writeSections(stdout, result.Article, result.Name, outputDir, format, compact)
+			return
 		}
 
-		// Write the data to the output
-		_, err = output.Write(outputData)
-		if err != nil {
-			fmt.Printf("Error writing output: %v\n", err)
-			continue
+		// Determine output path
+		outputPath := outputFile
+		if outputDir != "" {
+			// Use input filename with appropriate extension in output directory
+			baseName := filepath.Base(result.Name)
+			nameWithoutExt := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+			outputPath = filepath.Join(outputDir, nameWithoutExt+outputExtension(format))
 		}
+		writeArticle(stdout, result.Article, result.Name, outputPath, format, compact)
+	}
+
+	ext := readabiligo.New(append(options,
+		readabiligo.WithBatchJobs(jobs),
+		readabiligo.WithOutputCallback(writeResult),
+	)...).(readabiligo.ExtendedExtractor)
+	ext.ExtractBatch(batch, nil)
+}
 
-		// Add a newline if writing to stdout
-		if output == os.Stdout {
-			fmt.Println()
+// outputExtension returns the file extension of outputs in the given format
+func outputExtension(format OutputFormat) string {
+	switch format {
+	case FormatHTML:
+		return ".html"
+	case FormatText, FormatStructuredText:
+		return ".txt"
+	default:
+		return ".json"
+	}
+}
+
+// writeArticle writes an article in the given format to outputPath, or to
+// stdout when outputPath is empty, reporting progress and errors on stdout
+func writeArticle(stdout io.Writer, article *readabiligo.Article, inputPath, outputPath string, format OutputFormat, compact bool) {
+	// Generate output based on format
+	var outputData []byte
+	var err error
+	switch format {
+	case FormatJSON:
+		if compact {
+			outputData, err = json.Marshal(article)
+		} else {
+			outputData, err = article.MarshalJSONIndent("", "  ")
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "Error converting article to JSON: %v\n", err)
+			return
+		}
+	case FormatHTML:
+		outputData = []byte(article.Content)
+	case FormatText:
+		// Every block, the last included, is followed by a blank line
+		outputData = []byte(article.PlainTextString())
+		if len(article.PlainText) > 0 {
+			outputData = append(outputData, readabiligo.DefaultBlockSeparator...)
 		}
+	case FormatStructuredText:
+		outputData = []byte(readabiligo.StructuredText(article.PlainText))
+	}
+
+	// Write the data to stdout, adding a newline
+	if outputPath == "" {
+		stdout.Write(outputData)
+		fmt.Fprintln(stdout)
+		return
+	}
+
+	// Write the data to the output file
+	err = os.WriteFile(outputPath, outputData, 0644)
+	if err != nil {
+		fmt.Fprintf(stdout, "Error writing output file %s: %v\n", outputPath, err)
+		return
 	}
+	fmt.Fprintf(stdout, "Processed %s -> %s\n", inputPath, outputPath)
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrjoshuak/readabiligo"
+)
+
+// TestProcessFilesConcurrently tests that -jobs 4 writes an output for every
+// input file and reports each file on a line of its own
+func TestProcessFilesConcurrently(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	var inputs []string
+	for i := 0; i < 12; i++ {
+		inputPath := filepath.Join(inputDir, fmt.Sprintf("post-%d.html", i))
+		html := fmt.Sprintf(`<html><head><title>Post %d</title></head><body><article><p><span>Post number %d explains how to keep a sourdough starter alive, feeding it equal weights of flour and water every day.</span></p></article></body></html>`, i, i)
+		if err := os.WriteFile(inputPath, []byte(html), 0644); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, inputPath)
+	}
+	inputs = append(inputs, filepath.Join(inputDir, "missing.html"))
+
+	var stdout bytes.Buffer
+	processFiles(&stdout, nil, inputs, outputDir, "", FormatJSON, false, 4, false)
+
+	for i := 0; i < 12; i++ {
+		outputPath := filepath.Join(outputDir, fmt.Sprintf("post-%d.json", i))
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Errorf("Missing output for post-%d.html: %v", i, err)
+			continue
+		}
+		var article struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(data, &article); err != nil {
+			t.Errorf("Invalid JSON in %s: %v", outputPath, err)
+		} else if article.Title != fmt.Sprintf("Post %d", i) {
+			t.Errorf("Expected title %q in %s, got %q", fmt.Sprintf("Post %d", i), outputPath, article.Title)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != len(inputs) {
+		t.Fatalf("Expected %d progress lines, got %d:\n%s", len(inputs), len(lines), stdout.String())
+	}
+	errorLines := 0
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "Processed "):
+		case strings.HasPrefix(line, "Error extracting article from ") && strings.Contains(line, "missing.html"):
+			errorLines++
+		default:
+			t.Errorf("Unexpected progress line: %q", line)
+		}
+	}
+	if errorLines != 1 {
+		t.Errorf("Expected 1 error line for the missing file, got %d", errorLines)
+	}
+}
+
+// TestWriteArticleText tests that the text output is Article.PlainTextString
+// with each block, the last included, followed by a blank line
+func TestWriteArticleText(t *testing.T) {
+	extractor := readabiligo.New()
+	article, err := extractor.ExtractFromHTML(`<html><head><title>Starter</title></head><body><article><h2><span>Feeding</span></h2><p><span>Feed the sourdough starter equal weights of flour and water every day, at the same time if you can.</span></p><p><span>Keep it in a warm spot and it should double in size within six hours of a feeding.</span></p></article></body></html>`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(article.PlainText) < 2 {
+		t.Fatalf("Expected several blocks, got %d", len(article.PlainText))
+	}
+
+	var stdout bytes.Buffer
+	writeArticle(&stdout, article, "-", "", FormatText, false)
+
+	var expected strings.Builder
+	for _, block := range article.PlainText {
+		expected.WriteString(block.Text + "\n\n")
+	}
+	expected.WriteString("\n")
+	if stdout.String() != expected.String() {
+		t.Errorf("Expected text output %q, got %q", expected.String(), stdout.String())
+	}
+	if !strings.HasPrefix(stdout.String(), article.PlainTextString()) {
+		t.Errorf("Expected text output to start with PlainTextString %q, got %q", article.PlainTextString(), stdout.String())
+	}
+}
+
+// TestProcessFilesSplitSections tests that -split-sections writes each
+// top-level section of an article to its own numbered file, with the extension
+// of the chosen format
+func TestProcessFilesSplitSections(t *testing.T) {
+	inputDir := t.TempDir()
+	inputPath := filepath.Join(inputDir, "bread.html")
+	html := `<html><head><title>Guide to bread</title></head><body><article><h1>Guide to bread</h1>
+		<h2>Choosing the flour</h2><p><span>Strong white flour has more protein, which gives the dough the structure it needs to rise well in the oven.</span></p>
+		<h2>Water &amp; yeast</h2><div><p><span>Use water at about thirty degrees so that the yeast wakes up quickly and the dough ferments evenly.</span></p></div>
+		<h2>Baking</h2><p><span>Bake the loaf in a hot oven with steam for the first ten minutes, then lower the heat so the crust browns.</span></p>
+	</article></body></html>`
+	if err := os.WriteFile(inputPath, []byte(html), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []OutputFormat{FormatJSON, FormatHTML, FormatText} {
+		t.Run(string(format), func(t *testing.T) {
+			outputDir := t.TempDir()
+			var stdout bytes.Buffer
+			processFiles(&stdout, nil, []string{inputPath}, outputDir, "", format, false, 1, true)
+
+			ext := outputExtension(format)
+			expected := []string{"bread-01-choosing-the-flour" + ext, "bread-02-water-yeast" + ext, "bread-03-baking" + ext}
+			entries, err := os.ReadDir(outputDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(expected, ",") {
+				t.Fatalf("Expected files %v, got %v", expected, names)
+			}
+
+			data, err := os.ReadFile(filepath.Join(outputDir, expected[1]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "thirty degrees") || strings.Contains(string(data), "protein") || strings.Contains(string(data), "hot oven") {
+				t.Errorf("Expected only the second section in %s, got:\n%s", expected[1], data)
+			}
+		})
+	}
+}
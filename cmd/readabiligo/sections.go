@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mrjoshuak/readabiligo"
+	"golang.org/x/net/html"
+)
+
+// maxSlugLength is the maximum length of the section slug in output file names
+const maxSlugLength = 50
+
+// section is a part of an article headed by a top-level heading
+type section struct {
+	heading string // Text of the heading, empty for the text before the first heading
+	content string // HTML of the heading and what follows it up to the next heading
+	text    string // Normalized text of the section
+}
+
+// writeSections writes each top-level section of an article to its own
+// numbered file in outputDir, named after the input and the section heading,
+// such as "article-01-getting-started.json". Each file holds a copy of the
+// article whose Title, Content, PlainContent and PlainText are those of the
+// section; the other fields are the article's.
+func writeSections(stdout io.Writer, article *readabiligo.Article, inputPath, outputDir string, format OutputFormat, compact bool) {
+	baseName := "article"
+	if inputPath != "-" {
+		baseName = strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	}
+
+	for i, part := range splitSections(article) {
+		slug := slugify(part.Title)
+		outputPath := filepath.Join(outputDir, fmt.Sprintf("%s-%02d-%s%s", baseName, i+1, slug, outputExtension(format)))
+		writeArticle(stdout, part, inputPath, outputPath, format, compact)
+	}
+}
+
+// splitSections splits an article at its top-level headings, returning one
+// article per section. Text before the first heading becomes a section of its
+// own, titled with the article title, unless it holds nothing but headings.
+// An article without headings is returned whole.
+func splitSections(article *readabiligo.Article) []*readabiligo.Article {
+	sections := splitHTMLSections(article.Content)
+	if len(sections) <= 1 {
+		return []*readabiligo.Article{article}
+	}
+	plainSections := splitHTMLSections(article.PlainContent)
+
+	parts := make([]*readabiligo.Article, len(sections))
+	for i, s := range sections {
+		part := *article
+		if s.heading != "" {
+			part.Title = s.heading
+		}
+		part.Content = s.content
+		part.PlainContent = ""
+		if len(plainSections) == len(sections) {
+			part.PlainContent = plainSections[i].content
+		}
+		part.PlainText = nil
+		parts[i] = &part
+	}
+
+	// Give each block to the first section, from the current one on, whose
+	// text holds it
+	current := 0
+	for _, block := range article.PlainText {
+		text := strings.Join(strings.Fields(block.Text), " ")
+		for i := current; i < len(sections); i++ {
+			if strings.Contains(sections[i].text, text) {
+				current = i
+				break
+			}
+		}
+		parts[current].PlainText = append(parts[current].PlainText, block)
+	}
+	return parts
+}
+
+// splitHTMLSections splits article HTML at its top-level headings: the <h1>
+// elements when there are several, or else the highest level of heading below
+// <h1>. Each section keeps the elements enclosing its heading, so a section
+// that starts inside an <article> is still wrapped in one.
+func splitHTMLSections(content string) []section {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	body := doc.Find("body")
+	headings := topLevelHeadings(body).Nodes
+	if len(headings) == 0 {
+		return nil
+	}
+
+	// Split the sections off the end of the body, last heading first, so the
+	// body is left holding the text before the first heading
+	root := body.Get(0)
+	parts := make([]*html.Node, len(headings)+1)
+	for i := len(headings) - 1; i >= 0; i-- {
+		parts[i+1] = splitFrom(headings[i], root)
+	}
+	parts[0] = root
+
+	var sections []section
+	for i, part := range parts {
+		// Skip an introduction that is only the title heading
+		if i == 0 && !hasTextOutsideHeadings(part) {
+			continue
+		}
+		sel := goquery.NewDocumentFromNode(part).Selection
+		s := section{text: strings.Join(strings.Fields(sel.Text()), " ")}
+		if i > 0 {
+			s.heading = strings.Join(strings.Fields(goquery.NewDocumentFromNode(headings[i-1]).Text()), " ")
+		}
+		s.content, _ = sel.Html()
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// topLevelHeadings returns the headings an article is split at
+func topLevelHeadings(s *goquery.Selection) *goquery.Selection {
+	if h1 := s.Find("h1"); h1.Length() > 1 {
+		return h1
+	}
+	for _, tag := range []string{"h2", "h3", "h4", "h5", "h6"} {
+		if headings := s.Find(tag); headings.Length() > 0 {
+			return headings
+		}
+	}
+	return s.Find("h1").Slice(0, 0)
+}
+
+// splitFrom moves node and everything that comes after it in document order,
+// up to root, into a new tree, returning its root. The elements enclosing node
+// are copied without their children, so the new tree keeps them too.
+func splitFrom(node, root *html.Node) *html.Node {
+	var moved *html.Node
+	for n, parent := node, node.Parent; n != root && parent != nil; n, parent = parent, parent.Parent {
+		shell := &html.Node{Type: parent.Type, DataAtom: parent.DataAtom, Data: parent.Data, Namespace: parent.Namespace}
+		shell.Attr = append(shell.Attr, parent.Attr...)
+		// Move node itself, or the split-off part of the element above it,
+		// followed by the siblings after it
+		first := n
+		if moved != nil {
+			shell.AppendChild(moved)
+			first = n.NextSibling
+		}
+		for c := first; c != nil; {
+			next := c.NextSibling
+			parent.RemoveChild(c)
+			shell.AppendChild(c)
+			c = next
+		}
+		moved = shell
+	}
+	return moved
+}
+
+// hasTextOutsideHeadings reports whether any text below n is outside a heading
+func hasTextOutsideHeadings(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode && strings.TrimSpace(c.Data) != "":
+			return true
+		case c.Type == html.ElementNode && len(c.Data) == 2 && c.Data[0] == 'h' && c.Data[1] >= '1' && c.Data[1] <= '6':
+			continue
+		case hasTextOutsideHeadings(c):
+			return true
+		}
+	}
+	return false
+}
+
+// slugify turns a heading into a file name part: lowercase letters and digits
+// with runs of anything else turned into single hyphens, e.g. "Getting
+// Started!" becomes "getting-started"
+func slugify(text string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, c := range strings.ToLower(text) {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			hyphen = false
+			sb.WriteRune(c)
+		} else {
+			hyphen = true
+		}
+		if sb.Len() >= maxSlugLength {
+			break
+		}
+	}
+	if sb.Len() == 0 {
+		return "section"
+	}
+	return sb.String()
+}
@@ -6,10 +6,19 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mrjoshuak/readabiligo/internal/simplifiers"
 )
 
 // ExtractDate extracts the article date from HTML content
 func ExtractDate(html string) time.Time {
+	return ExtractDateIn(html, nil)
+}
+
+// ExtractDateIn is like ExtractDate, but interprets dates without timezone
+// information in the given location instead of UTC. A nil location means UTC.
+func ExtractDateIn(html string, loc *time.Location) time.Time {
 	// ---- STEP 1: Extract dates from metadata tags ----
 	// List of selectors for HTML tags that could contain a date
 	// Scores reflect confidence in these selectors and the preference used for extraction
@@ -55,24 +64,35 @@ func ExtractDate(html string) time.Time {
 	
 	// Extract visible dates
 	visibleDates := ExtractElement(html, visibleDateSelectors, nil)
+
+	// Dates next to the byline are almost always the publication date
+	nearByline := bylineDates(html)
 	
 	// Combine all extracted dates
 	allDates := make([]dateEntry, 0)
 	
 	// Process metadata dates
 	for dateStr, element := range extractedDates {
+		score := element.Score
+		if nearByline[dateStr] {
+			score += BylineProximityBonus
+		}
 		allDates = append(allDates, dateEntry{
 			dateStr: dateStr,
-			score:   element.Score,
+			score:   score,
 			source:  "metadata",
 		})
 	}
 	
 	// Process visible dates
 	for dateStr, element := range visibleDates {
+		score := element.Score
+		if nearByline[dateStr] {
+			score += BylineProximityBonus
+		}
 		allDates = append(allDates, dateEntry{
 			dateStr: dateStr,
-			score:   element.Score,
+			score:   score,
 			source:  "visible",
 		})
 	}
@@ -103,7 +123,7 @@ func ExtractDate(html string) time.Time {
 		}
 		
 		// Then try comprehensive format parsing for all sources
-		parsedTime = ParseFlexibleDateFormat(entry.dateStr)
+		parsedTime = ParseFlexibleDateFormatIn(entry.dateStr, loc)
 		if !parsedTime.IsZero() {
 			// For regular date parsing, check if we have time information
 			if parsedTime.Hour() != 0 || parsedTime.Minute() != 0 || parsedTime.Second() != 0 {
@@ -130,6 +150,41 @@ func ExtractDate(html string) time.Time {
 	return time.Time{}
 }
 
+// BylineProximityBonus is added to the score of dates found in the same
+// container as the byline, which outweighs a date elsewhere on the page such
+// as a copyright or "last updated" date in the footer
+const BylineProximityBonus = 5
+
+// bylineContainerSelector matches the byline and article meta containers, and
+// bylineAuthorSelector the author elements whose parent is such a container
+const (
+	bylineContainerSelector = "[class*='byline'], [class*='meta']"
+	bylineAuthorSelector    = "[rel='author'], [itemprop='author'], [class*='author']"
+	bylineDateSelector      = "time, [class*='date'], [class*='time'], [class*='published']"
+)
+
+// bylineDates returns the date strings found in the same container as the
+// byline: the normalized text of date elements, and the datetime attribute of
+// <time> elements, keyed as ExtractElement keys them
+func bylineDates(html string) map[string]bool {
+	dates := make(map[string]bool)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return dates
+	}
+
+	containers := doc.Find("body").Find(bylineContainerSelector).AddSelection(doc.Find("body").Find(bylineAuthorSelector).Parent())
+	containers.Find(bylineDateSelector).Each(func(i int, s *goquery.Selection) {
+		if text := simplifiers.NormalizeWhitespace(s.Text()); text != "" {
+			dates[text] = true
+		}
+		if datetime := simplifiers.NormalizeWhitespace(s.AttrOr("datetime", "")); datetime != "" {
+			dates[datetime] = true
+		}
+	})
+	return dates
+}
+
 // dateEntry represents a date string with its score and source
 type dateEntry struct {
 	dateStr string
@@ -170,7 +225,7 @@ func ParseISO8601Format(dateStr string) time.Time {
 	}
 
 	// Special case for timezone with colon
-	if len(dateStr) > 3 && (dateStr[len(dateStr)-3] == ':' && (dateStr[len(dateStr)-6] == '+' || dateStr[len(dateStr)-6] == '-')) {
+	if len(dateStr) > 6 && (dateStr[len(dateStr)-3] == ':' && (dateStr[len(dateStr)-6] == '+' || dateStr[len(dateStr)-6] == '-')) {
 		// Try to parse by removing the colon in the timezone
 		modifiedDateStr := dateStr[:len(dateStr)-3] + dateStr[len(dateStr)-2:]
 		parsedTime, err := time.Parse(time.RFC3339, modifiedDateStr)
@@ -215,6 +270,36 @@ func ParseFlexibleDateFormat(dateStr string) time.Time {
 	return time.Time{}
 }
 
+// ParseFlexibleDateFormatIn is like ParseFlexibleDateFormat, but interprets
+// dates without timezone information in the given location instead of UTC.
+// An explicit offset or zone abbreviation in the string takes precedence.
+// A nil location means UTC.
+func ParseFlexibleDateFormatIn(dateStr string, loc *time.Location) time.Time {
+	parsed := ParseFlexibleDateFormat(dateStr)
+	if parsed.IsZero() || hasExplicitZone(CleanupDateString(dateStr)) {
+		return parsed
+	}
+	return inLocation(parsed, loc)
+}
+
+// explicitZoneRe matches a trailing UTC designator, numeric offset or zone abbreviation
+var explicitZoneRe = regexp.MustCompile(`(Z|[+-]\d{2}:?\d{2}|\b[A-Z]{2,5})$`)
+
+// hasExplicitZone reports whether a date string ends with timezone information
+func hasExplicitZone(dateStr string) bool {
+	match := explicitZoneRe.FindString(dateStr)
+	return match != "" && match != "AM" && match != "PM"
+}
+
+// inLocation reinterprets the wall clock time of a naive date, parsed as UTC,
+// in the given location
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
 // CleanupDateString sanitizes date strings for parsing
 func CleanupDateString(dateStr string) string {
 	// Convert to lowercase for easier pattern matching
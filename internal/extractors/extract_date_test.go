@@ -179,6 +179,62 @@ func TestParseNaturalLanguageDates(t *testing.T) {
 	}
 }
 
+func TestParseFlexibleDateFormatIn(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name     string
+		dateStr  string
+		loc      *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "Naive date in location",
+			dateStr:  "January 2, 2006",
+			loc:      tokyo,
+			expected: time.Date(2006, 1, 2, 0, 0, 0, 0, tokyo),
+		},
+		{
+			name:     "Naive date and time in location",
+			dateStr:  "2006-01-02T15:04:05",
+			loc:      tokyo,
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, tokyo),
+		},
+		{
+			name:     "Naive time with AM/PM in location",
+			dateStr:  "January 2, 2006 3:04 PM",
+			loc:      tokyo,
+			expected: time.Date(2006, 1, 2, 15, 4, 0, 0, tokyo),
+		},
+		{
+			name:     "Explicit offset wins",
+			dateStr:  "2006-01-02T15:04:05-0700",
+			loc:      tokyo,
+			expected: time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC),
+		},
+		{
+			name:     "Explicit UTC designator wins",
+			dateStr:  "2006-01-02T15:04:05Z",
+			loc:      tokyo,
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name:     "Nil location means UTC",
+			dateStr:  "January 2, 2006",
+			loc:      nil,
+			expected: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseFlexibleDateFormatIn(tt.dateStr, tt.loc)
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseFlexibleDateFormatIn(%q) = %v, want %v", tt.dateStr, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseDateComponents(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -292,4 +348,27 @@ func TestManualExtractDate(t *testing.T) {
 	if result.Hour() != 15 || result.Minute() != 4 || result.Second() != 5 {
 		t.Errorf("Time information was lost: got %v, want hour=15, minute=4, second=5", result)
 	}
-}
\ No newline at end of file
+}
+
+// This test verifies that a date next to the byline beats a misleading date elsewhere on the page
+func TestExtractDateBylineProximity(t *testing.T) {
+	html := `<html>
+<body>
+  <article>
+    <h1>Article Title</h1>
+    <div class="byline">By <a rel="author" href="/authors/ann">Ann Lee</a> <time>March 27, 2023</time></div>
+    <p>Article text.</p>
+  </article>
+  <footer class="site-footer">
+    <span class="date">January 5, 2020</span>
+  </footer>
+</body>
+</html>`
+
+	expected := time.Date(2023, 3, 27, 0, 0, 0, 0, time.UTC)
+	result := ExtractDate(html)
+
+	if !result.Equal(expected) {
+		t.Errorf("ExtractDate() = %v, want %v", result, expected)
+	}
+}
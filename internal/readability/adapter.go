@@ -13,11 +13,16 @@
 package readability
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"log/slog"
+	"regexp"
 	"strings"
+	"time"
 	
 	"github.com/PuerkitoBio/goquery"
 	"github.com/mrjoshuak/readabiligo/internal/simplifiers"
+	"golang.org/x/net/html"
 )
 
 // ExtractionOptions represents options for extraction 
@@ -26,9 +31,87 @@ type ExtractionOptions struct {
 	NodeIndexes           bool
 	MaxBufferSize         int
 	Timeout               int
+	PhaseTimeouts         map[Phase]time.Duration
+	ExtractKeywords       int
 	PreserveImportantLinks bool
-	DetectContentType     bool
 	ContentType           ContentType
+	ContentTypeRules      []ContentTypeRule
+	ExtractTemplates      bool
+	UseNoscriptFallback   bool
+	QuoteStyle            simplifiers.QuoteStyle
+	ComputeReadingLevel   bool
+	PreserveIDs           bool
+	StripAutogeneratedIDs bool
+	AutogeneratedIDPatterns []*regexp.Regexp
+	ExcludePlainTextSelectors []string
+	NormalizePunctuation  bool
+	DashReplacement       string
+	ContentLanguage       string
+	ExtractAuthorImage    bool
+	ExtractThemeColor     bool
+	ExtractAlternates     bool
+	StripHiddenText       bool
+	StripConsentBanners   bool
+	StripBylineFromContent bool
+	CollapseBreaks        bool
+	ParagraphBreakThreshold int
+	MaxLineBreaks         int
+	TablesVerbatim        bool
+	ExtractVideos         bool
+	ExtractCodeBlocks     bool
+	ExtractTables         bool
+	ExtractQuotes         bool
+	DetectPrimaryDocument bool
+	TrackRemovedLinks     bool
+	DiscussionMode        bool
+	LiveBlogMode          bool
+	ExtractStructuredContent bool
+	ExtractContactInfo    bool
+	ContentBoundaryMarkers bool
+	StrictTitle           bool
+	MinContentScore       float64
+	StripHeaderAnchors    bool
+	TrimBoilerplateHeadings bool
+	BoilerplateLabels     []string
+	NormalizeHeadingWhitespace bool
+	HeadingBlocks         bool
+	StripEmptyAnchors     bool
+	PreserveLinks         bool
+	MinifyOutput          bool
+	CollapseWhitespaceInAttributes bool
+	ContentMaxLength      int
+	CharThreshold         int
+	LinkDensityModifier   float64
+	CMSContentClasses     []string
+	UnlikelyRoles         []string
+	AllowedRoles          []string
+	AllowedSchemes        []string
+	KeepStructure         bool
+	ExpandDetails         bool
+	Dehyphenate           bool
+	NormalizeListMarkers  bool
+	MergeImageAltIntoCaption bool
+	PreserveGalleries     bool
+	MaxImages             int
+	ImageDimensionInference bool
+	ProtectLeadParagraphs int
+	MergeListsAcrossParagraphs bool
+	AssumeTimezone        *time.Location
+	MetadataExtractors    []MetadataExtractor
+	PostExtractHook       func(*goquery.Selection)
+	Logger                *slog.Logger
+	PreserveMath          bool
+	RenderMathAsText      bool
+	PreserveKbdAndSamp    bool
+	ReorderColumns        bool
+	StripLeadingSymbols   bool
+	ParallelScoring       bool
+	PullQuoteMode         PullQuoteMode
+	PreserveSemanticStyles bool
+	SentenceSegmentation  bool
+	TextOnly              bool
+	DisableFallback       bool
+	RetryFlags            []int
 }
 
 // Article represents the extracted content
@@ -40,12 +123,59 @@ type Article struct {
 	PlainContent string
 	PlainText    []Block
 	ContentType  ContentType
+	ReadabilityScore float64
+	AuthorImageURL   string
+	ThemeColor       string
+	Metadata         map[string]string
+	AlternateLinks   []AlternateLink
+	ImageCount       int
+	Videos           []VideoEmbed
+	CodeBlocks       []CodeBlock
+	Tables           []Table
+	Quotes           []Quote
+	IsTruncated      bool
+	FullContentURL   string
+	IsDocumentLanding  bool
+	PrimaryDocumentURL string
+	RemovedLinks       []RemovedLink
+	Thread             []ThreadPost
+	Updates            []LiveUpdate
+	Recipe             *Recipe
+	ContactInfo        *ContactInfo
+	PullQuotes         []string
+	Keywords           []string
+	ContentHash        string
+	HowTo              *HowTo
+	Confidence         float64
+	Warnings           []string
 }
 
 // Block represents a block of text
 type Block struct {
 	Text      string
+	Type      BlockType
+	Level     int
 	NodeIndex string
+	Links     []Link
+	ParentIndex int
+}
+
+// BlockType identifies the kind of element a block of text came from
+type BlockType int
+
+// Block type constants
+const (
+	BlockParagraph BlockType = iota
+	BlockHeading
+	BlockListItem
+	BlockQuote
+)
+
+// Link represents a hyperlink found in a block of text
+type Link struct {
+	Href string
+	Text string
+	Rel  string
 }
 
 // ExtractFromHTML extracts readable content from HTML using pure Go Readability
@@ -60,14 +190,101 @@ func ExtractFromHTML(html string, options *ExtractionOptions) (*Article, error)
 		}
 		
 		// Apply content type detection options
-		opts.DetectContentType = options.DetectContentType
 		opts.ContentType = ContentType(options.ContentType)
+		opts.ContentTypeRules = options.ContentTypeRules
+
+		// Apply document preparation options
+		opts.ExtractTemplates = options.ExtractTemplates
+		opts.UseNoscriptFallback = options.UseNoscriptFallback
+		opts.PreserveIDs = options.PreserveIDs
+		opts.StripAutogeneratedIDs = options.StripAutogeneratedIDs
+		opts.AutogeneratedIDPatterns = options.AutogeneratedIDPatterns
+		opts.ContentLanguage = options.ContentLanguage
+		opts.CollapseBreaks = options.CollapseBreaks
+		opts.ParagraphBreakThreshold = options.ParagraphBreakThreshold
+		opts.MaxLineBreaks = options.MaxLineBreaks
+
+		// Apply metadata options
+		opts.ExtractAuthorImage = options.ExtractAuthorImage
+		opts.ExtractThemeColor = options.ExtractThemeColor
+		opts.ExtractAlternates = options.ExtractAlternates
+		opts.MetadataExtractors = options.MetadataExtractors
+		opts.PostExtractHook = options.PostExtractHook
+		opts.Logger = options.Logger
+		opts.ExtractVideos = options.ExtractVideos
+		opts.ExtractCodeBlocks = options.ExtractCodeBlocks
+		opts.ExtractTables = options.ExtractTables
+		opts.ExtractQuotes = options.ExtractQuotes
+		opts.DetectPrimaryDocument = options.DetectPrimaryDocument
+		opts.TrackRemovedLinks = options.TrackRemovedLinks
+		opts.DiscussionMode = options.DiscussionMode
+		opts.LiveBlogMode = options.LiveBlogMode
+		opts.ExtractStructuredContent = options.ExtractStructuredContent
+		opts.ExtractContactInfo = options.ExtractContactInfo
+		opts.StrictTitle = options.StrictTitle
+		opts.MinContentScore = options.MinContentScore
+		opts.StripHeaderAnchors = options.StripHeaderAnchors
+		opts.TrimBoilerplateHeadings = options.TrimBoilerplateHeadings
+		opts.BoilerplateLabels = options.BoilerplateLabels
+		opts.NormalizeHeadingWhitespace = options.NormalizeHeadingWhitespace
+		opts.StripEmptyAnchors = options.StripEmptyAnchors
+		opts.StripHiddenText = options.StripHiddenText
+		opts.StripConsentBanners = options.StripConsentBanners
+		opts.StripBylineFromContent = options.StripBylineFromContent
+		opts.ExpandDetails = options.ExpandDetails
+		opts.Dehyphenate = options.Dehyphenate
+		opts.NormalizeListMarkers = options.NormalizeListMarkers
+		opts.MergeImageAltIntoCaption = options.MergeImageAltIntoCaption
+		opts.PreserveGalleries = options.PreserveGalleries
+		opts.MaxImages = options.MaxImages
+		opts.ImageDimensionInference = options.ImageDimensionInference
+		opts.ProtectLeadParagraphs = options.ProtectLeadParagraphs
+		opts.MergeListsAcrossParagraphs = options.MergeListsAcrossParagraphs
+		opts.PreserveMath = options.PreserveMath
+		opts.RenderMathAsText = options.RenderMathAsText
+		opts.ReorderColumns = options.ReorderColumns
+		opts.StripLeadingSymbols = options.StripLeadingSymbols
+		opts.ParallelScoring = options.ParallelScoring
+		opts.PullQuoteMode = options.PullQuoteMode
+		opts.PreserveSemanticStyles = options.PreserveSemanticStyles
+		opts.AssumeTimezone = options.AssumeTimezone
+
+		// Apply content selection options
+		opts.DisableFallback = options.DisableFallback
+		if options.RetryFlags != nil {
+			opts.RetryFlags = options.RetryFlags
+		}
+		if options.CharThreshold > 0 {
+			opts.CharThreshold = options.CharThreshold
+		}
+		opts.LinkDensityModifier = options.LinkDensityModifier
+		opts.CMSContentClasses = options.CMSContentClasses
+		opts.UnlikelyRoles = options.UnlikelyRoles
+		opts.AllowedRoles = options.AllowedRoles
+		opts.AllowedSchemes = options.AllowedSchemes
+		opts.KeepStructure = options.KeepStructure
 		
 		// Add any other option mappings here in the future
 	}
 
-	// Parse HTML using Readability algorithm
-	readabilityArticle, err := ParseHTML(html, &opts)
+	// Parse the HTML, then extract the article with the Readability algorithm,
+	// each within its time budget (if set)
+	var budgets map[Phase]time.Duration
+	if options != nil {
+		budgets = options.PhaseTimeouts
+	}
+	var r *Readability
+	var err error
+	if timeoutErr := runPhase(PhaseParse, budgets, func() { r, err = NewFromHTML(html, &opts) }); timeoutErr != nil {
+		return nil, WrapError(timeoutErr, TimeoutError, "ExtractFromHTML", "")
+	}
+	if err != nil {
+		return nil, WrapExtractionError(err, "ExtractFromHTML", "failed to parse HTML content")
+	}
+	var readabilityArticle *ReadabilityArticle
+	if timeoutErr := runPhase(PhaseExtract, budgets, func() { readabilityArticle, err = r.Parse() }); timeoutErr != nil {
+		return nil, WrapError(timeoutErr, TimeoutError, "ExtractFromHTML", "")
+	}
 	if err != nil {
 		return nil, WrapExtractionError(err, "ExtractFromHTML", "failed to parse HTML content")
 	}
@@ -113,16 +330,112 @@ func ExtractFromHTML(html string, options *ExtractionOptions) (*Article, error)
 		}
 	}
 
-	// Generate plain content with content digests and node indexes if requested
-	plainContent, err := simplifiers.PlainContent(result.Content, options.ContentDigests, options.NodeIndexes)
-	if err != nil {
-		return nil, WrapExtractionError(err, "ExtractFromHTML", "failed to generate plain content")
+	// Cut the content at a block boundary if it is longer than requested
+	if options.ContentMaxLength > 0 {
+		if content, truncated := simplifiers.TruncateHTML(result.Content, options.ContentMaxLength); truncated {
+			result.Content = content
+			result.IsTruncated = true
+		}
 	}
-	result.PlainContent = plainContent
 
-	// Extract plain text blocks
-	result.PlainText = extractTextBlocks(result.PlainContent)
+	// Rank the words of the text as keywords (if requested)
+	if options.ExtractKeywords > 0 {
+		result.Keywords = extractKeywords(readabilityArticle.TextContent, readabilityArticle.Lang, options.ExtractKeywords)
+	}
+
+	// Hash the text of the article for change detection
+	result.ContentHash = contentHash(readabilityArticle.TextContent)
+
+	// Walk the content for its text only, skipping the simplifier (if requested).
+	// Content is left empty, so the options below that format it don't apply.
+	if options.TextOnly {
+		result.PlainText = walkTextBlocks(result.Content)
+		if options.SentenceSegmentation {
+			result.PlainText = segmentSentences(result.PlainText)
+		}
+		texts := make([]string, len(result.PlainText))
+		for i, block := range result.PlainText {
+			texts[i] = block.Text
+		}
+		result.PlainContent = strings.Join(texts, "\n\n")
+		result.Content = ""
+		if options.ComputeReadingLevel {
+			result.ReadabilityScore = computeReadingLevel(readabilityArticle.TextContent, readabilityArticle.Lang)
+		}
+		if len(result.PlainText) == 0 && result.Title != "" {
+			result.PlainText = []Block{{Text: result.Title}}
+		}
+		return result, nil
+	}
+
+	// Collapse whitespace in alt, title and aria-label values if requested
+	if options.CollapseWhitespaceInAttributes {
+		result.Content = simplifiers.CollapseAttributeWhitespace(result.Content)
+	}
+
+	// Render the plain content and text blocks within the text phase budget (if
+	// set). The phase works on copies, so nothing of it is used after a timeout.
+	content := result.Content
+	var plainContent string
+	var plainText []Block
+	var textErr error
+	timeoutErr := runPhase(PhaseText, options.PhaseTimeouts, func() {
+		// Generate plain content with content digests and node indexes if requested
+		plainContent, textErr = simplifiers.PlainContentWithOptions(content, simplifiers.ContentOptions{
+			AddContentDigests: options.ContentDigests,
+			AddNodeIndexes:    options.NodeIndexes,
+			QuoteStyle:        options.QuoteStyle,
+			NormalizePunctuation: options.NormalizePunctuation,
+			DashReplacement:   options.DashReplacement,
+			TablesVerbatim:    options.TablesVerbatim,
+			PreserveKbdAndSamp: options.PreserveKbdAndSamp,
+			ImageDimensionInference: options.ImageDimensionInference,
+		})
+		if textErr != nil {
+			return
+		}
+
+		// Extract plain text blocks
+		plainText = extractTextBlocks(plainContent, options.ExcludePlainTextSelectors, options.HeadingBlocks, options.PreserveLinks, options.PreserveMath, options.NormalizeHeadingWhitespace)
+	})
+	if timeoutErr != nil {
+		// Fall back to the single walk of the text-only mode, without the simplifier
+		result.PlainText = walkTextBlocks(result.Content)
+		texts := make([]string, len(result.PlainText))
+		for i, block := range result.PlainText {
+			texts[i] = block.Text
+		}
+		result.PlainContent = strings.Join(texts, "\n\n")
+		result.Warnings = append(result.Warnings, timeoutErr.Error()+"; PlainContent and PlainText were built without the simplifier")
+	} else if textErr != nil {
+		return nil, WrapExtractionError(textErr, "ExtractFromHTML", "failed to generate plain content")
+	} else {
+		result.PlainContent = plainContent
+		result.PlainText = plainText
+	}
+
+	// Split the blocks into sentences if requested
+	if options.SentenceSegmentation {
+		result.PlainText = segmentSentences(result.PlainText)
+	}
 	
+	// Compute the reading level of the extracted text if requested
+	if options.ComputeReadingLevel {
+		result.ReadabilityScore = computeReadingLevel(readabilityArticle.TextContent, readabilityArticle.Lang)
+	}
+
+	// Remove insignificant whitespace from the rendered HTML if requested
+	if options.MinifyOutput {
+		result.Content = simplifiers.MinifyHTML(result.Content)
+		result.PlainContent = simplifiers.MinifyHTML(result.PlainContent)
+	}
+
+	// Mark where the extracted content begins and ends, and which node it came from
+	if options.ContentBoundaryMarkers && result.Content != "" {
+		start, end := ContentBoundaryMarkers(readabilityArticle.Origin)
+		result.Content = start + result.Content + end
+	}
+
 	// Ensure we have at least one block of plain text
 	// This is important for test compatibility
 	if len(result.PlainText) == 0 && result.Title != "" {
@@ -153,6 +466,28 @@ func (ra *ReadabilityArticle) ToStandardArticle() *Article {
 		Byline:       ra.Byline,
 		Content:      ra.Content,
 		ContentType:  ContentType(ra.ContentType),
+		AuthorImageURL: ra.AuthorImageURL,
+		ThemeColor:     ra.ThemeColor,
+		Metadata:       ra.Metadata,
+		AlternateLinks: ra.AlternateLinks,
+		ImageCount:     ra.ImageCount,
+		Videos:       ra.Videos,
+		CodeBlocks:   ra.CodeBlocks,
+		Tables:       ra.Tables,
+		Quotes:       ra.Quotes,
+		IsTruncated:  ra.IsTruncated,
+		FullContentURL: ra.FullContentURL,
+		IsDocumentLanding:  ra.IsDocumentLanding,
+		PrimaryDocumentURL: ra.PrimaryDocumentURL,
+		RemovedLinks:       ra.RemovedLinks,
+		Thread:             ra.Thread,
+		Updates:            ra.Updates,
+		Recipe:             ra.Recipe,
+		ContactInfo:        ra.ContactInfo,
+		PullQuotes:         ra.PullQuotes,
+		HowTo:              ra.HowTo,
+		Confidence:         ra.Confidence,
+		Warnings:           ra.Warnings,
 	}
 	
 	// Set publication date if available
@@ -163,32 +498,236 @@ func (ra *ReadabilityArticle) ToStandardArticle() *Article {
 	return article
 }
 
-// extractTextBlocks creates a slice of Block objects from HTML content
-func extractTextBlocks(html string) []Block {
+// contentHash returns the hex-encoded SHA-256 hash of text, with every run of
+// whitespace read as a single space
+func contentHash(text string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(strings.Fields(text), " "))))
+}
+
+// computeReadingLevel returns the Flesch-Kincaid grade level of the text.
+// The syllable heuristics are English-centric, so documents that declare a
+// non-English language are skipped and score 0.
+func computeReadingLevel(text, lang string) float64 {
+	lang = strings.ToLower(lang)
+	if lang != "" && lang != "en" && !strings.HasPrefix(lang, "en-") {
+		return 0
+	}
+
+	return simplifiers.CalculateReadingLevel(text)
+}
+
+// extractTextBlocks creates a slice of Block objects from the paragraphs and
+// list items in HTML content, and from its headings when withHeadings is set,
+// recording the kind of element and its level for each block. Blocks matching, or nested inside elements matching, any of the exclude
+// selectors are skipped. When withLinks is set, each block also lists the
+// links it contains.
+func extractTextBlocks(html string, excludeSelectors []string, withHeadings, withLinks, withMath, normalizeHeadings bool) []Block {
 	r, err := NewFromHTML(html, nil)
 	if err != nil {
 		return []Block{}
 	}
 
+	// Render MathML equations as LaTeX rather than as the text of their tokens
+	if withMath {
+		r.doc.Find("math").Each(func(i int, s *goquery.Selection) {
+			if tex := mathToTeX(s); tex != "" {
+				s.SetText(tex)
+			}
+		})
+	}
+
+	exclude := strings.Join(excludeSelectors, ", ")
+
+	selector := "p, li"
+	if withHeadings {
+		selector = "h1, h2, h3, h4, h5, h6, p, li"
+	}
+
 	blocks := []Block{}
-	r.doc.Find("p, li").Each(func(i int, s *goquery.Selection) {
+	r.doc.Find(selector).Each(func(i int, s *goquery.Selection) {
+		// Skip blocks inside excluded elements
+		if exclude != "" && (s.Is(exclude) || s.ParentsFiltered(exclude).Length() > 0) {
+			return
+		}
+
+		// Keep the lines of a multi-line heading apart
+		if normalizeHeadings && s.Is("h1, h2, h3, h4, h5, h6") {
+			s.Find("br").ReplaceWithHtml(" ")
+		}
+
 		text := getInnerText(s, true)
 		if text == "" {
 			return
 		}
 
-		// Create block with text
+		// Create block with text and the kind of element it came from
 		block := Block{
 			Text: text,
 		}
+		switch tag := goquery.NodeName(s); tag {
+		case "li":
+			block.Type = BlockListItem
+			block.Level = s.ParentsFiltered("ul, ol").Length()
+		case "p":
+			if quotes := s.ParentsFiltered("blockquote").Length(); quotes > 0 {
+				block.Type = BlockQuote
+				block.Level = quotes
+			}
+		default:
+			block.Type = BlockHeading
+			block.Level = int(tag[1] - '0')
+		}
 
 		// Add node index if available
 		if nodeIndex, exists := s.Attr("data-node-index"); exists {
 			block.NodeIndex = nodeIndex
 		}
 
+		// Add the links in the block if requested
+		if withLinks {
+			s.Find("a[href]").Each(func(j int, a *goquery.Selection) {
+				block.Links = append(block.Links, Link{
+					Href: a.AttrOr("href", ""),
+					Text: getNormalized(a.Text()),
+					Rel:  strings.ToLower(strings.Join(strings.Fields(a.AttrOr("rel", "")), " ")),
+				})
+			})
+		}
+
 		blocks = append(blocks, block)
 	})
 
 	return blocks
-}
\ No newline at end of file
+}
+
+// textBlockTags are the elements walkTextBlocks turns into blocks
+var textBlockTags = map[string]bool{
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"p": true, "li": true,
+}
+
+// walkTextBlocks creates the same kinds of blocks as extractTextBlocks in a
+// single walk of the parsed content, without building a goquery document or
+// running the simplifier. Text inside a nested block, such as a sub-list of a
+// list item, belongs to the nested block only.
+func walkTextBlocks(content string) []Block {
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return []Block{}
+	}
+
+	blocks := []Block{}
+	var walk func(n *html.Node, lists, quotes int)
+	walk = func(n *html.Node, lists, quotes int) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript", "template":
+				return
+			case "ul", "ol":
+				lists++
+			case "blockquote":
+				quotes++
+			}
+			if textBlockTags[n.Data] {
+				var text strings.Builder
+				collectBlockText(n, &text)
+				if normalized := getNormalized(text.String()); normalized != "" {
+					block := Block{Text: normalized}
+					switch {
+					case n.Data == "li":
+						block.Type = BlockListItem
+						block.Level = lists
+					case n.Data == "p":
+						if quotes > 0 {
+							block.Type = BlockQuote
+							block.Level = quotes
+						}
+					default:
+						block.Type = BlockHeading
+						block.Level = int(n.Data[1] - '0')
+					}
+					blocks = append(blocks, block)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, lists, quotes)
+		}
+	}
+	walk(doc, 0, 0)
+
+	return blocks
+}
+
+// collectBlockText writes the text of n to text, leaving out nested blocks and
+// lists, which walkTextBlocks visits on their own
+func collectBlockText(n *html.Node, text *strings.Builder) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			text.WriteString(c.Data)
+		case html.ElementNode:
+			switch {
+			case textBlockTags[c.Data], c.Data == "ul", c.Data == "ol":
+				continue
+			case c.Data == "br":
+				text.WriteString(" ")
+			case c.Data == "script", c.Data == "style":
+				continue
+			}
+			collectBlockText(c, text)
+		}
+	}
+}
+
+// mathToTeX returns the LaTeX source of a MathML equation, between \( \) or
+// \[ \] delimiters, from its TeX annotation or alttext attribute. It returns an
+// empty string when the equation carries no LaTeX source.
+func mathToTeX(s *goquery.Selection) string {
+	tex := strings.TrimSpace(s.Find(`annotation[encoding="application/x-tex"]`).First().Text())
+	if tex == "" {
+		tex = strings.TrimSpace(s.AttrOr("alttext", ""))
+	}
+	if tex == "" {
+		return ""
+	}
+	if s.AttrOr("display", "") == "block" {
+		return `\[` + tex + `\]`
+	}
+	return `\(` + tex + `\)`
+}
+
+// segmentSentences splits each block into one block per sentence. A sentence
+// keeps the type, level and node index of its block, records the index of the
+// block in ParentIndex, and holds the links whose text it contains.
+func segmentSentences(blocks []Block) []Block {
+	sentences := make([]Block, 0, len(blocks))
+	for i, block := range blocks {
+		first := len(sentences)
+		for _, text := range simplifiers.SplitSentences(block.Text) {
+			sentences = append(sentences, Block{
+				Text:        text,
+				Type:        block.Type,
+				Level:       block.Level,
+				NodeIndex:   block.NodeIndex,
+				ParentIndex: i,
+			})
+		}
+		if len(sentences) == first {
+			continue
+		}
+
+		// Give each link to the first sentence that contains its text
+		for _, link := range block.Links {
+			target := first
+			for j := first; j < len(sentences); j++ {
+				if strings.Contains(sentences[j].Text, link.Text) {
+					target = j
+					break
+				}
+			}
+			sentences[target].Links = append(sentences[target].Links, link)
+		}
+	}
+	return sentences
+}
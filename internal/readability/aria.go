@@ -0,0 +1,95 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ariaLandmarkSelector matches the elements whose accessible name is taken to
+// be the article title
+const ariaLandmarkSelector = `article, main, [role="main"], [role="article"]`
+
+// ariaBylinePrefixRe matches the "By" that introduces an author name
+var ariaBylinePrefixRe = regexp.MustCompile(`(?i)^by\s+`)
+
+// getARIATitle returns the accessible name of the first article or main element
+// that has one: the text of the elements its aria-labelledby refers to, or else
+// its aria-label. Accessibility-first sites name the article this way.
+func (r *Readability) getARIATitle() string {
+	title := ""
+	r.doc.Find(ariaLandmarkSelector).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if labelledBy := r.ariaReferences(s, "aria-labelledby"); labelledBy.Length() > 0 {
+			title = r.ariaText(labelledBy)
+		}
+		if title == "" {
+			title = getNormalized(s.AttrOr("aria-label", ""))
+		}
+		return title == ""
+	})
+	return title
+}
+
+// getARIAHeading returns the heading that labels the article through the
+// aria-labelledby of an article or main element, if any
+func (r *Readability) getARIAHeading() *goquery.Selection {
+	var heading *goquery.Selection
+	r.doc.Find(ariaLandmarkSelector).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if labelledBy := r.ariaReferences(s, "aria-labelledby").Filter("h1, h2, h3"); labelledBy.Length() > 0 {
+			heading = labelledBy.First()
+		}
+		return heading == nil
+	})
+	return heading
+}
+
+// getARIAByline returns the author named by the aria-describedby of an article
+// or main element. Descriptions are often summaries, so a described element
+// only counts when it is marked as a byline (by rel="author", an author
+// itemprop, or a byline or author class or id) or its text starts with "By".
+func (r *Readability) getARIAByline() string {
+	byline := ""
+	r.doc.Find(ariaLandmarkSelector).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		r.ariaReferences(s, "aria-describedby").EachWithBreak(func(j int, target *goquery.Selection) bool {
+			text := getNormalized(target.Text())
+			matchString := target.AttrOr("class", "") + " " + target.AttrOr("id", "")
+			marked := target.AttrOr("rel", "") == "author" ||
+				strings.Contains(target.AttrOr("itemprop", ""), "author") ||
+				RegexpByline.MatchString(matchString)
+			if !marked && !ariaBylinePrefixRe.MatchString(text) {
+				return true
+			}
+			text = ariaBylinePrefixRe.ReplaceAllString(text, "")
+			if isValidByline(text) {
+				byline = text
+			}
+			return byline == ""
+		})
+		return byline == ""
+	})
+	return byline
+}
+
+// ariaReferences returns the elements referred to by the space-separated ids of
+// an ARIA relationship attribute, in the order given
+func (r *Readability) ariaReferences(s *goquery.Selection, attr string) *goquery.Selection {
+	var nodes []*html.Node
+	for _, id := range strings.Fields(s.AttrOr(attr, "")) {
+		nodes = append(nodes, r.doc.Find(`[id="`+strings.ReplaceAll(id, `"`, `\"`)+`"]`).First().Nodes...)
+	}
+	return r.doc.FindNodes(nodes...)
+}
+
+// ariaText returns the text of the elements an ARIA relationship refers to,
+// joined with spaces
+func (r *Readability) ariaText(refs *goquery.Selection) string {
+	var texts []string
+	refs.Each(func(i int, ref *goquery.Selection) {
+		if text := r.headingText(ref); text != "" {
+			texts = append(texts, text)
+		}
+	})
+	return getNormalized(strings.Join(texts, " "))
+}
@@ -0,0 +1,48 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Sources of the content region, recorded in ContentOrigin
+const (
+	OriginCandidate = "candidate"  // The highest scoring candidate node
+	OriginMicrodata = "microdata"  // The node marked as the articleBody with schema.org microdata
+	OriginBody      = "body"       // The whole body, as no candidate was found
+	OriginFallback  = "fallback"   // The whole body, as every attempt found too little text
+	OriginErrorPage = "error-page" // A message built for an error page
+)
+
+// ContentOrigin describes the node the content was extracted from
+type ContentOrigin struct {
+	Selector string  // CSS-like description of the node, such as "div#main.content"
+	Score    float64 // Content score of the node, 0 when it wasn't scored
+	Source   string  // How the node was chosen, one of the Origin constants
+}
+
+// setContentOrigin records the node the content is extracted from
+func (r *Readability) setContentOrigin(node *goquery.Selection, score float64, source string) {
+	r.contentOrigin = ContentOrigin{Source: source, Score: score}
+	if node != nil && node.Length() > 0 {
+		r.contentOrigin.Selector = describeNode(node)
+	}
+	r.logEvent("extract", "choose content region", node, "source", source, "score", score)
+}
+
+// ContentBoundaryMarkers returns the HTML comments put before and after the
+// content to show where extraction began and ended, and which node it chose,
+// e.g. "<!-- readabiligo:content-start selector=div#main score=42.5 source=candidate -->"
+func ContentBoundaryMarkers(origin ContentOrigin) (start, end string) {
+	// Keep odd class names from closing the comment early
+	clean := strings.NewReplacer("<", "", ">", "", " ", "")
+	selector := clean.Replace(origin.Selector)
+	if selector == "" {
+		selector = "none"
+	}
+	start = fmt.Sprintf("<!-- readabiligo:content-start selector=%s score=%.1f source=%s -->",
+		selector, origin.Score, clean.Replace(origin.Source))
+	return start, "<!-- readabiligo:content-end -->"
+}
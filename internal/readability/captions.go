@@ -0,0 +1,51 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MinAltCaptionWords is the number of words an alt text needs to be used as the
+// caption of a figure
+const MinAltCaptionWords = 2
+
+// filenameAltRe matches alt texts that are file names or camera image names,
+// such as "beach photo.jpg" or "IMG 2041", rather than descriptions
+var filenameAltRe = regexp.MustCompile(`(?i)(\.(jpe?g|png|gif|webp|avif|svg|bmp|tiff?|heic)$|^(img|dsc|dscn|pxl|photo|image|screenshot)[-_ ]?\d+$)`)
+
+// captionFiguresFromAlt adds a <figcaption> holding the alt text of the image to
+// each figure that has a single image and no caption, so that renderers show
+// the description. Alt texts that are empty, shorter than MinAltCaptionWords
+// words, or look like a file name are not used.
+func captionFiguresFromAlt(articleContent *goquery.Selection) {
+	articleContent.Find("figure").Each(func(i int, figure *goquery.Selection) {
+		if figure.Find("figcaption").Length() > 0 {
+			return
+		}
+		images := figure.Find("img")
+		if images.Length() != 1 {
+			return
+		}
+		alt := getNormalized(images.AttrOr("alt", ""))
+		if !isDescriptiveAlt(alt) {
+			return
+		}
+
+		caption := &html.Node{Type: html.ElementNode, DataAtom: atom.Figcaption, Data: "figcaption"}
+		caption.AppendChild(&html.Node{Type: html.TextNode, Data: alt})
+		figure.Get(0).AppendChild(caption)
+	})
+}
+
+// isDescriptiveAlt reports whether an alt text describes its image well enough
+// to be shown as a caption
+func isDescriptiveAlt(alt string) bool {
+	if len(strings.Fields(alt)) < MinAltCaptionWords {
+		return false
+	}
+	return !filenameAltRe.MatchString(alt)
+}
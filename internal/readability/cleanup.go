@@ -1,12 +1,14 @@
 package readability
 
 import (
-	"fmt"
 	"math"
 	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/mrjoshuak/readabiligo/internal/simplifiers"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // clean removes all nodes of the specified tag from the element
@@ -20,12 +22,7 @@ func (r *Readability) clean(e *goquery.Selection, tag string) {
 	// 2. If none are found, look in the original document for orphaned elements
 	//    and remove them by manipulating the HTML of our article content
 	
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Cleaning tag %s from content\n", tag)
-		fmt.Printf("DEBUG: Current content before cleaning: %s\n", getOuterHTML(e))
-		foundNodes := e.Find(tag)
-		fmt.Printf("DEBUG: Found %d %s elements to clean within article content\n", foundNodes.Length(), tag)
-	}
+	r.logEvent("cleanup", "clean tag", e, "tag", tag, "count", e.Find(tag).Length())
 	
 	// Phase 1: Clean elements already in our article content
 	elementsCleaned := r.cleanElementsInArticle(e, tag)
@@ -38,17 +35,16 @@ func (r *Readability) clean(e *goquery.Selection, tag string) {
 	}
 }
 
+// formControlTags are the form controls removed from the article, except in
+// preserved forms
+var formControlTags = map[string]bool{"input": true, "textarea": true, "select": true, "button": true}
+
 // cleanElementsInArticle removes elements of the specified tag that are within the article content
 func (r *Readability) cleanElementsInArticle(e *goquery.Selection, tag string) int {
 	isEmbed := tag == "object" || tag == "embed" || tag == "iframe"
 	elementsCleaned := 0
 	
 	e.Find(tag).Each(func(i int, node *goquery.Selection) {
-		// For debugging
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Found %s element to clean in article content: %s\n", tag, getOuterHTML(node))
-		}
-		
 		// For footer, aside, and nav elements, check if we should preserve important links
 		if r.options.PreserveImportantLinks && (tag == "footer" || tag == "aside" || tag == "nav") {
 			r.preserveImportantLinksIfNeeded(e, node)
@@ -59,18 +55,16 @@ func (r *Readability) cleanElementsInArticle(e *goquery.Selection, tag string) i
 			return
 		}
 
-		// Remove the node
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Removing %s element\n", tag)
+		// Skip the controls of a preserved form, such as the login form of a minimal page
+		if formControlTags[tag] && node.ParentsFiltered("form.readability-preserve").Length() > 0 {
+			return
 		}
+
+		// Remove the node
+		r.logEvent("cleanup", "remove element", node, "tag", tag)
+		r.recordRemovedLinks(node, tag)
 		node.Remove()
 		elementsCleaned++
-		
-		if r.options.Debug {
-			// Verify removal
-			newCount := e.Find(tag).Length()
-			fmt.Printf("DEBUG: After removal, found %d %s elements remaining\n", newCount, tag)
-		}
 	})
 	
 	return elementsCleaned
@@ -109,9 +103,7 @@ func (r *Readability) preserveImportantLinksIfNeeded(article *goquery.Selection,
 // cleanElementsFromOriginalDocument handles orphaned elements from the original document
 func (r *Readability) cleanElementsFromOriginalDocument(e *goquery.Selection, tag string) {
 	originalElements := r.doc.Find(tag)
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Found %d %s elements in original document\n", originalElements.Length(), tag)
-	}
+	r.logEvent("cleanup", "find elements in original document", nil, "tag", tag, "count", originalElements.Length())
 	
 	// For important links preservation if enabled
 	r.preserveImportantLinksFromOriginal(e, originalElements)
@@ -119,33 +111,24 @@ func (r *Readability) cleanElementsFromOriginalDocument(e *goquery.Selection, ta
 	// Get the outer HTML of the current element
 	articleHTML, err := goquery.OuterHtml(e)
 	if err != nil {
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Error getting article HTML: %v\n", err)
-		}
+		r.logEvent("cleanup", "render article", e, "error", err)
 		return
 	}
 	
 	// Create a completely new document from this HTML
 	tempDoc, err := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
 	if err != nil {
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Error creating temp document: %v\n", err)
-		}
+		r.logEvent("cleanup", "parse article", e, "error", err)
 		return
 	}
 	
 	// Find the tag directly at the document level
 	elementsToRemove := tempDoc.Find(tag)
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Found %d %s elements at document level\n", elementsToRemove.Length(), tag)
-	}
 	
 	// Remove all instances of the tag
 	elementsToRemove.Each(func(i int, element *goquery.Selection) {
-		if r.options.Debug {
-			eleHTML, _ := goquery.OuterHtml(element)
-			fmt.Printf("DEBUG: Removing %s: %s\n", tag, eleHTML)
-		}
+		r.logEvent("cleanup", "remove element", element, "tag", tag)
+		r.recordRemovedLinks(element, tag)
 		element.Remove()
 	})
 	
@@ -170,11 +153,7 @@ func (r *Readability) cleanElementsFromOriginalDocument(e *goquery.Selection, ta
 	}
 	
 	// Verify the cleanup worked
-	if r.options.Debug {
-		remainingElements := e.Find(tag)
-		fmt.Printf("DEBUG: After direct removal, found %d %s elements remaining\n", remainingElements.Length(), tag)
-		fmt.Printf("DEBUG: New article content: %s\n", getOuterHTML(e))
-	}
+	r.logEvent("cleanup", "clean tag", e, "tag", tag, "remaining", e.Find(tag).Length())
 }
 
 
@@ -188,10 +167,7 @@ func (r *Readability) preserveImportantLinksFromOriginal(article *goquery.Select
 	allImportantLinks := r.findAndExtractImportantLinks(elements)
 	if allImportantLinks != nil && allImportantLinks.Children().Length() > 0 {
 		// Since these aren't in our article content, we need to add them
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Adding important links from original document: %s\n", 
-				getOuterHTML(allImportantLinks))
-		}
+		r.logEvent("cleanup", "preserve important links", nil, "count", allImportantLinks.Find("a").Length())
 		article.AppendSelection(allImportantLinks)
 	}
 }
@@ -199,17 +175,11 @@ func (r *Readability) preserveImportantLinksFromOriginal(article *goquery.Select
 // removeElementsFromHTML removes elements from the article HTML
 // Uses direct DOM manipulation instead of creating temporary documents
 func (r *Readability) removeElementsFromHTML(e *goquery.Selection, tag string) {
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Attempting to remove %s elements directly from article root\n", tag)
-	}
-	
 	// Find elements that match the tag
 	elementsToRemove := e.Find(tag)
 	count := elementsToRemove.Length()
 	
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Found %d %s elements at root level\n", count, tag)
-	}
+	r.logEvent("cleanup", "remove elements from article root", e, "tag", tag, "count", count)
 	
 	// If no direct children match, nothing to do
 	if count == 0 {
@@ -218,17 +188,14 @@ func (r *Readability) removeElementsFromHTML(e *goquery.Selection, tag string) {
 	
 	// Remove matching elements directly - more efficient than reconstructing the document
 	elementsToRemove.Each(func(i int, element *goquery.Selection) {
-		if r.options.Debug {
-			eleHTML, _ := goquery.OuterHtml(element)
-			fmt.Printf("DEBUG: Removing %s: %s\n", tag, eleHTML)
-		}
+		r.logEvent("cleanup", "remove element", element, "tag", tag)
 		element.Remove()
 	})
 }
 
 
 // cleanMatchedNodes removes nodes that match a specific pattern
-func (r *Readability) cleanMatchedNodes(e *goquery.Selection, filter func(*goquery.Selection, string) bool) {
+func (r *Readability) cleanMatchedNodes(e *goquery.Selection, reason string, filter func(*goquery.Selection, string) bool) {
 	endOfSearchMarker := getNextNode(e, true)
 	node := getNextNode(e, false)
 
@@ -249,6 +216,7 @@ func (r *Readability) cleanMatchedNodes(e *goquery.Selection, filter func(*goque
 
 		// Apply filter function to determine if node should be removed
 		if filter(node, matchString) {
+			r.recordRemovedLinks(node, reason)
 			node = removeAndGetNext(node)
 		} else {
 			node = getNextNode(node, false)
@@ -270,13 +238,62 @@ func (r *Readability) cleanConditionally(e *goquery.Selection, tag string) {
 
 		// Evaluate if node should be removed
 		if r.shouldRemoveNode(node, tag) {
+			r.recordRemovedLinks(node, conditionalRemovalReason(node))
 			node.Remove()
 		}
 	})
 }
 
+// conditionalRemovalReason describes why conditional cleaning removed a node
+func conditionalRemovalReason(node *goquery.Selection) string {
+	matchString := strings.ToLower(node.AttrOr("class", "") + " " + node.AttrOr("id", ""))
+	switch {
+	case strings.Contains(matchString, "related"):
+		return "related"
+	case getLinkDensity(node) > ConditionalLinkDensityThresholdLow:
+		return "link-density"
+	default:
+		return "low-content"
+	}
+}
+
+// recordRemovedLinks records the links inside a node that is about to be
+// removed from the article, when removed links are tracked. Each link is
+// recorded once, with the reason its outermost removed element was removed.
+func (r *Readability) recordRemovedLinks(node *goquery.Selection, reason string) {
+	if !r.options.TrackRemovedLinks {
+		return
+	}
+	if r.removedLinkNodes == nil {
+		r.removedLinkNodes = make(map[*html.Node]bool)
+	}
+	node.Find("a[href]").AddSelection(node.Filter("a[href]")).Each(func(i int, a *goquery.Selection) {
+		if r.removedLinkNodes[a.Get(0)] {
+			return
+		}
+		r.removedLinkNodes[a.Get(0)] = true
+		r.removedLinks = append(r.removedLinks, RemovedLink{
+			Href:   a.AttrOr("href", ""),
+			Text:   getNormalized(a.Text()),
+			Reason: reason,
+		})
+	})
+}
+
+// resetRemovedLinks forgets the links removed by an extraction attempt that
+// was discarded
+func (r *Readability) resetRemovedLinks() {
+	r.removedLinks = nil
+	r.removedLinkNodes = nil
+}
+
 // shouldSkipConditionalCleaning determines if a node should be exempt from conditional cleaning
 func (r *Readability) shouldSkipConditionalCleaning(node *goquery.Selection, tag string) bool {
+	// Skip preserved elements and the elements holding them
+	if node.HasClass("readability-preserve") || node.Find(".readability-preserve").Length() > 0 {
+		return true
+	}
+
 	// Skip data tables completely
 	if tag == "table" && node.AttrOr("data-readability-table-type", "") == "data" {
 		return true
@@ -341,6 +358,17 @@ func (r *Readability) shouldPreserveStructure(node *goquery.Selection, tag strin
 		}
 	}
 	
+	// Preserve all lists, and sections made of headings and lists (such as
+	// "See also"), when structure is favoured
+	if r.options.KeepStructure {
+		if (tag == "ul" || tag == "ol") && node.Find("li").Length() > 0 {
+			return true
+		}
+		if node.Find("h2, h3, h4, h5, h6").Length() > 0 && node.Find("ul, ol").Length() > 0 {
+			return true
+		}
+	}
+
 	// Preserve content-rich elements
 	if len(getInnerText(node, true)) > MinParagraphLength*2 {
 		return true
@@ -382,10 +410,40 @@ func (r *Readability) shouldRemoveNode(node *goquery.Selection, tag string) bool
 			return false // Keep image galleries
 		}
 	}
+
+	// Keep galleries of captioned images (if enabled)
+	if shouldRemove && r.options.PreserveGalleries && isGallery(node, metrics) {
+		r.logEvent("cleanup", "keep gallery", node, "images", metrics.imgCount)
+		return false
+	}
 	
 	return shouldRemove
 }
 
+// galleryCaptionSelector matches the captions of gallery images
+const galleryCaptionSelector = "figcaption, .wp-caption-text, .gallery-caption"
+
+// isGallery reports whether a node is a gallery of captioned images, as in a
+// photo essay: it holds MinGalleryImages images, at least half as many captions,
+// less prose than caption text, and few links, and isn't a related content box
+func isGallery(node *goquery.Selection, metrics NodeMetrics) bool {
+	if metrics.imgCount < MinGalleryImages || conditionalRemovalReason(node) != "low-content" {
+		return false
+	}
+
+	captions, captionLength := 0, 0
+	node.Find(galleryCaptionSelector).Each(func(i int, caption *goquery.Selection) {
+		if length := len(getNormalized(caption.Text())); length > 0 {
+			captions++
+			captionLength += length
+		}
+	})
+	if captions*2 < metrics.imgCount {
+		return false
+	}
+	return len(getNormalized(node.Text()))-captionLength <= captionLength
+}
+
 // NodeMetrics holds metrics used to evaluate if a node should be kept or removed
 type NodeMetrics struct {
 	paragraphCount   int
@@ -470,7 +528,7 @@ func (r *Readability) hasNonLinkListContent(node *goquery.Selection) bool {
 		linkDensity := float64(totalLinks)/float64(totalText)
 		
 		// Accept lists with reasonable link density
-		if linkDensity < ListLinkDensityThreshold {
+		if linkDensity < ListLinkDensityThreshold+r.options.LinkDensityModifier {
 			return true
 		}
 		
@@ -516,13 +574,13 @@ func (r *Readability) evaluateRemovalCriteria(node *goquery.Selection, tag strin
 	
 	// Low weight with high link density - but exempt lists from this check
 	if !isList && weight < ConditionalWeightThresholdLow && 
-	   metrics.linkDensity > ConditionalLinkDensityThresholdLow {
+	   metrics.linkDensity > ConditionalLinkDensityThresholdLow+r.options.LinkDensityModifier {
 		return true
 	}
 	
 	// High weight with very high link density
 	if weight >= ConditionalWeightThresholdLow && 
-	   metrics.linkDensity > ConditionalLinkDensityThresholdHigh &&
+	   metrics.linkDensity > ConditionalLinkDensityThresholdHigh+r.options.LinkDensityModifier &&
 	   // Be more forgiving with lists, especially those with many items
 	   !(isList && metrics.liCount > 4) {
 		return true
@@ -639,7 +697,14 @@ func (r *Readability) processDuplicateHeaders(e *goquery.Selection, seenHeadings
 	})
 }
 
-// headerDuplicatesTitle checks if this node is an H1 or H2 whose content is mostly the same as the article title
+// headerDuplicatesTitle checks if this node is an H1 or H2 whose content is mostly the same as the article title.
+//
+// A heading duplicates the title when the two match case-insensitively, or when
+// their words are more than TitleSimilarityThreshold similar. The exception is a
+// heading whose words start with all of the title's words and go on, such as a
+// heading that appends a subtitle the <title> lacks: that heading is the richer
+// of the two, so it is not a duplicate and stays in the content, while the
+// article title keeps the cleaner page title.
 func (r *Readability) headerDuplicatesTitle(node *goquery.Selection) bool {
 	if getNodeName(node) != "H1" && getNodeName(node) != "H2" {
 		return false
@@ -657,6 +722,11 @@ func (r *Readability) headerDuplicatesTitle(node *goquery.Selection) bool {
 		return true
 	}
 
+	// A heading that extends the title is richer than it, not a duplicate
+	if extendsTitle(headingTrimmed, titleTrimmed) {
+		return false
+	}
+
 	// Check for similarity if the strings are not identical
 	if headingTrimmed != titleTrimmed {
 		// If not an exact match, check for similarity
@@ -667,19 +737,33 @@ func (r *Readability) headerDuplicatesTitle(node *goquery.Selection) bool {
 	return false
 }
 
+// extendsTitle reports whether the words of heading start with all of the words
+// of title and go on, ignoring case and punctuation
+func extendsTitle(heading, title string) bool {
+	headingWords := tokenize(heading)
+	titleWords := tokenize(title)
+	if len(titleWords) == 0 || len(headingWords) <= len(titleWords) {
+		return false
+	}
+	for i, word := range titleWords {
+		if headingWords[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
 // finalCleanupFooters handles the final cleanup of footer elements from the article content
 // This is needed because in some cases, the clean function in prepArticle might not 
 // have removed footer elements, especially if grabArticle returned the body element
 func (r *Readability) finalCleanupFooters(article *goquery.Selection) {
-	if article.Get(0) == nil {
+	if article == nil || article.Length() == 0 {
 		return
 	}
 	
 	// Find all footers in the article
 	footers := article.Find("footer, .footer")
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Found %d footer elements in final article content\n", footers.Length())
-	}
+	r.logEvent("cleanup", "find footers", article, "count", footers.Length())
 	
 	// Handle footers based on options and presence
 	if footers.Length() > 0 {
@@ -706,9 +790,8 @@ func (r *Readability) finalCleanupFooters(article *goquery.Selection) {
 					}
 				})
 				
-				// Debug logging
-				if r.options.Debug && importantLinksFound {
-					fmt.Printf("DEBUG: Found important links in footer element #%d\n", i)
+				if importantLinksFound {
+					r.logEvent("cleanup", "find important links", footer)
 				}
 			})
 			
@@ -727,9 +810,7 @@ func (r *Readability) finalCleanupFooters(article *goquery.Selection) {
 				linkContainer.AppendSelection(allImportantLinks)
 				article.AppendSelection(linkContainer)
 				
-				if r.options.Debug {
-					fmt.Printf("DEBUG: Added important links section to article\n")
-				}
+				r.logEvent("cleanup", "add important links section", article)
 			}
 			
 			// Now remove all footer elements regardless of whether they had important links
@@ -746,22 +827,104 @@ func (r *Readability) finalCleanupFooters(article *goquery.Selection) {
 // Keeping as a stub for backward compatibility
 func (r *Readability) cleanupFootersWithLinksPreservation(article *goquery.Selection, footers *goquery.Selection) {
 	// This function is now a no-op - all functionality is in finalCleanupFooters
-	if r.options.Debug {
-		fmt.Printf("DEBUG: cleanupFootersWithLinksPreservation is deprecated, using finalCleanupFooters\n")
-	}
+	r.logEvent("cleanup", "skip deprecated footer cleanup", article)
 }
 
 // removeAllFooters removes all footer elements from the article
 func (r *Readability) removeAllFooters(article *goquery.Selection, footers *goquery.Selection) {
 	// Not in preservation mode, remove all footers
 	footers.Each(func(i int, footer *goquery.Selection) {
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Removing footer in final cleanup (preservation disabled): %s\n", getOuterHTML(footer))
-		}
+		r.logEvent("cleanup", "remove footer", footer)
+		r.recordRemovedLinks(footer, "footer")
 		footer.Remove()
 	})
 }
 
+// flattenNestedAnchors unwraps links nested inside other links, which HTML does
+// not allow and browsers render unpredictably. Cleanup can produce them when
+// preserved important links are moved into content that already had anchors.
+// The outermost link is kept and the text and markup of the links inside it
+// are kept as part of its text.
+func flattenNestedAnchors(article *goquery.Selection) {
+	article.Find("a a").Each(func(i int, inner *goquery.Selection) {
+		if contents := inner.Contents(); contents.Length() > 0 {
+			contents.Unwrap()
+		} else {
+			inner.Remove()
+		}
+	})
+}
+
+// MinLeadImageSize is the width and height below which an image, such as an
+// icon or a tracking pixel, is not taken as the lead image
+const MinLeadImageSize = 100
+
+// limitImages keeps at most MaxImages images in the article, in document order,
+// and returns the number of images the article had. The lead image, the one
+// matching the page's og:image or else the first one not known to be smaller
+// than MinLeadImageSize, is always kept. Removed
+// images take their <picture> with them, and a <figure> left without an image
+// is removed along with its caption.
+func (r *Readability) limitImages(article *goquery.Selection, leadImageURL string) int {
+	images := article.Find("img")
+	total := images.Length()
+	if r.options.MaxImages <= 0 || total <= r.options.MaxImages {
+		return total
+	}
+
+	lead := -1
+	if leadImageURL != "" {
+		leadImageURL = r.resolveDocumentURL(leadImageURL)
+		images.EachWithBreak(func(i int, img *goquery.Selection) bool {
+			if r.resolveDocumentURL(img.AttrOr("src", "")) == leadImageURL {
+				lead = i
+				return false
+			}
+			return true
+		})
+	}
+	if lead < 0 {
+		lead = 0
+		images.EachWithBreak(func(i int, img *goquery.Selection) bool {
+			if !r.isSmallImage(img) {
+				lead = i
+				return false
+			}
+			return true
+		})
+	}
+
+	// Keep the lead image and the first images up to the limit
+	keep := map[int]bool{lead: true}
+	for i := 0; i < total && len(keep) < r.options.MaxImages; i++ {
+		keep[i] = true
+	}
+
+	images.Each(func(i int, img *goquery.Selection) {
+		if keep[i] {
+			return
+		}
+		figure := img.Closest("figure")
+		if picture := img.Closest("picture"); picture.Length() > 0 {
+			picture.Remove()
+		} else {
+			img.Remove()
+		}
+		if figure.Length() > 0 && figure.Find("img").Length() == 0 {
+			figure.Remove()
+		}
+	})
+	return total
+}
+
+// isSmallImage reports whether an image is known to be smaller than
+// MinLeadImageSize in either dimension, from its width and height attributes
+// or, with ImageDimensionInference set, from its URL
+func (r *Readability) isSmallImage(img *goquery.Selection) bool {
+	width, height := simplifiers.ImageDimensions(img, r.options.ImageDimensionInference)
+	return (width > 0 && width < MinLeadImageSize) || (height > 0 && height < MinLeadImageSize)
+}
+
 // findAndExtractImportantLinks extracts important links from the given node
 // and returns a container with those links.
 // This is a helper function that consolidates the link extraction logic.
@@ -880,15 +1043,13 @@ func (r *Readability) preserveImportantLinksAnywhere(article *goquery.Selection)
 		// Append to the article
 		article.AppendSelection(linkContainer)
 		
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Added important links section from article content\n")
-		}
+		r.logEvent("cleanup", "add important links section", article)
 	}
 }
 
 // isImportantLink checks if a link has text matching patterns we consider important
 func (r *Readability) isImportantLink(link *goquery.Selection) bool {
-	linkText := getInnerText(link, true)
+	linkText := getNormalized(link.Text())
 	linkTextLower := strings.ToLower(linkText)
 	
 	// List of important link patterns
@@ -938,8 +1099,8 @@ func (r *Readability) cleanStyles(e *goquery.Selection) {
 		return
 	}
 
-	// Skip SVG elements
-	if getNodeName(e) == "SVG" {
+	// Skip SVG elements, and MathML when math is preserved
+	if getNodeName(e) == "SVG" || (r.options.PreserveMath && getNodeName(e) == "MATH") {
 		return
 	}
 
@@ -960,6 +1121,103 @@ func (r *Readability) cleanStyles(e *goquery.Selection) {
 	})
 }
 
+// semanticStyleTags are the elements that already carry the meaning of a
+// semantic inline style, keyed by the tag that style converts to
+var semanticStyleTags = map[string][]string{
+	"strong": {"strong", "b"},
+	"em":     {"em", "i"},
+	"del":    {"del", "s", "strike"},
+	"ins":    {"ins", "u"},
+}
+
+// convertSemanticStyles turns the inline styles in the body that carry meaning
+// into elements, so that they survive when styles are stripped
+func (r *Readability) convertSemanticStyles() {
+	r.doc.Find("body [style]").Each(func(i int, e *goquery.Selection) {
+		convertSemanticStyle(e)
+	})
+}
+
+// convertSemanticStyle turns the inline style of e into elements where it
+// carries meaning: bold text becomes <strong>, italic text <em>, line-through
+// text <del> and underlined text <ins>. A <span> or <font> is renamed to the
+// first of these elements; other elements keep their tag and have their content
+// wrapped instead.
+func convertSemanticStyle(e *goquery.Selection) {
+	node := e.Get(0)
+	if node == nil || node.FirstChild == nil {
+		return
+	}
+	style := strings.ToLower(strings.Join(strings.Fields(e.AttrOr("style", "")), ""))
+	if style == "" {
+		return
+	}
+
+	var tags []string
+	for _, tag := range semanticStyleTagsFor(style) {
+		if !contains(semanticStyleTags[tag], node.Data) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return
+	}
+
+	if node.Data == "span" || node.Data == "font" {
+		node.Data = tags[0]
+		node.DataAtom = atom.Lookup([]byte(tags[0]))
+		tags = tags[1:]
+	}
+	if len(tags) > 0 {
+		opening, closing := "", ""
+		for _, tag := range tags {
+			opening += "<" + tag + ">"
+			closing = "</" + tag + ">" + closing
+		}
+		e.WrapInnerHtml(opening + closing)
+	}
+}
+
+// semanticStyleTagsFor returns the elements that stand for the meaningful
+// declarations of a normalized (lowercase, whitespace-free) inline style
+func semanticStyleTagsFor(style string) []string {
+	var tags []string
+	weight := ""
+	decoration := ""
+	italic := false
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, found := strings.Cut(declaration, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSuffix(value, "!important")
+		switch property {
+		case "font-weight":
+			weight = value
+		case "font-style":
+			italic = value == "italic" || strings.HasPrefix(value, "oblique")
+		case "text-decoration", "text-decoration-line":
+			decoration = value
+		}
+	}
+
+	if weight == "bold" || weight == "bolder" {
+		tags = append(tags, "strong")
+	} else if n, err := strconv.Atoi(weight); err == nil && n >= 600 {
+		tags = append(tags, "strong")
+	}
+	if italic {
+		tags = append(tags, "em")
+	}
+	if strings.Contains(decoration, "line-through") {
+		tags = append(tags, "del")
+	}
+	if strings.Contains(decoration, "underline") {
+		tags = append(tags, "ins")
+	}
+	return tags
+}
+
 // cleanClasses removes class attributes except those in classesToPreserve
 func (r *Readability) cleanClasses(node *goquery.Selection) {
 	if node == nil || node.Length() == 0 {
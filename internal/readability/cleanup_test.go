@@ -0,0 +1,211 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestFlattenNestedAnchors(t *testing.T) {
+	tests := []struct {
+		name string
+		nest func(doc *goquery.Document)
+		want string
+	}{
+		{
+			name: "link moved into a link",
+			nest: func(doc *goquery.Document) {
+				doc.Find("#outer").AppendSelection(doc.Find("#inner"))
+			},
+			want: `<p><a id="outer" href="/story">Read the <b>story</b> and more</a></p><p>After</p>`,
+		},
+		{
+			name: "empty link moved into a link",
+			nest: func(doc *goquery.Document) {
+				doc.Find("#inner").Empty()
+				doc.Find("#outer").AppendSelection(doc.Find("#inner"))
+			},
+			want: `<p><a id="outer" href="/story">Read the <b>story</b></a></p><p>After</p>`,
+		},
+		{
+			name: "links three deep",
+			nest: func(doc *goquery.Document) {
+				doc.Find("#inner").AppendHtml(`<b>!</b>`)
+				doc.Find("#inner b").WrapHtml(`<a href="/deep"></a>`)
+				doc.Find("#outer").AppendSelection(doc.Find("#inner"))
+			},
+			want: `<p><a id="outer" href="/story">Read the <b>story</b> and more<b>!</b></a></p><p>After</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(
+				`<body><p><a id="outer" href="/story">Read the <b>story</b></a></p><p>After<a id="inner" href="/more"> and more</a></p></body>`))
+			if err != nil {
+				t.Fatalf("failed to parse document: %v", err)
+			}
+			tt.nest(doc)
+			body := doc.Find("body")
+			flattenNestedAnchors(body)
+
+			if got, _ := body.Html(); got != tt.want {
+				t.Errorf("flattenNestedAnchors() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if body.Find("a a").Length() > 0 {
+				t.Errorf("nested anchors remain")
+			}
+		})
+	}
+}
+
+func TestHeaderDuplicatesTitle(t *testing.T) {
+	const title = "Growing tomatoes on a small city balcony"
+	tests := []struct {
+		name    string
+		heading string
+		want    bool
+	}{
+		{"same text", `<h1><span>Growing Tomatoes on a Small City Balcony</span></h1>`, true},
+		{"title extended by a subtitle", `<h1><span>Growing tomatoes on a small city balcony: year two</span></h1>`, false},
+		{"title extended by one word", `<h2><span>Growing tomatoes on a small city balcony, revisited</span></h2>`, false},
+		{"heading covered by the title", `<h1><span>Growing tomatoes on a small city</span></h1>`, true},
+		{"similar words in another order", `<h1><span>On a small city balcony: growing tomatoes</span></h1>`, true},
+		{"different heading", `<h1><span>Choosing a pot</span></h1>`, false},
+		{"not a title heading", `<h3><span>Growing tomatoes on a small city balcony</span></h3>`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<body>` + tt.heading + `</body>`))
+			if err != nil {
+				t.Fatalf("failed to parse document: %v", err)
+			}
+			r := &Readability{articleTitle: title}
+
+			if got := r.headerDuplicatesTitle(doc.Find("body").Children().First()); got != tt.want {
+				t.Errorf("headerDuplicatesTitle(%s) = %v, want %v", tt.heading, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectLeadParagraphs(t *testing.T) {
+	const page = `<body><article>` +
+		`<div class="standfirst"><p><span>The council </span><a href="/plans"><span>approved the plans for a new library</span></a><span> on Tuesday.</span></p></div>` +
+		`<div class="body"><p><span>The council voted on Tuesday night to approve the plan for the new library, after months of debate over its cost and location in the town centre.</span></p></div>` +
+		`</article></body>`
+
+	tests := []struct {
+		name    string
+		protect int
+		want    bool
+	}{
+		{"unprotected lead is cleaned", 0, false},
+		{"protected lead is kept", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+			if err != nil {
+				t.Fatalf("failed to parse document: %v", err)
+			}
+			opts := defaultReadabilityOptions()
+			opts.ProtectLeadParagraphs = tt.protect
+			r := NewFromDocument(doc, &opts)
+			article := doc.Find("article")
+			r.prepArticle(article)
+
+			if got := strings.Contains(article.Text(), "approved the plans for a new library"); got != tt.want {
+				t.Errorf("lead kept = %v, want %v", got, tt.want)
+			}
+			if article.Find(".body p").Length() != 1 {
+				t.Errorf("body paragraph was removed")
+			}
+		})
+	}
+}
+
+func TestMinimalPageKeepsForm(t *testing.T) {
+	const page = `<body><div class="login-container">` +
+		`<h1>Sign in to your account</h1>` +
+		`<p><span>Enter your email address and password to continue to your dashboard.</span></p>` +
+		`<form action="/login" method="post"><label for="email">Email</label><input id="email" name="email" type="email">` +
+		`<label for="password">Password</label><input id="password" name="password" type="password">` +
+		`<button type="submit">Sign in</button></form>` +
+		`</div></body>`
+
+	tests := []struct {
+		name        string
+		contentType ContentType
+		want        bool
+	}{
+		{"article drops form controls", ContentTypeArticle, false},
+		{"minimal page keeps its form", ContentTypeMinimal, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+			if err != nil {
+				t.Fatalf("failed to parse document: %v", err)
+			}
+			opts := defaultReadabilityOptions()
+			r := NewFromDocument(doc, &opts)
+			r.contentType = tt.contentType
+			article := doc.Find("body")
+			r.prepArticle(article)
+
+			got := article.Find(`form input[type="password"]`).Length() == 1 && article.Find("form button").Length() == 1
+			if got != tt.want {
+				t.Errorf("form kept = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreserveGalleries(t *testing.T) {
+	var figures strings.Builder
+	for i := 1; i <= 8; i++ {
+		figures.WriteString(`<figure><img src="https://example.com/photos/harbour-` + string(rune('0'+i)) + `.jpg" width="800" height="600">` +
+			`<figcaption>The quay, photo ` + string(rune('0'+i)) + `</figcaption></figure>`)
+	}
+	tests := []struct {
+		name     string
+		class    string
+		preserve bool
+		want     int
+	}{
+		{"gallery removed by default", "gallery", false, 0},
+		{"gallery preserved when enabled", "gallery", true, 8},
+		{"related box removed when enabled", "related-stories", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := `<body><article>` +
+				`<p><span>The harbour has been home to the same fishing families for generations, and the morning catch still sets the rhythm of the town, from the market stalls to the cafes along the quay.</span></p>` +
+				`<div class="` + tt.class + `">` + figures.String() + `</div>` +
+				`<p><span>By mid-morning the boats are tied up again, the nets are drying on the harbour wall and the crews have gone home to sleep before the next tide.</span></p>` +
+				`</article></body>`
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+			if err != nil {
+				t.Fatalf("failed to parse document: %v", err)
+			}
+			opts := defaultReadabilityOptions()
+			opts.PreserveGalleries = tt.preserve
+			r := NewFromDocument(doc, &opts)
+			article := doc.Find("article")
+			r.prepArticle(article)
+
+			if got := article.Find("img").Length(); got != tt.want {
+				t.Errorf("images kept = %d, want %d", got, tt.want)
+			}
+			if got := article.Find("figcaption").Length(); got != tt.want {
+				t.Errorf("captions kept = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package readability
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// columnStyleRe matches the CSS properties that place an element in a
+	// column: grid-column, grid-column-start and the flex order
+	columnStyleRe = regexp.MustCompile(`(?i)(?:^|;)\s*(?:grid-column(?:-start)?|order)\s*:\s*(-?\d+)`)
+
+	// columnClassRe matches the class names that name a column, such as
+	// "column-2", "col-left" or "right-column"
+	columnClassRe = regexp.MustCompile(`(?i)^(?:(?:col|column)[-_]?(left|right|first|second|third|\d)|(left|right|first|second|third)[-_]?(?:col|column))$`)
+)
+
+// columnWords gives the position of the column named in a class
+var columnWords = map[string]int{"left": 1, "first": 1, "right": 2, "second": 2, "third": 3}
+
+// reorderColumns puts multi-column layouts into reading order, column by
+// column, where the DOM order differs from it. Two layouts are recognized: rows
+// that each hold one piece of every column, as when a layout is split into
+// bands, are regrouped into one element per column; and sibling columns placed
+// with the CSS order or grid-column properties are sorted by position. Both
+// need every column to be marked, by style, a data-column attribute or a class
+// such as "column-2" or "col-right", so that ordinary grids are left alone.
+func (r *Readability) reorderColumns() {
+	r.doc.Find("body *").Each(func(i int, s *goquery.Selection) {
+		container := s.Get(0)
+		if regroupColumnRows(container) {
+			r.logEvent("prepare", "regroup column rows", s)
+			return
+		}
+		if sortColumns(container) {
+			r.logEvent("prepare", "sort columns", s)
+		}
+	})
+}
+
+// regroupColumnRows replaces rows of marked columns with one element per
+// column holding the pieces of that column in row order. It reports whether
+// the container was such a layout.
+func regroupColumnRows(container *html.Node) bool {
+	rows := elementChildren(container)
+	if len(rows) < 2 {
+		return false
+	}
+
+	var order []int
+	for i, row := range rows {
+		positions, ok := columnPositions(elementChildren(row))
+		if !ok {
+			return false
+		}
+		if i == 0 {
+			order = positions
+			continue
+		}
+		if !sameColumns(order, positions) {
+			return false
+		}
+	}
+
+	columns := make(map[int]*html.Node)
+	for _, position := range order {
+		columns[position] = &html.Node{Type: html.ElementNode, DataAtom: atom.Div, Data: "div"}
+	}
+	for _, row := range rows {
+		for _, cell := range elementChildren(row) {
+			position, _ := columnPosition(cell)
+			row.RemoveChild(cell)
+			columns[position].AppendChild(cell)
+		}
+		container.RemoveChild(row)
+	}
+
+	sorted := append([]int(nil), order...)
+	sort.Ints(sorted)
+	for _, position := range sorted {
+		container.AppendChild(columns[position])
+	}
+	return true
+}
+
+// sortColumns sorts sibling columns by their marked position, keeping the DOM
+// order of columns with the same position. It reports whether the columns had
+// to be moved.
+func sortColumns(container *html.Node) bool {
+	cells := elementChildren(container)
+	positions, ok := columnPositions(cells)
+	if !ok || sort.IntsAreSorted(positions) {
+		return false
+	}
+
+	indexes := make([]int, len(cells))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return positions[indexes[a]] < positions[indexes[b]]
+	})
+	for _, i := range indexes {
+		container.RemoveChild(cells[i])
+		container.AppendChild(cells[i])
+	}
+	return true
+}
+
+// columnPositions returns the marked column positions of at least two cells,
+// or false when one isn't marked or they all share a position
+func columnPositions(cells []*html.Node) ([]int, bool) {
+	if len(cells) < 2 {
+		return nil, false
+	}
+	positions := make([]int, len(cells))
+	distinct := false
+	for i, cell := range cells {
+		position, ok := columnPosition(cell)
+		if !ok {
+			return nil, false
+		}
+		positions[i] = position
+		distinct = distinct || position != positions[0]
+	}
+	return positions, distinct
+}
+
+// columnPosition returns the column an element is marked to be in, from its
+// style, data-column attribute or class
+func columnPosition(n *html.Node) (int, bool) {
+	var class string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "style":
+			if match := columnStyleRe.FindStringSubmatch(attr.Val); match != nil {
+				position, _ := strconv.Atoi(match[1])
+				return position, true
+			}
+		case "data-column":
+			if position, err := strconv.Atoi(strings.TrimSpace(attr.Val)); err == nil {
+				return position, true
+			}
+		case "class":
+			class = attr.Val
+		}
+	}
+	for _, name := range strings.Fields(class) {
+		match := columnClassRe.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		word := strings.ToLower(match[1] + match[2])
+		if position, ok := columnWords[word]; ok {
+			return position, true
+		}
+		position, _ := strconv.Atoi(word)
+		return position, true
+	}
+	return 0, false
+}
+
+// sameColumns reports whether two rows have the same columns in any order
+func sameColumns(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[int]int)
+	for _, position := range a {
+		count[position]++
+	}
+	for _, position := range b {
+		if count[position] == 0 {
+			return false
+		}
+		count[position]--
+	}
+	return true
+}
@@ -13,6 +13,17 @@ const (
 	FlagCleanConditionally  = 0x4
 )
 
+// RetryFlags are the flags cleared in turn, each retry keeping the earlier ones
+// cleared, when the article found is shorter than the character threshold
+var RetryFlags = []int{FlagStripUnlikelys, FlagWeightClasses, FlagCleanConditionally}
+
+// flagNames names the flags in log events
+var flagNames = map[int]string{
+	FlagStripUnlikelys:     "StripUnlikelys",
+	FlagWeightClasses:      "WeightClasses",
+	FlagCleanConditionally: "CleanConditionally",
+}
+
 // Node types from the HTML package
 const (
 	ElementNode = 1
@@ -95,6 +106,9 @@ const (
 	// HeadingDensityThreshold is the maximum ratio of heading text to total text
 	HeadingDensityThreshold = 0.9
 
+	// MinGalleryImages is the minimum number of images for a node to be kept as a gallery
+	MinGalleryImages = 4
+
 	// DataTableMinRows is the minimum number of rows for a table to be considered a data table
 	DataTableMinRows = 3
 
@@ -125,6 +139,9 @@ const (
 	// Class weight adjustments
 	ClassWeightNegative = -25
 	ClassWeightPositive = 25
+
+	// Bonus for the well-known content containers of CMS themes
+	CMSContentClassWeight = 25
 )
 
 // Ancestor scoring constants
@@ -151,6 +168,28 @@ var ClassesToPreserve = []string{"page"}
 // UnlikelyRoles defines ARIA roles that suggest a node is not content
 var UnlikelyRoles = []string{"menu", "menubar", "complementary", "navigation", "alert", "alertdialog", "dialog"}
 
+// KnownRoles defines the WAI-ARIA 1.2 roles, against which the roles passed to
+// UnlikelyRoles and AllowedRoles options are checked
+var KnownRoles = map[string]bool{
+	"alert": true, "alertdialog": true, "application": true, "article": true, "banner": true,
+	"blockquote": true, "button": true, "caption": true, "cell": true, "checkbox": true,
+	"code": true, "columnheader": true, "combobox": true, "comment": true, "complementary": true,
+	"contentinfo": true, "definition": true, "deletion": true, "dialog": true, "directory": true,
+	"document": true, "emphasis": true, "feed": true, "figure": true, "form": true,
+	"generic": true, "grid": true, "gridcell": true, "group": true, "heading": true,
+	"img": true, "insertion": true, "link": true, "list": true, "listbox": true,
+	"listitem": true, "log": true, "main": true, "mark": true, "marquee": true,
+	"math": true, "menu": true, "menubar": true, "menuitem": true, "menuitemcheckbox": true,
+	"menuitemradio": true, "meter": true, "navigation": true, "none": true, "note": true,
+	"option": true, "paragraph": true, "presentation": true, "progressbar": true, "radio": true,
+	"radiogroup": true, "region": true, "row": true, "rowgroup": true, "rowheader": true,
+	"scrollbar": true, "search": true, "searchbox": true, "separator": true, "slider": true,
+	"spinbutton": true, "status": true, "strong": true, "subscript": true, "superscript": true,
+	"switch": true, "tab": true, "table": true, "tablist": true, "tabpanel": true,
+	"term": true, "textbox": true, "time": true, "timer": true, "toolbar": true,
+	"tooltip": true, "tree": true, "treegrid": true, "treeitem": true,
+}
+
 // DivToPElems defines elements that can appear inside a <div> but should be promoted to paragraphs
 var DivToPElems = []string{"BLOCKQUOTE", "DL", "DIV", "IMG", "OL", "P", "PRE", "TABLE", "UL"}
 
@@ -192,6 +231,35 @@ var (
 	// Positive indicators of content
 	RegexpPositive = regexp.MustCompile(`article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
 
+	// Content container classes of common CMS themes (WordPress, Drupal, Ghost and others)
+	CMSContentClasses = []string{
+		"entry-content", "post-content", "article-body", "article-content", "post-body",
+		"entry-body", "td-post-content", "single-post-content", "gh-content", "post-full-content",
+		"field-name-body", "field--name-body", "node__content", "story-body",
+	}
+
+	// Labels left behind by removed advertisements, matched case-insensitively
+	// against the whole text of a block
+	BoilerplateLabels = []string{
+		"advertisement", "advertisements", "ad", "ads", "advert", "sponsored", "sponsored content",
+		"sponsored post", "promoted", "promoted content", "paid content", "paid post", "partner content",
+		"story continues below advertisement", "article continues below advertisement",
+		"continue reading below", "scroll to continue with content",
+	}
+
+	// URL schemes of the links and images kept in the article (data is only allowed on images)
+	AllowedSchemes = []string{"http", "https", "mailto", "data"}
+
+	// Element ids generated by frameworks and tools rather than written by authors:
+	// hex hashes, UUIDs, React useId values, and the ids of Gatsby, Next.js, Nuxt,
+	// Ember, Radix, Headless UI, MUI and Webflow
+	AutogeneratedIDPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^[0-9a-f]{8,}$`),
+		regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+		regexp.MustCompile(`^:r[0-9a-z]+:$`),
+		regexp.MustCompile(`^(gatsby-|___gatsby|__next|__nuxt|ember\d|radix-|headlessui-|mui-\d|w-node-)`),
+	}
+
 	// Negative indicators of content - adjusted to be consistent with Readability.js
 	RegexpNegative = regexp.MustCompile(`-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|footer|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|widget`)
 
@@ -231,9 +299,15 @@ var (
 	// Hash URL
 	RegexpHashUrl = regexp.MustCompile(`^#.+`)
 
+	// Classes of permalink anchors placed inside headings
+	RegexpHeaderAnchorClass = regexp.MustCompile(`(?i)(^|\s)(headerlink|header-?anchor|heading-?anchor|anchor|anchorjs-link|anchor-link|permalink)(\s|$)`)
+
 	// Srcset URL
 	RegexpSrcsetUrl = regexp.MustCompile(`(\S+)(\s+[\d.]+[xw])?(\s*(?:,|$))`)
 
+	// Scheme of an absolute URL
+	RegexpURLScheme = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.\-]*):`)
+
 	// Base64 data URL
 	RegexpB64DataUrl = regexp.MustCompile(`^data:\s*([^\s;,]+)\s*;\s*base64\s*,`)
 
@@ -0,0 +1,133 @@
+package readability
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ContactInfo holds the email addresses and phone numbers found in the content
+type ContactInfo struct {
+	Emails []string // Lowercase email addresses
+	Phones []string // Phone numbers as digits, with a leading "+" for international numbers
+}
+
+// Lengths of the phone numbers that are recognized
+const (
+	MinPhoneDigits = 7  // Local numbers without an area code
+	MaxPhoneDigits = 15 // Longest number allowed by E.164
+)
+
+var (
+	// contactEmailRe matches an email address in text
+	contactEmailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+
+	// contactPhoneRe matches a phone number in text: digit groups separated by
+	// spaces, dots or dashes, optionally starting with a country code or an
+	// area code in parentheses
+	contactPhoneRe = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{1,5}\)[\s.-]?)?\d{2,5}(?:[\s.-]\d{2,5}){1,4}`)
+
+	// contactDateRe matches dates and year ranges, which look like phone numbers
+	contactDateRe = regexp.MustCompile(`^(?:\d{4}[\s./-]\d{1,2}[\s./-]\d{1,2}|\d{1,2}[\s./-]\d{1,2}[\s./-]\d{2,4}|\d{4}\s?-\s?\d{4})$`)
+
+	// digitGroupRe matches a group of digits
+	digitGroupRe = regexp.MustCompile(`\d+`)
+)
+
+// getContactInfo collects the email addresses of mailto: links, the phone
+// numbers of tel: links and the addresses and numbers written in the text of
+// the content, without duplicates, links first. Numbers in text need a
+// country code, an area code in parentheses, or at least three digit groups,
+// so that figures such as years aren't taken for phone numbers. It returns nil
+// when there are none.
+func getContactInfo(articleContent *goquery.Selection) *ContactInfo {
+	info := &ContactInfo{}
+	seen := make(map[string]bool)
+	add := func(list *[]string, value string) {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			*list = append(*list, value)
+		}
+	}
+
+	articleContent.Find("a[href]").Each(func(i int, link *goquery.Selection) {
+		href := strings.TrimSpace(link.AttrOr("href", ""))
+		target := href[strings.Index(href, ":")+1:]
+		switch getURLScheme(href) {
+		case "mailto":
+			addresses := strings.SplitN(target, "?", 2)[0]
+			if unescaped, err := url.PathUnescape(addresses); err == nil {
+				addresses = unescaped
+			}
+			for _, address := range strings.Split(addresses, ",") {
+				add(&info.Emails, normalizeEmail(address))
+			}
+		case "tel":
+			number := strings.SplitN(target, ";", 2)[0]
+			if unescaped, err := url.PathUnescape(number); err == nil {
+				number = unescaped
+			}
+			add(&info.Phones, normalizePhone(number))
+		}
+	})
+
+	text := articleContent.Text()
+	for _, address := range contactEmailRe.FindAllString(text, -1) {
+		add(&info.Emails, normalizeEmail(address))
+	}
+	for _, number := range contactPhoneRe.FindAllString(text, -1) {
+		if isPhoneText(number) {
+			add(&info.Phones, normalizePhone(number))
+		}
+	}
+
+	if len(info.Emails) == 0 && len(info.Phones) == 0 {
+		return nil
+	}
+	return info
+}
+
+// normalizeEmail returns an email address in lowercase, or "" if it isn't one
+func normalizeEmail(address string) string {
+	address = strings.ToLower(strings.Trim(strings.TrimSpace(address), "."))
+	if contactEmailRe.FindString(address) != address {
+		return ""
+	}
+	return address
+}
+
+// normalizePhone returns the digits of a phone number, keeping a leading "+",
+// so that "(555) 123-4567" and "555.123.4567" are the same number. It returns
+// "" when the number has too few or too many digits.
+func normalizePhone(number string) string {
+	var sb strings.Builder
+	number = strings.TrimSpace(number)
+	if strings.HasPrefix(number, "+") {
+		sb.WriteByte('+')
+	}
+	digits := 0
+	for _, c := range number {
+		if c >= '0' && c <= '9' {
+			sb.WriteRune(c)
+			digits++
+		}
+	}
+	if digits < MinPhoneDigits || digits > MaxPhoneDigits {
+		return ""
+	}
+	return sb.String()
+}
+
+// isPhoneText reports whether a number found in text is likely a phone number
+// rather than a date, a year range or another figure
+func isPhoneText(number string) bool {
+	if contactDateRe.MatchString(number) {
+		return false
+	}
+	if strings.HasPrefix(number, "+") || strings.Contains(number, "(") {
+		return true
+	}
+	return len(digitGroupRe.FindAllString(number, -1)) >= 3
+}
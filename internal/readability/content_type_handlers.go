@@ -24,18 +24,14 @@ func (r *Readability) adjustForContentType() {
 	// Note: We're deliberately not adjusting the threshold or other parameters based on 
 	// content type, to match Mozilla's original algorithm
 	
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Using standard Mozilla algorithm settings (ignoring content type)\n")
-	}
+	r.logEvent("parse", "use standard algorithm settings", nil, "content_type", r.contentType.String())
 }
 
 // applyContentTypeCleanup performs standard content cleanup using Mozilla's algorithm
 // The content-type specific handling has been removed to match the original implementation
 func (r *Readability) applyContentTypeCleanup(article *goquery.Selection) {
 	// Use a standard cleanup approach regardless of content type
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Applying standard Mozilla cleanup (not content-type specific)\n")
-	}
+	r.logEvent("cleanup", "apply standard cleanup", article)
 
 	// Preserve code blocks and technical content structure, as these are important in any content
 	// This is always applied to ensure we properly handle technical content regardless of type
@@ -2,15 +2,15 @@
 // for extracting the main content from web pages.
 package readability
 
-import (
-	"github.com/PuerkitoBio/goquery"
-)
+import "strings"
 
-// ContentType is maintained for API compatibility but no longer affects the extraction algorithm.
-// Mozilla's Readability.js doesn't use content type detection, so we use a unified algorithm.
+// ContentType labels a page. Content types are not detected, since Mozilla's
+// Readability.js uses a unified algorithm: pages are ContentTypeArticle unless a
+// content type is assigned in the options or by a rule, or the page turns out to
+// be an error page. ContentTypePaywall and ContentTypeMinimal change extraction.
 type ContentType int
 
-// Content type constants - kept for API compatibility
+// Content type constants
 const (
 	ContentTypeUnknown ContentType = iota
 	ContentTypeReference  // Wikipedia, documentation
@@ -41,10 +41,22 @@ func (ct ContentType) String() string {
 	}
 }
 
-// DetectContentType returns ContentTypeArticle for all content.
-// This function is maintained for API compatibility but no longer performs detection.
-// Mozilla's Readability.js doesn't classify content by type, so we use a unified algorithm.
-func DetectContentType(doc *goquery.Document) ContentType {
-	// Always return Article to use the standard algorithm for all content
-	return ContentTypeArticle
-}
\ No newline at end of file
+// ContentTypeRule applies ContentType to documents on which Selector matches
+type ContentTypeRule struct {
+	Selector    string      // CSS selector matched against the document before cleanup
+	ContentType ContentType // Content type applied when the selector matches
+}
+
+// applyContentTypeRules sets the content type from the first rule whose selector
+// matches the document. Empty and invalid selectors never match.
+func (r *Readability) applyContentTypeRules() {
+	for _, rule := range r.options.ContentTypeRules {
+		if strings.TrimSpace(rule.Selector) == "" {
+			continue
+		}
+		if r.doc.Find(rule.Selector).Length() > 0 {
+			r.contentType = rule.ContentType
+			return
+		}
+	}
+}
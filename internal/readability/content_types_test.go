@@ -7,245 +7,6 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
-func TestDetectContentType(t *testing.T) {
-	// DEPRECATED: Content type detection has been removed to match Mozilla's unified algorithm.
-	// All content is now treated as ContentTypeArticle regardless of characteristics.
-	// This test is skipped as the functionality it tests no longer exists.
-	// See commit 577bc59: "Remove content type detection to match Mozilla's unified approach"
-	t.Skip("Content type detection has been deprecated in favor of Mozilla's unified algorithm")
-
-	tests := []struct {
-		name     string
-		html     string
-		expected ContentType
-	}{
-		{
-			name: "Error Page Detection",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>404 - Page Not Found</title>
-				</head>
-				<body>
-					<h1>Page Not Found</h1>
-					<p>The page you were looking for does not exist.</p>
-					<a href="/">Go back to homepage</a>
-				</body>
-				</html>
-			`,
-			expected: ContentTypeError,
-		},
-		{
-			name: "Wikipedia Reference Content",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>Article Title - Wikipedia</title>
-				</head>
-				<body>
-					<div id="mw-content-text">
-						<div class="infobox">Info content</div>
-						<div id="toc">Table of Contents</div>
-						<p>Article content with <a class="citation">citation</a>.</p>
-						<div class="references">
-							<ol>
-								<li>Reference 1</li>
-								<li>Reference 2</li>
-							</ol>
-						</div>
-						<span class="mw-editsection">[edit]</span>
-					</div>
-				</body>
-				</html>
-			`,
-			expected: ContentTypeReference,
-		},
-		{
-			name: "Technical Blog Content",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>Technical Blog Post</title>
-				</head>
-				<body>
-					<article>
-						<h1>How to Use Go Modules</h1>
-						<div class="byline">By Author - January 1, 2023</div>
-						<p>This is a technical article about Go modules.</p>
-						<pre>
-							<code>
-								func main() {
-									fmt.Println("Hello, world!")
-								}
-							</code>
-						</pre>
-						<p>More content with code examples.</p>
-						<div class="code">
-								import "fmt"
-								
-								func Example() {
-									// This is a code example
-								}
-						</div>
-					</article>
-				</body>
-				</html>
-			`,
-			expected: ContentTypeTechnical,
-		},
-		{
-			name: "Standard Article",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>News Article</title>
-				</head>
-				<body>
-					<article>
-						<h1>Breaking News</h1>
-						<div class="byline">By Reporter - January 1, 2023</div>
-						<p>This is a news article with content.</p>
-						<p>Second paragraph with more details.</p>
-						<p>Third paragraph with conclusion.</p>
-					</article>
-				</body>
-				</html>
-			`,
-			expected: ContentTypeArticle,
-		},
-		{
-			name: "Minimal Login Page",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>Login</title>
-				</head>
-				<body>
-					<div class="login-container">
-						<h1>Login</h1>
-						<form action="/login" method="post">
-							<input type="text" name="username" placeholder="Username">
-							<input type="password" name="password" placeholder="Password">
-							<button type="submit">Login</button>
-						</form>
-						<a href="/forgot-password">Forgot password?</a>
-					</div>
-				</body>
-				</html>
-			`,
-			expected: ContentTypeMinimal,
-		},
-		{
-			name: "Paywall Content With Subscription Container",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>Premium Article</title>
-				</head>
-				<body>
-					<article>
-						<h1>Premium Content Article</h1>
-						<div class="byline">By Premium Writer - January 1, 2023</div>
-						<p>This is the introduction to a premium article.</p>
-						<p>Here's a bit more content that's visible to everyone.</p>
-						<div class="paywall">
-							<h2>Continue Reading</h2>
-							<p>You've reached your free article limit this month.</p>
-							<a href="/subscribe" class="subscribe-button">Subscribe Now</a>
-						</div>
-						<div class="premium-content">
-							<p>This is the premium content hidden behind the paywall.</p>
-							<p>More detailed analysis and information for subscribers only.</p>
-							<h2>Exclusive Insights</h2>
-							<p>Special content for our paying subscribers.</p>
-						</div>
-					</article>
-				</body>
-				</html>
-			`,
-			expected: ContentTypePaywall,
-		},
-		{
-			name: "Paywall Content With Metered Message",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>Metered Paywall Article</title>
-				</head>
-				<body>
-					<article>
-						<h1>Important News Analysis</h1>
-						<div class="byline">By Staff Reporter - January 1, 2023</div>
-						<p>This is the first paragraph of a metered article.</p>
-						<p>You can read a few more paragraphs before hitting the limit.</p>
-						<div class="article-body">
-							<p>Third paragraph with more context about the story.</p>
-							<div class="metered-message">
-								<p>You have read 3 of your 5 free articles this month.</p>
-								<a href="/subscribe">Subscribe for unlimited access</a>
-							</div>
-						</div>
-					</article>
-				</body>
-				</html>
-			`,
-			expected: ContentTypePaywall,
-		},
-		{
-			name: "Paywall Content With Hidden Premium Elements",
-			html: `
-				<!DOCTYPE html>
-				<html>
-				<head>
-					<title>Hidden Content Article</title>
-				</head>
-				<body>
-					<article>
-						<h1>Investigation Report</h1>
-						<div class="byline">By Investigative Team - January 1, 2023</div>
-						<p>This is the introduction to our investigation.</p>
-						<p>Here are some initial findings that are available to all readers.</p>
-						<p class="fade-out">This paragraph is partially visible but fades out.</p>
-						<div style="display: none;" class="paid-content">
-							<h2>Detailed Analysis</h2>
-							<p>This hidden section contains our detailed analysis.</p>
-							<p>This is only available to subscribers.</p>
-						</div>
-						<div class="subscription-prompt">
-							<p>Sign in to continue reading</p>
-							<a href="/login" class="login-link">Already a subscriber? Log in</a>
-							<a href="/subscribe" class="subscribe-cta">Subscribe now</a>
-						</div>
-					</article>
-				</body>
-				</html>
-			`,
-			expected: ContentTypePaywall,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
-			if err != nil {
-				t.Fatalf("Error creating document: %v", err)
-			}
-
-			contentType := DetectContentType(doc)
-			if contentType != tt.expected {
-				t.Errorf("Expected content type %s, got %s", tt.expected, contentType)
-			}
-		})
-	}
-}
-
 func TestContentTypeAwareExtraction(t *testing.T) {
 	// DEPRECATED: Content type-specific extraction has been removed to match Mozilla's unified algorithm.
 	// All content now uses the same extraction logic regardless of type.
@@ -442,7 +203,6 @@ func TestContentTypeAwareExtraction(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			opts := defaultReadabilityOptions()
-			opts.DetectContentType = tt.detectEnabled
 			if !tt.detectEnabled {
 				opts.ContentType = tt.forceContentType
 			}
@@ -0,0 +1,93 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// lineBreakHyphenRe matches a word broken across lines with a hyphen, as in
+// "exam-\nple", capturing the two halves
+var lineBreakHyphenRe = regexp.MustCompile(`(\p{L}+)-[ \t]*\r?\n\s*(\p{L}+)`)
+
+// Words that commonly form hyphenated compounds, whose hyphen is kept when they
+// start or end a word broken across lines
+var (
+	compoundPrefixes = map[string]bool{
+		"all": true, "anti": true, "best": true, "co": true, "cross": true, "ex": true,
+		"far": true, "first": true, "full": true, "half": true, "high": true, "ill": true,
+		"long": true, "low": true, "mid": true, "multi": true, "non": true, "old": true,
+		"post": true, "pre": true, "self": true, "semi": true, "short": true, "so": true,
+		"top": true, "well": true, "world": true,
+	}
+	compoundSuffixes = map[string]bool{
+		"based": true, "aware": true, "driven": true, "free": true, "friendly": true,
+		"known": true, "level": true, "like": true, "made": true, "oriented": true,
+		"related": true, "scale": true, "specific": true, "style": true, "term": true,
+		"wide": true,
+	}
+)
+
+// dehyphenate joins words that were broken across lines with a hyphen, as in
+// text from OCR or PDF conversion, so "exam-\nple" becomes "example". Only
+// lowercase halves are considered (the first may be capitalized), and the
+// hyphen is kept, with the line break removed, when the result is likely a
+// real compound: when the hyphenated form appears elsewhere in the article,
+// when either half is a common compound word like "well" or "known", or when
+// the first half is a single letter. The joined form appearing elsewhere in the
+// article always wins.
+func dehyphenate(article *goquery.Selection) {
+	text := strings.ToLower(article.Text())
+	if !lineBreakHyphenRe.MatchString(text) {
+		return
+	}
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(text, func(c rune) bool {
+		return !unicode.IsLetter(c) && c != '-'
+	}) {
+		words[word] = true
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			n.Data = lineBreakHyphenRe.ReplaceAllStringFunc(n.Data, func(match string) string {
+				parts := lineBreakHyphenRe.FindStringSubmatch(match)
+				return joinHyphenatedWord(parts[1], parts[2], words, match)
+			})
+			return
+		}
+		// Preformatted text keeps its line breaks as written
+		if n.Type == html.ElementNode && (n.Data == "pre" || n.Data == "code") {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range article.Nodes {
+		walk(n)
+	}
+}
+
+// joinHyphenatedWord returns the replacement of a word broken across lines,
+// given its halves and the words of the article. The match is returned as is
+// when either half has uppercase letters, other than a leading capital.
+func joinHyphenatedWord(first, second string, words map[string]bool, match string) string {
+	_, size := utf8.DecodeRuneInString(first)
+	if strings.IndexFunc(first[size:]+second, unicode.IsUpper) >= 0 {
+		return match
+	}
+	lower := strings.ToLower(first)
+	switch {
+	case words[lower+second]:
+		return first + second
+	case words[lower+"-"+second], compoundPrefixes[lower], compoundSuffixes[second], len([]rune(lower)) == 1:
+		return first + "-" + second
+	}
+	return first + second
+}
@@ -66,6 +66,17 @@ func getClassWeight(s *goquery.Selection) int {
 	return weight
 }
 
+// getCMSClassWeight returns CMSContentClassWeight if the element has one of the
+// given CMS content container classes, and 0 otherwise
+func getCMSClassWeight(s *goquery.Selection, classes []string) int {
+	for _, class := range classes {
+		if s.HasClass(class) {
+			return CMSContentClassWeight
+		}
+	}
+	return 0
+}
+
 // isSameNode checks if two nodes are the same
 // This is a pointer comparison, so it only returns true if both arguments
 // reference exactly the same node in memory
@@ -296,4 +307,15 @@ func isSingleImage(s *goquery.Selection) bool {
 
 	// Recursively check the single child
 	return isSingleImage(s.Children())
-}
\ No newline at end of file
+}
+
+// elementChildren returns the element children of a node
+func elementChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			children = append(children, c)
+		}
+	}
+	return children
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // ErrorType defines the category of an error
@@ -25,8 +26,43 @@ var (
 	ErrDocumentLarge = errors.New("document too large")
 	ErrNoContent     = errors.New("could not extract article content")
 	ErrTimeout       = errors.New("operation timed out")
+	ErrNoTitle       = errors.New("could not resolve a confident title")
 )
 
+// ContentScoreError reports that the top content candidate scored below
+// MinContentScore. It wraps ErrNoContent.
+type ContentScoreError struct {
+	Score    float64 // Adjusted score of the best top candidate found
+	MinScore float64 // Minimum score required
+}
+
+// Error describes the score found and the minimum
+func (e *ContentScoreError) Error() string {
+	return fmt.Sprintf("top candidate score %.1f is below the minimum of %.1f: %v", e.Score, e.MinScore, ErrNoContent)
+}
+
+// Unwrap returns ErrNoContent
+func (e *ContentScoreError) Unwrap() error {
+	return ErrNoContent
+}
+
+// PhaseTimeoutError reports that a phase of the extraction ran over its time
+// budget. It wraps ErrTimeout.
+type PhaseTimeoutError struct {
+	Phase  Phase         // Phase that ran over
+	Budget time.Duration // Time budget of the phase
+}
+
+// Error describes the phase and its budget
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("%s phase exceeded its budget of %v: %v", e.Phase, e.Budget, ErrTimeout)
+}
+
+// Unwrap returns ErrTimeout
+func (e *PhaseTimeoutError) Unwrap() error {
+	return ErrTimeout
+}
+
 // WrapError wraps an error with context information
 func WrapError(err error, errorType ErrorType, funcName, message string) error {
 	if err == nil {
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -25,13 +27,8 @@ func (r *Readability) grabArticle() *goquery.Selection {
 		// included in the article content but contain important links
 		docFooters := r.doc.Find("footer, .footer, aside")
 		
-		// Debug output
-		if r.options.Debug {
-			footersInArticle := articleContent.Find("footer, .footer").Length()
-			footersInDoc := docFooters.Length()
-			fmt.Printf("DEBUG: Found %d footers in article content, %d in original document\n", 
-				footersInArticle, footersInDoc)
-		}
+		r.logEvent("extract", "find footers", articleContent,
+			"in_article", articleContent.Find("footer, .footer").Length(), "in_document", docFooters.Length())
 
 		// Look for important links in footers, asides, etc. that may have been excluded
 		// Note: We only need to do this for elements that aren't already in the article
@@ -50,9 +47,7 @@ func (r *Readability) grabArticle() *goquery.Selection {
 				// If this element has important links, clone and append it to the article
 				// This ensures it will be processed by finalCleanupFooters later
 				if hasImportant {
-					if r.options.Debug {
-						fmt.Printf("DEBUG: Found important links in outer document, preserving element\n")
-					}
+					r.logEvent("extract", "preserve element with important links", elem)
 					elemCopy := elem.Clone()
 					articleContent.AppendSelection(elemCopy)
 				}
@@ -76,10 +71,7 @@ func (r *Readability) grabArticle() *goquery.Selection {
 		
 		// If we found any footers with important links, make sure they're in the article
 		if footersWithImportantLinks.Length() > 0 && articleContent.Find("footer, .footer").Length() == 0 {
-			if r.options.Debug {
-				fmt.Printf("DEBUG: Found %d footers with important links in document\n", 
-					footersWithImportantLinks.Length())
-			}
+			r.logEvent("extract", "find footers with important links", nil, "count", footersWithImportantLinks.Length())
 			
 			// Add a container for the important links that will be preserved
 			importantLinksContainer := r.createElement("footer")
@@ -103,35 +95,27 @@ func (r *Readability) grabArticle() *goquery.Selection {
 		}
 	}
 
-	// Check word count and retry with different flags if needed
+	// Check word count and retry with different flags if needed,
+	// unless the caller asked for the strict first attempt only
 	textLength := len(getInnerText(articleContent, true))
-	if textLength < r.options.CharThreshold {
+	if textLength < r.options.CharThreshold && !r.options.DisableFallback {
 		// Store the page HTML for reuse
 		pageHTML, _ := r.doc.Find("body").Html()
 
-		// Try again with different flags
-		if r.flags&FlagStripUnlikelys != 0 {
-			r.flags &= ^FlagStripUnlikelys
-			r.doc.Find("body").SetHtml(pageHTML)
-			articleContent = r.grabArticleNode()
-			if articleContent != nil {
-				r.prepArticle(articleContent)
-				textLength = len(getInnerText(articleContent, true))
-			}
+		// Try again with different flags, clearing them in the configured order
+		retryFlags := r.options.RetryFlags
+		if retryFlags == nil {
+			retryFlags = RetryFlags
 		}
-
-		if textLength < r.options.CharThreshold && r.flags&FlagWeightClasses != 0 {
-			r.flags &= ^FlagWeightClasses
-			r.doc.Find("body").SetHtml(pageHTML)
-			articleContent = r.grabArticleNode()
-			if articleContent != nil {
-				r.prepArticle(articleContent)
-				textLength = len(getInnerText(articleContent, true))
+		for _, flag := range retryFlags {
+			if textLength >= r.options.CharThreshold {
+				break
 			}
-		}
-
-		if textLength < r.options.CharThreshold && r.flags&FlagCleanConditionally != 0 {
-			r.flags &= ^FlagCleanConditionally
+			if r.flags&flag == 0 {
+				continue
+			}
+			r.flags &= ^flag
+			r.logEvent("extract", "retry with relaxed heuristic", nil, "flag", flagNames[flag])
 			r.doc.Find("body").SetHtml(pageHTML)
 			articleContent = r.grabArticleNode()
 			if articleContent != nil {
@@ -143,6 +127,7 @@ func (r *Readability) grabArticle() *goquery.Selection {
 		// If still too short, use the body or apply special handling
 		if textLength < r.options.CharThreshold {
 			r.doc.Find("body").SetHtml(pageHTML)
+			r.resetRemovedLinks()
 			
 			// Special handling for certain types of pages that might not have
 			// been properly detected during the initial content type detection
@@ -193,13 +178,16 @@ func (r *Readability) grabArticle() *goquery.Selection {
 					articleContent.Find("a[href='/']").AddClass("readability-preserve")
 					// Set content type to Error
 					r.contentType = ContentTypeError
+					r.setContentOrigin(nil, 0, OriginErrorPage)
 				} else {
 					// Fallback
 					articleContent = r.doc.Find("body")
+					r.setContentOrigin(articleContent, 0, OriginFallback)
 				}
 			} else {
 				// Otherwise, set articleContent to the body element
 				articleContent = r.doc.Find("body")
+				r.setContentOrigin(articleContent, 0, OriginFallback)
 			}
 		}
 	}
@@ -215,6 +203,15 @@ func (r *Readability) grabArticleNode() *goquery.Selection {
 	
 	// Get the document body, creating one if needed
 	body := r.initializeDocumentBody()
+
+	// Schema.org microdata marking the article body is a strong content hint
+	if articleBody := r.getMicrodataArticleBody(); articleBody != nil {
+		r.logEvent("extract", "use microdata article body", articleBody)
+		r.setContentOrigin(articleBody, 0, OriginMicrodata)
+		// The marked article body is trusted without scoring
+		r.topScore = math.Inf(1)
+		return articleBody
+	}
 	
 	// Prepare nodes for scoring
 	elementsToScore := r.prepareNodesForScoring(body)
@@ -224,6 +221,7 @@ func (r *Readability) grabArticleNode() *goquery.Selection {
 	
 	// If no candidates found, return article with whole body
 	if len(candidates) == 0 {
+		r.setContentOrigin(r.doc.Find("body"), 0, OriginBody)
 		return r.doc.Find("body")
 	}
 	
@@ -379,13 +377,9 @@ func (r *Readability) prepareNodesForScoring(body *goquery.Selection) []*goquery
 			}
 
 			// Check for unlikely roles (be more lenient with deeply nested content)
-			if role, exists := node.Attr("role"); exists && (!isDeeplyNested || role == "banner" || role == "advertisement") {
-				for _, unlikelyRole := range UnlikelyRoles {
-					if role == unlikelyRole {
-						node = removeAndGetNext(node)
-						continue
-					}
-				}
+			if role, exists := node.Attr("role"); exists && (!isDeeplyNested || role == "banner" || role == "advertisement") && r.isUnlikelyRole(role) {
+				node = removeAndGetNext(node)
+				continue
 			}
 		}
 
@@ -433,6 +427,50 @@ func (r *Readability) prepareNodesForScoring(body *goquery.Selection) []*goquery
 	return elementsToScore
 }
 
+// isUnlikelyRole reports whether a role attribute, which may list fallback
+// roles separated by spaces, names a role of UnlikelyRoles or of the
+// UnlikelyRoles option that isn't exempted by the AllowedRoles option. Roles
+// are compared ignoring case.
+func (r *Readability) isUnlikelyRole(role string) bool {
+	for _, token := range strings.Fields(strings.ToLower(role)) {
+		if containsRole(r.options.AllowedRoles, token) {
+			continue
+		}
+		if contains(UnlikelyRoles, token) || containsRole(r.options.UnlikelyRoles, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRole reports whether roles holds the lowercase role, ignoring the
+// case of the roles
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.ToLower(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownRoleWarnings returns a warning for each role of the UnlikelyRoles and
+// AllowedRoles options that isn't a WAI-ARIA role, which is likely a typo
+func (r *Readability) unknownRoleWarnings() []string {
+	var warnings []string
+	for _, list := range []struct {
+		option string
+		roles  []string
+	}{{"unlikely roles", r.options.UnlikelyRoles}, {"allowed roles", r.options.AllowedRoles}} {
+		for _, role := range list.roles {
+			if !KnownRoles[strings.ToLower(role)] {
+				warnings = append(warnings, fmt.Sprintf("%s: %q is not a known ARIA role", list.option, role))
+			}
+		}
+	}
+	return warnings
+}
+
 // calculateNestingLevels recursively calculates how deeply nested each element is
 // This helps with scoring deeply nested content appropriately
 func (r *Readability) calculateNestingLevels(node *goquery.Selection, nestingLevels map[*goquery.Selection]int, currentLevel int) {
@@ -455,39 +493,93 @@ func isElementCompletelyEmpty(e *goquery.Selection) bool {
 	return strings.TrimSpace(getInnerText(e, false)) == "" && e.Children().Length() == 0
 }
 
-// scoreNodes calculates scores for all candidate nodes
+// elementScore is the score an element gives to its ancestors
+type elementScore struct {
+	ancestors    []*goquery.Selection // Ancestors of the element, nearest first
+	contentScore float64              // Score shared among the ancestors
+}
+
+// scoreNodes calculates scores for all candidate nodes. With ParallelScoring set,
+// the elements are scored concurrently and their scores then given to their
+// ancestors in document order, so the candidates are the same as when scoring
+// serially.
 func (r *Readability) scoreNodes(elementsToScore []*goquery.Selection) []*NodeInfo {
 	candidates := []*NodeInfo{}
-	
-	for _, elem := range elementsToScore {
-		// Skip elements with no parent
-		parent := elem.Parent()
-		if parent.Length() == 0 {
-			continue
-		}
 
-		// Skip elements with less than minimum content length
-		innerText := getInnerText(elem, true)
-		if len(innerText) < MinContentTextLength {
-			continue
+	if r.options.ParallelScoring && len(elementsToScore) > 1 {
+		for _, score := range scoreElementsParallel(elementsToScore) {
+			if score != nil {
+				candidates = r.scoreAncestors(score.ancestors, candidates, score.contentScore)
+			}
 		}
+		return candidates
+	}
 
-		// Get ancestors up to specified level
-		ancestors := getNodeAncestors(elem, AncestorLevelDepth)
-		if len(ancestors) == 0 {
-			continue
+	for _, elem := range elementsToScore {
+		if score := scoreElement(elem); score != nil {
+			candidates = r.scoreAncestors(score.ancestors, candidates, score.contentScore)
 		}
+	}
 
-		// Calculate content score for this element
-		contentScore := BaseContentScore                                // Base score
-		contentScore += float64(getCharCount(elem, ",")) * CommaBonus   // Bonus for commas
-		contentScore += math.Min(float64(len(innerText))/TextLengthDivisor, MaxLengthBonus) // Bonus for text length
+	return candidates
+}
 
-		// Initialize and score ancestors
-		candidates = r.scoreAncestors(ancestors, candidates, contentScore)
+// scoreElementsParallel scores elements on one goroutine per CPU, returning the
+// scores in the order of the elements. Scoring only reads the document.
+func scoreElementsParallel(elements []*goquery.Selection) []*elementScore {
+	scores := make([]*elementScore, len(elements))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(elements) {
+		workers = len(elements)
 	}
-	
-	return candidates
+
+	indexes := make(chan int, len(elements))
+	for i := range elements {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				scores[i] = scoreElement(elements[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return scores
+}
+
+// scoreElement calculates the content score of an element, or returns nil for
+// elements that are too short or have no ancestors to score
+func scoreElement(elem *goquery.Selection) *elementScore {
+	// Skip elements with no parent
+	parent := elem.Parent()
+	if parent.Length() == 0 {
+		return nil
+	}
+
+	// Skip elements with less than minimum content length
+	innerText := getInnerText(elem, true)
+	if len(innerText) < MinContentTextLength {
+		return nil
+	}
+
+	// Get ancestors up to specified level
+	ancestors := getNodeAncestors(elem, AncestorLevelDepth)
+	if len(ancestors) == 0 {
+		return nil
+	}
+
+	// Calculate content score for this element
+	contentScore := BaseContentScore                                // Base score
+	contentScore += float64(getCharCount(elem, ",")) * CommaBonus   // Bonus for commas
+	contentScore += math.Min(float64(len(innerText))/TextLengthDivisor, MaxLengthBonus) // Bonus for text length
+
+	return &elementScore{ancestors: ancestors, contentScore: contentScore}
 }
 
 // scoreAncestors assigns scores to the ancestors of content elements
@@ -555,6 +647,7 @@ func (r *Readability) scoreAncestors(ancestors []*goquery.Selection, candidates
 			// Adjust for class/id weight
 			if r.flags&FlagWeightClasses != 0 {
 				scoreInitial += float64(getClassWeight(ancestor))
+				scoreInitial += float64(getCMSClassWeight(ancestor, r.options.CMSContentClasses))
 			}
 
 			// Add the new node to candidates
@@ -591,6 +684,10 @@ func (r *Readability) buildArticleFromCandidates(candidates []*NodeInfo) *goquer
 			node:         r.doc.Find("body"),
 			contentScore: 0,
 		}
+		r.setContentOrigin(topCandidate.node, 0, OriginBody)
+	} else {
+		r.setContentOrigin(topCandidate.node, topCandidate.contentScore, OriginCandidate)
+		r.topScore = math.Max(r.topScore, topCandidate.contentScore*(1.0-getLinkDensity(topCandidate.node)))
 	}
 
 	// Create a new article element
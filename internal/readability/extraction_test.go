@@ -0,0 +1,176 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestCMSContentClasses(t *testing.T) {
+	paragraph := func(text string) string {
+		return "<p><span>" + text + "</span></p>"
+	}
+	html := `<html><head><title>Growing tomatoes</title></head><body>
+		<div class="layout">
+			<div class="main-column">
+				<div class="entry-content">` +
+		paragraph("Tomatoes need at least six hours of sun, rich soil and steady watering, or the fruit will split and the leaves will curl.") +
+		paragraph("Pinch out the side shoots of cordon varieties every week, and tie the main stem to a cane as it grows, so the plant puts its energy into fruit.") +
+		paragraph("Feed the plants with a high-potash fertiliser once the first truss has set, and keep feeding them every ten days until the end of the season.") + `
+				</div>
+			</div>
+			<div class="page-aside">` +
+		paragraph("Our gardening shop has seeds, compost, canes, fertiliser, trays and tools, with free delivery on orders over thirty pounds, and gift cards for every occasion.") +
+		paragraph("Visit the garden centre in town, open every day from nine until five, with a cafe, a play area, a plant clinic, a farm shop and plenty of free parking.") +
+		paragraph("Sign up for the newsletter to get seasonal planting guides, offers, competitions and news from the nursery, delivered every month to your inbox.") + `
+			</div>
+		</div>
+	</body></html>`
+
+	tests := []struct {
+		name    string
+		classes []string
+		want    string
+	}{
+		{name: "default classes", classes: CMSContentClasses, want: "entry-content"},
+		{name: "disabled", classes: nil, want: "page-aside"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := defaultReadabilityOptions()
+			opts.CMSContentClasses = tt.classes
+			r, err := NewFromHTML(html, &opts)
+			if err != nil {
+				t.Fatalf("NewFromHTML() error = %v", err)
+			}
+			r.prepDocument()
+
+			var top *NodeInfo
+			for _, candidate := range r.scoreNodes(r.prepareNodesForScoring(r.initializeDocumentBody())) {
+				if top == nil || candidate.contentScore > top.contentScore {
+					top = candidate
+				}
+			}
+			if top == nil {
+				t.Fatal("no candidates were scored")
+			}
+			if class := strings.TrimSpace(top.node.AttrOr("class", "")); class != tt.want {
+				t.Errorf("top candidate has class %q, want %q", class, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryFlags(t *testing.T) {
+	html := `<html><head><title>Short note</title></head><body>
+		<article><p><span>The bakery is closed on Monday for a family event and will open again on Tuesday morning.</span></p></article>
+	</body></html>`
+	allFlags := FlagStripUnlikelys | FlagWeightClasses | FlagCleanConditionally
+
+	tests := []struct {
+		name       string
+		retryFlags []int
+		want       int
+	}{
+		{name: "default ladder", retryFlags: RetryFlags, want: 0},
+		{name: "clean conditionally only", retryFlags: []int{FlagCleanConditionally}, want: FlagStripUnlikelys | FlagWeightClasses},
+		{name: "custom order subset", retryFlags: []int{FlagWeightClasses, FlagStripUnlikelys}, want: FlagCleanConditionally},
+		{name: "nil uses the default ladder", retryFlags: nil, want: 0},
+		{name: "disabled", retryFlags: []int{}, want: allFlags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := defaultReadabilityOptions()
+			opts.RetryFlags = tt.retryFlags
+			r, err := NewFromHTML(html, &opts)
+			if err != nil {
+				t.Fatalf("NewFromHTML() error = %v", err)
+			}
+			r.prepDocument()
+			if article := r.grabArticle(); article == nil {
+				t.Fatal("grabArticle() returned nil")
+			}
+			if r.flags != tt.want {
+				t.Errorf("flags after retries = %#x, want %#x", r.flags, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcerptSkipsTitleEcho(t *testing.T) {
+	html := `<html><head><title>Ten ways to brew better coffee</title></head><body>
+		<article>
+			<p><span>Ten ways to brew better coffee</span></p>
+			<p><span>Good coffee starts with fresh beans, ground just before brewing and measured by weight rather than by the scoop.</span></p>
+			<p><span>Water just off the boil, around ninety-four degrees, extracts the flavour without scorching the grounds or leaving them sour.</span></p>
+		</article>
+	</body></html>`
+
+	r, err := NewFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("NewFromHTML: %v", err)
+	}
+	article, err := r.Parse()
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !strings.HasPrefix(article.Excerpt, "Good coffee starts with fresh beans") {
+		t.Errorf("Excerpt = %q, want the first paragraph after the title echo", article.Excerpt)
+	}
+}
+
+func TestParallelScoring(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString(`<html><head><title>Field notes</title></head><body><div class="page">`)
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&sb, `<div class="section-%d"><div class="inner"><h2>Day %d</h2>`, i%7, i)
+		for j := 0; j <= i%4; j++ {
+			fmt.Fprintf(&sb, `<p><span>On day %d the team walked %d kilometres, counted birds, measured the stream, and logged the weather, which stayed mild.</span></p>`, i, j+i%9)
+		}
+		sb.WriteString(`</div></div>`)
+	}
+	sb.WriteString(`</div></body></html>`)
+
+	score := func(parallel bool) []*NodeInfo {
+		opts := defaultReadabilityOptions()
+		opts.ParallelScoring = parallel
+		r, err := NewFromHTML(sb.String(), &opts)
+		if err != nil {
+			t.Fatalf("NewFromHTML() error = %v", err)
+		}
+		r.prepDocument()
+		return r.scoreNodes(r.prepareNodesForScoring(r.initializeDocumentBody()))
+	}
+
+	// The two runs parse their own documents, so nodes are compared by position
+	path := func(s *goquery.Selection) string {
+		var parts []string
+		for n := s.Get(0); n.Parent != nil; n = n.Parent {
+			index := 0
+			for c := n.PrevSibling; c != nil; c = c.PrevSibling {
+				index++
+			}
+			parts = append(parts, fmt.Sprintf("%s[%d]", n.Data, index))
+		}
+		return strings.Join(parts, "<")
+	}
+
+	serial := score(false)
+	parallel := score(true)
+	if len(serial) == 0 {
+		t.Fatal("no candidates were scored")
+	}
+	if len(parallel) != len(serial) {
+		t.Fatalf("parallel scoring found %d candidates, serial scoring %d", len(parallel), len(serial))
+	}
+	for i := range serial {
+		serialPath, parallelPath := path(serial[i].node), path(parallel[i].node)
+		if parallelPath != serialPath || parallel[i].contentScore != serial[i].contentScore {
+			t.Errorf("candidate %d: parallel %s scored %v, serial %s scored %v", i, parallelPath, parallel[i].contentScore, serialPath, serial[i].contentScore)
+		}
+	}
+}
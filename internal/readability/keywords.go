@@ -0,0 +1,107 @@
+package readability
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Limits of the words ranked as keywords
+const (
+	MinKeywordLength = 3 // Minimum number of letters of a keyword
+	MinKeywordCount  = 2 // Minimum number of times a keyword appears in the text
+)
+
+// keywordStopwords are the function words left out of keywords, by primary
+// language subtag. Text in other languages is ranked without removing any.
+var keywordStopwords = map[string]map[string]bool{
+	"en": stopwordSet(`about above after again against all also although among and another any
+		are aren't around because been before being below between both but can cannot could couldn't
+		did didn't does doesn't doing don't down during each either even ever every few for from
+		further get gets got had hadn't has hasn't have haven't having her here hers herself him
+		himself his how however i'm i've into isn't it's its itself just least less let let's like
+		made make many may might more most much must mustn't myself near neither never new nor not
+		now off often once one only onto other others our ours ourselves out over own per perhaps
+		rather really said same say says shall she she's should shouldn't since some still such than
+		that that's the their theirs them themselves then there there's these they they're thing
+		things this those though through thus too toward under until upon use used using very via
+		was wasn't way we're well were weren't what what's when where which while who whom whose why
+		will with within without won't would wouldn't yes yet you you're your yours yourself
+		yourselves`),
+	"fr": stopwordSet(`alors au aucun aussi autre aux avec avoir bon car ce cela celle celui ces cet
+		cette ceux chaque comme comment dans des donc dont du elle elles en encore est et été être eux
+		fait faire fois font ici il ils je la le les leur leurs lui mais me même mes moi mon ne nos
+		notre nous on ont ou où par parce pas peu peut plus pour pourquoi quand que quel quelle quels
+		qui sa sans se ses seulement si sien son sont sous sur ta tandis te tes toi ton tous tout
+		toute toutes très tu un une vos votre vous`),
+	"de": stopwordSet(`aber alle allem allen aller alles als also am an ander andere auch auf aus bei
+		bin bis bist da damit dann das dass dein deine dem den denn der des dich die dies diese dieser
+		dieses dir doch dort du durch ein eine einem einen einer eines er es etwas euch euer für gegen
+		hab habe haben hat hatte hier hin hinter ich ihm ihn ihnen ihr ihre im in ist jede jedem jeden
+		jeder jetzt kann kein keine können man mehr mein meine mich mir mit muss nach nicht nichts noch
+		nun nur ob oder ohne sehr sein seine sich sie sind so solche soll sondern um und uns unser
+		unter viel vom von vor war waren was weil wenn wer werden wie wieder will wir wird wo zu zum
+		zur über`),
+	"es": stopwordSet(`al algo algunos ante antes aquí así aunque bien cada como con contra cual
+		cuando del desde donde durante el ella ellas ellos en entre era eran es esa ese eso esta está
+		están este esto estos fue fueron ha hace hacia han hasta hay la las le les lo los más me mi
+		mientras muy nada ni no nos nosotros o otra otro otros para pero poco por porque que quien
+		se sea ser si sido sin sobre son su sus también tan tanto te tiene tienen todo todos tu un una
+		uno unos y ya yo`),
+}
+
+// stopwordSet returns the set of the space-separated words of a list
+func stopwordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// extractKeywords ranks the words of the text by how often they appear and
+// returns up to n of them, most frequent first, with ties in order of first
+// appearance. Words are lowercased; stopwords of the language, with English
+// assumed when none is declared, words shorter than MinKeywordLength letters,
+// numbers and words that appear fewer than MinKeywordCount times are left out.
+// This is a heuristic for tagging untagged content, not linguistic analysis:
+// inflected forms such as "bee" and "bees" are counted apart.
+func extractKeywords(text, lang string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	language := primaryLanguage(lang)
+	if language == "" {
+		language = "en"
+	}
+	stopwords := keywordStopwords[language]
+
+	counts := make(map[string]int)
+	var order []string
+	words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '\'' && c != '’'
+	})
+	for _, word := range words {
+		word = strings.Trim(strings.ReplaceAll(word, "’", "'"), "'")
+		if len([]rune(word)) < MinKeywordLength || stopwords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		if counts[word] == 0 {
+			order = append(order, word)
+		}
+		counts[word]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	var keywords []string
+	for _, word := range order {
+		if len(keywords) == n || counts[word] < MinKeywordCount {
+			break
+		}
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
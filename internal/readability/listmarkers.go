@@ -0,0 +1,130 @@
+package readability
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Run lengths of marker paragraphs that are turned into lists
+const (
+	// MinListMarkerItems is the number of consecutive paragraphs starting with
+	// a bullet glyph such as "•", or with consecutive numbers, that make a list
+	MinListMarkerItems = 2
+
+	// MinDashListItems is the number of consecutive paragraphs starting with
+	// "-" or "*" that make a list. It is higher than MinListMarkerItems since
+	// dialogue and asides are also written as paragraphs starting with a dash.
+	MinDashListItems = 3
+)
+
+// listMarkerRe matches the marker at the start of a pasted list item: a bullet
+// glyph, a dash or asterisk, or a number followed by "." or ")", which must be
+// followed by whitespace
+var listMarkerRe = regexp.MustCompile(`^\s*(?:([•◦▪▫‣●○■□∙⁃])|([-*])|(\d{1,3})[.)])\s+`)
+
+// listMarker is the marker found at the start of a paragraph
+type listMarker struct {
+	text   string // Bullet glyph or dash, empty for numbers
+	number int    // Number of a numbered item, 0 for bullets
+}
+
+// normalizeListMarkers turns runs of paragraphs that start with the same bullet
+// glyph or dash, or with consecutive numbers, into <ul> and <ol> lists, as in
+// content pasted from documents. Only sibling paragraphs separated by nothing
+// but whitespace are joined, every paragraph of the run must carry a marker,
+// and a run must have MinListMarkerItems items (MinDashListItems for "-" and
+// "*"), so a single paragraph of prose that starts with a dash is left alone.
+func normalizeListMarkers(articleContent *goquery.Selection) {
+	articleContent.Find("p").Each(func(i int, s *goquery.Selection) {
+		first := s.Get(0)
+		// Paragraphs moved into an earlier list are no longer paragraphs
+		if first.Parent == nil || first.Data != "p" {
+			return
+		}
+		marker, ok := paragraphListMarker(first)
+		if !ok || (marker.number != 0 && marker.number != 1) {
+			return
+		}
+
+		run := []*html.Node{first}
+		for next := nextListSibling(first); next != nil && next.Data == "p"; next = nextListSibling(next) {
+			nextMarker, ok := paragraphListMarker(next)
+			if !ok || nextMarker.text != marker.text || (marker.number != 0 && nextMarker.number != marker.number+len(run)) {
+				break
+			}
+			run = append(run, next)
+		}
+
+		minItems := MinListMarkerItems
+		if marker.text == "-" || marker.text == "*" {
+			minItems = MinDashListItems
+		}
+		if len(run) < minItems {
+			return
+		}
+
+		list := &html.Node{Type: html.ElementNode, DataAtom: atom.Ul, Data: "ul"}
+		if marker.number != 0 {
+			list.DataAtom, list.Data = atom.Ol, "ol"
+		}
+		first.Parent.InsertBefore(list, first)
+		for _, p := range run {
+			stripListMarker(p)
+			item := &html.Node{Type: html.ElementNode, DataAtom: atom.Li, Data: "li"}
+			for child := p.FirstChild; child != nil; child = p.FirstChild {
+				p.RemoveChild(child)
+				item.AppendChild(child)
+			}
+			p.Parent.RemoveChild(p)
+			list.AppendChild(item)
+		}
+	})
+}
+
+// paragraphListMarker returns the list marker at the start of a paragraph's
+// text, if any
+func paragraphListMarker(p *html.Node) (listMarker, bool) {
+	text := firstTextNode(p)
+	if text == nil {
+		return listMarker{}, false
+	}
+	match := listMarkerRe.FindStringSubmatch(text.Data)
+	if match == nil {
+		return listMarker{}, false
+	}
+	if match[3] != "" {
+		number, _ := strconv.Atoi(match[3])
+		return listMarker{number: number}, true
+	}
+	return listMarker{text: match[1] + match[2]}, true
+}
+
+// stripListMarker removes the list marker from the start of a paragraph's text
+func stripListMarker(p *html.Node) {
+	if text := firstTextNode(p); text != nil {
+		text.Data = listMarkerRe.ReplaceAllString(text.Data, "")
+	}
+}
+
+// firstTextNode returns the first text node of an element that isn't only
+// whitespace, looking into its inline children
+func firstTextNode(n *html.Node) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			if strings.TrimSpace(c.Data) != "" {
+				return c
+			}
+		case html.ElementNode:
+			if text := firstTextNode(c); text != nil {
+				return text
+			}
+		}
+	}
+	return nil
+}
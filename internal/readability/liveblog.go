@@ -0,0 +1,161 @@
+package readability
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mrjoshuak/readabiligo/internal/extractors"
+	"golang.org/x/net/html"
+)
+
+// Selectors of the parts of a live blog
+const (
+	// liveUpdateSelector matches the entries of common live blog markup:
+	// schema.org liveBlogUpdate items and the entry classes of live blog plugins
+	liveUpdateSelector = `[itemprop="liveBlogUpdate"], .liveblog-entry, .live-blog-entry, .liveblog-update, .live-update, .live-post, .lb-post`
+
+	// liveTimeSelector matches the timestamp of an entry
+	liveTimeSelector = `time, [itemprop="datePublished"], .timestamp, .update-time, .entry-time`
+
+	// MinLiveUpdates is the number of timestamped sibling blocks that make a
+	// container a live blog when no live blog markup is found
+	MinLiveUpdates = 3
+)
+
+// clockTimeRe matches a time of day such as "14:05" or "9:30"
+var clockTimeRe = regexp.MustCompile(`\b([01]?\d|2[0-3]):[0-5]\d\b`)
+
+// LiveUpdate is a timestamped entry of a live blog
+type LiveUpdate struct {
+	Timestamp string // Timestamp as written: the datetime attribute when present, otherwise the text
+	Body      string // Text of the entry, with paragraphs separated by blank lines
+}
+
+// getLiveUpdates finds the entries of a live blog and returns them in
+// chronological order. Entries are recognized by live blog markup, or else as
+// the largest group of at least MinLiveUpdates sibling blocks that each hold a
+// timestamp. Live blogs usually list the latest entry first, so entries are
+// sorted by time when every timestamp can be parsed, and kept in document
+// order otherwise. Entries without text are skipped.
+func (r *Readability) getLiveUpdates() []LiveUpdate {
+	entries := r.doc.Find(liveUpdateSelector)
+	if entries.Length() == 0 {
+		entries = r.findTimestampedSiblings()
+	}
+
+	var updates []LiveUpdate
+	var times []time.Time
+	sortable := true
+	entries.Each(func(i int, entry *goquery.Selection) {
+		// Entries nested in another entry are part of it
+		if entry.ParentsFiltered(liveUpdateSelector).Length() > 0 {
+			return
+		}
+		update := LiveUpdate{
+			Timestamp: liveUpdateTimestamp(entry),
+			Body:      liveUpdateBody(entry),
+		}
+		if update.Body == "" {
+			return
+		}
+		t := parseLiveUpdateTime(update.Timestamp, r.options.AssumeTimezone)
+		sortable = sortable && !t.IsZero()
+		updates = append(updates, update)
+		times = append(times, t)
+	})
+
+	if sortable {
+		sort.Stable(liveUpdatesByTime{updates, times})
+	}
+	return updates
+}
+
+// liveUpdatesByTime sorts live blog entries by their parsed timestamps
+type liveUpdatesByTime struct {
+	updates []LiveUpdate
+	times   []time.Time
+}
+
+func (u liveUpdatesByTime) Len() int           { return len(u.updates) }
+func (u liveUpdatesByTime) Less(i, j int) bool { return u.times[i].Before(u.times[j]) }
+func (u liveUpdatesByTime) Swap(i, j int) {
+	u.updates[i], u.updates[j] = u.updates[j], u.updates[i]
+	u.times[i], u.times[j] = u.times[j], u.times[i]
+}
+
+// findTimestampedSiblings returns the largest group of sibling blocks that each
+// hold a timestamp, or an empty selection when no group has MinLiveUpdates
+func (r *Readability) findTimestampedSiblings() *goquery.Selection {
+	best := r.doc.Selection.Slice(0, 0)
+	seen := make(map[*html.Node]bool)
+	r.doc.Find(liveTimeSelector).Each(func(i int, timestamp *goquery.Selection) {
+		// Walk up to the block that is one of a run of timestamped siblings
+		for block := timestamp.Parent(); block.Length() > 0 && !block.Is("body"); block = block.Parent() {
+			parent := block.Parent()
+			if seen[parent.Get(0)] {
+				break
+			}
+			siblings := parent.ChildrenFiltered(goquery.NodeName(block)).FilterFunction(func(j int, s *goquery.Selection) bool {
+				return s.Find(liveTimeSelector).Length() > 0
+			})
+			if siblings.Length() >= MinLiveUpdates {
+				seen[parent.Get(0)] = true
+				if siblings.Length() > best.Length() {
+					best = siblings
+				}
+				break
+			}
+		}
+	})
+	return best
+}
+
+// liveUpdateTimestamp returns the timestamp of a live blog entry, preferring a
+// machine readable datetime attribute
+func liveUpdateTimestamp(entry *goquery.Selection) string {
+	timestamp := entry.Find(liveTimeSelector).First()
+	if datetime := strings.TrimSpace(timestamp.AttrOr("datetime", "")); datetime != "" {
+		return datetime
+	}
+	return getNormalized(timestamp.Text())
+}
+
+// liveUpdateBody returns the text of a live blog entry without its timestamp
+func liveUpdateBody(entry *goquery.Selection) string {
+	body := entry.Clone()
+	body.Find(liveTimeSelector).Remove()
+
+	paragraphs := body.Find("h1, h2, h3, h4, h5, h6, p, li, blockquote")
+	if paragraphs.Length() == 0 {
+		return getNormalized(body.Text())
+	}
+	var texts []string
+	paragraphs.Each(func(i int, p *goquery.Selection) {
+		// Nested blocks are part of their outer block
+		if p.ParentsFiltered("p, li, blockquote").Length() > 0 {
+			return
+		}
+		if text := getNormalized(p.Text()); text != "" {
+			texts = append(texts, text)
+		}
+	})
+	return strings.Join(texts, "\n\n")
+}
+
+// parseLiveUpdateTime parses the timestamp of a live blog entry, which is a full
+// date or, on many live blogs, only a time of day. It returns the zero time
+// when the timestamp can't be parsed.
+func parseLiveUpdateTime(timestamp string, loc *time.Location) time.Time {
+	if t := extractors.ParseFlexibleDateFormatIn(timestamp, loc); !t.IsZero() {
+		return t
+	}
+	if clock := clockTimeRe.FindString(timestamp); clock != "" {
+		if t, err := time.Parse("15:04", clock); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,57 @@
+package readability
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// newLogger returns the logger of debug events for the given options: the
+// provided Logger, a text logger writing to stderr in Debug mode, or a logger
+// discarding every event
+func newLogger(options ReadabilityOptions) *slog.Logger {
+	if options.Logger != nil {
+		return options.Logger
+	}
+	if options.Debug {
+		return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	return slog.New(slog.DiscardHandler)
+}
+
+// debugEnabled reports whether debug events are logged, so that attributes that
+// are expensive to build, like the HTML of a node, are only built when needed
+func (r *Readability) debugEnabled() bool {
+	return r.logger != nil && r.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logEvent logs a debug event of an extraction phase, such as "prepare" or
+// "cleanup", with the action taken, the node it was taken on (if any) and
+// further key/value attributes
+func (r *Readability) logEvent(phase, action string, node *goquery.Selection, args ...any) {
+	if !r.debugEnabled() {
+		return
+	}
+	attrs := []any{slog.String("phase", phase), slog.String("action", action)}
+	if node != nil && node.Length() > 0 {
+		attrs = append(attrs, slog.String("node", describeNode(node)))
+	}
+	r.logger.Debug(phase+": "+action, append(attrs, args...)...)
+}
+
+// describeNode returns a short CSS-like description of a node, such as
+// "div#main.content", for log events
+func describeNode(node *goquery.Selection) string {
+	var b strings.Builder
+	b.WriteString(goquery.NodeName(node))
+	if id := strings.TrimSpace(node.AttrOr("id", "")); id != "" {
+		b.WriteString("#" + id)
+	}
+	for _, class := range strings.Fields(node.AttrOr("class", "")) {
+		b.WriteString("." + class)
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// mathSpacedOperators are the MathML operators written with a space on each
+// side in the text rendering of an equation
+var mathSpacedOperators = map[string]bool{
+	"=": true, "+": true, "-": true, "−": true, "±": true, "×": true, "·": true, "÷": true,
+	"<": true, ">": true, "≤": true, "≥": true, "≠": true, "≈": true, "≡": true, "→": true,
+}
+
+// renderMathAsText replaces each MathML equation with a <span> holding a plain
+// text rendering, such as "(a + b)/2" or "x^2", so that it survives cleanup. Equations
+// whose markup gives no text fall back to their alttext, then aria-label.
+func (r *Readability) renderMathAsText() {
+	r.doc.Find("math").Each(func(i int, s *goquery.Selection) {
+		text := strings.Join(strings.Fields(linearizeMath(s.Get(0))), " ")
+		if text == "" {
+			text = getNormalized(s.AttrOr("alttext", s.AttrOr("aria-label", "")))
+		}
+		if text == "" {
+			s.Remove()
+			return
+		}
+		span := &html.Node{Type: html.ElementNode, DataAtom: atom.Span, Data: "span"}
+		span.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+		s.ReplaceWithNodes(span)
+	})
+}
+
+// linearizeMath renders a MathML element as text: fractions as "a/b", scripts
+// as "x^2" and "x_i", roots as "√x", fenced groups and tables in brackets, with
+// compound operands put in parentheses. Annotations are skipped.
+func linearizeMath(n *html.Node) string {
+	if n.Type != html.ElementNode {
+		return ""
+	}
+
+	children := elementChildren(n)
+	arg := func(i int) string {
+		if i < len(children) {
+			return linearizeMath(children[i])
+		}
+		return ""
+	}
+
+	switch n.Data {
+	case "annotation", "annotation-xml", "mphantom", "none", "mprescripts":
+		return ""
+	case "semantics":
+		return arg(0)
+	case "mspace":
+		return " "
+	case "mo":
+		op := strings.TrimSpace(mathText(n))
+		if mathSpacedOperators[op] {
+			return " " + op + " "
+		}
+		return op
+	case "mi", "mn", "mtext", "ms":
+		return strings.TrimSpace(mathText(n))
+	case "mfrac":
+		return mathGroup(arg(0)) + "/" + mathGroup(arg(1))
+	case "msup", "mover":
+		return arg(0) + "^" + mathGroup(arg(1))
+	case "msub", "munder":
+		return arg(0) + "_" + mathGroup(arg(1))
+	case "msubsup", "munderover":
+		return arg(0) + "_" + mathGroup(arg(1)) + "^" + mathGroup(arg(2))
+	case "msqrt":
+		return "√" + mathGroup(mathJoin(children))
+	case "mroot":
+		return mathGroup(arg(0)) + "^(1/" + arg(1) + ")"
+	case "mfenced":
+		open, close := "(", ")"
+		separator := ","
+		for _, attr := range n.Attr {
+			switch attr.Key {
+			case "open":
+				open = attr.Val
+			case "close":
+				close = attr.Val
+			case "separators":
+				separator = strings.TrimSpace(attr.Val)
+			}
+		}
+		parts := make([]string, len(children))
+		for i, c := range children {
+			parts[i] = strings.TrimSpace(linearizeMath(c))
+		}
+		return open + strings.Join(parts, separator+" ") + close
+	case "mtable":
+		rows := make([]string, len(children))
+		for i, row := range children {
+			cells := elementChildren(row)
+			texts := make([]string, len(cells))
+			for j, cell := range cells {
+				texts[j] = strings.TrimSpace(linearizeMath(cell))
+			}
+			rows[i] = strings.Join(texts, ", ")
+		}
+		return "[" + strings.Join(rows, "; ") + "]"
+	}
+	return mathJoin(children)
+}
+
+// mathJoin renders MathML elements one after the other
+func mathJoin(nodes []*html.Node) string {
+	var sb strings.Builder
+	for _, c := range nodes {
+		sb.WriteString(linearizeMath(c))
+	}
+	return sb.String()
+}
+
+// mathText returns the text of a MathML token element
+func mathText(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+	return sb.String()
+}
+
+// mathGroup puts a rendered operand in parentheses when it is more than a
+// single token, so that "(a + b)/2" isn't read as "a + b/2"
+func mathGroup(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if strings.ContainsAny(text, " +-−/^_,=") && !(strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")")) {
+		return "(" + text + ")"
+	}
+	return text
+}
@@ -1,8 +1,13 @@
 package readability
 
 import (
+	"fmt"
+	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -72,9 +77,16 @@ func (r *Readability) getArticleMetadata(jsonLd map[string]string) map[string]st
 			metadata["title"] = values["og:title"]
 		} else if values["twitter:title"] != "" {
 			metadata["title"] = values["twitter:title"]
+		} else if ariaTitle := r.getARIATitle(); ariaTitle != "" {
+			metadata["title"] = ariaTitle
 		}
 	}
 
+	// Drop decorative emoji, bullets and arrows in front of the title
+	if r.options.StripLeadingSymbols {
+		metadata["title"] = stripLeadingSymbols(metadata["title"])
+	}
+
 	// Extract article byline
 	if jsonLd["byline"] != "" {
 		metadata["byline"] = jsonLd["byline"]
@@ -84,6 +96,8 @@ func (r *Readability) getArticleMetadata(jsonLd map[string]string) map[string]st
 		metadata["byline"] = values["dcterm:creator"]
 	} else if values["author"] != "" {
 		metadata["byline"] = values["author"]
+	} else if ariaByline := r.getARIAByline(); ariaByline != "" {
+		metadata["byline"] = ariaByline
 	}
 
 	// Extract article excerpt/description
@@ -113,6 +127,20 @@ func (r *Readability) getArticleMetadata(jsonLd map[string]string) map[string]st
 		metadata["date"] = jsonLd["date"]
 	}
 
+	// Extract author image (if enabled)
+	if r.options.ExtractAuthorImage {
+		if authorImage := r.getAuthorImage(jsonLd); authorImage != "" {
+			metadata["authorImage"] = authorImage
+		}
+	}
+
+	// Extract theme color (if enabled)
+	if r.options.ExtractThemeColor {
+		if themeColor := r.getThemeColor(); themeColor != "" {
+			metadata["themeColor"] = themeColor
+		}
+	}
+
 	// Unescape HTML entities
 	for key, value := range metadata {
 		metadata[key] = unescapeHtmlEntities(value)
@@ -137,7 +165,7 @@ func (r *Readability) getArticleTitle() string {
 	h1Title := ""
 	r.doc.Find("h1[itemprop='headline']").Each(func(i int, s *goquery.Selection) {
 		if i == 0 { // Only take the first one
-			h1Title = strings.TrimSpace(s.Text())
+			h1Title = r.headingText(s)
 		}
 	})
 	
@@ -175,7 +203,7 @@ func (r *Readability) getArticleTitle() string {
 		// Check if we have an h1 or h2 with the exact title
 		matchFound := false
 		r.doc.Find("h1, h2").EachWithBreak(func(i int, s *goquery.Selection) bool {
-			if strings.TrimSpace(s.Text()) == docTitle {
+			if r.headingText(s) == docTitle {
 				matchFound = true
 				return false // stop iteration
 			}
@@ -204,18 +232,10 @@ func (r *Readability) getArticleTitle() string {
 		// If the title is empty, too long, or too short, look for h1 elements
 		h1s := r.doc.Find("h1")
 		if h1s.Length() == 1 {
-			docTitle = strings.TrimSpace(h1s.Text())
+			docTitle = r.headingText(h1s)
 		} else if h1s.Length() > 1 {
-			// If multiple h1 elements, prioritize ones with itemprop="headline"
-			h1Title := ""
-			h1s.Each(func(i int, s *goquery.Selection) {
-				if itemprop, exists := s.Attr("itemprop"); exists && itemprop == "headline" {
-					h1Title = strings.TrimSpace(s.Text())
-					return // Break the loop
-				}
-			})
-			
-			if h1Title != "" {
+			// If multiple h1 elements, pick the one most likely to be the article title
+			if h1Title := r.selectTitleHeading(h1s, origTitle); h1Title != "" {
 				docTitle = h1Title
 			}
 		}
@@ -232,6 +252,525 @@ func (r *Readability) getArticleTitle() string {
 	return docTitle
 }
 
+// headingText returns the text of a heading for use as the title. With
+// NormalizeHeadingWhitespace set, the lines of a multi-line heading are joined
+// with single spaces.
+func (r *Readability) headingText(s *goquery.Selection) string {
+	if !r.options.NormalizeHeadingWhitespace {
+		return strings.TrimSpace(s.Text())
+	}
+	return getHeadingText(s)
+}
+
+// decorativeSymbols are the bullets and arrows that stripLeadingSymbols removes
+// along with emoji; other punctuation and math symbols, such as "#", "$" or "+",
+// can be part of the title
+var decorativeSymbols = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00b7, Hi: 0x00b7, Stride: 1}, // Middle dot
+		{Lo: 0x2022, Hi: 0x2023, Stride: 1}, // Bullet, triangular bullet
+		{Lo: 0x2043, Hi: 0x2043, Stride: 1}, // Hyphen bullet
+		{Lo: 0x2190, Hi: 0x21ff, Stride: 1}, // Arrows
+		{Lo: 0x2219, Hi: 0x2219, Stride: 1}, // Bullet operator
+		{Lo: 0x27f0, Hi: 0x27ff, Stride: 1}, // Supplemental arrows-A
+		{Lo: 0x2900, Hi: 0x297f, Stride: 1}, // Supplemental arrows-B
+	},
+}
+
+// stripLeadingSymbols removes the decorative characters in front of a title,
+// as in "🔥 Hot Take" or "» Latest news": other symbols (Unicode category So,
+// which holds emoji and most arrows), modifier symbols such as skin tones,
+// combining and enclosing marks such as emoji variation selectors, format
+// characters such as zero width joiners, bullets and arrows, and the spaces
+// between them. A title that is only such characters is returned unchanged.
+func stripLeadingSymbols(title string) string {
+	stripped := strings.TrimLeftFunc(title, func(c rune) bool {
+		return unicode.IsSpace(c) || c == '»' || unicode.In(c, unicode.So, unicode.Sk, unicode.Mn, unicode.Me, unicode.Cf, decorativeSymbols)
+	})
+	if stripped == "" {
+		return title
+	}
+	return stripped
+}
+
+// selectTitleHeading picks the article title from several <h1> elements, such
+// as a page with a site-name h1 in its header and the article's own h1. An h1
+// with itemprop="headline" wins, then the h1 that the article or main element
+// is aria-labelledby; otherwise h1s inside the content container (article, main
+// or the articleBody) are preferred, and among the candidates the one most
+// similar to og:title, or else the <title>, is chosen.
+func (r *Readability) selectTitleHeading(h1s *goquery.Selection, docTitle string) string {
+	if headline := h1s.Filter(`[itemprop="headline"]`).First(); headline.Length() > 0 {
+		return r.headingText(headline)
+	}
+	if labelling := r.getARIAHeading(); labelling != nil && h1s.IsSelection(labelling) {
+		return r.headingText(labelling)
+	}
+
+	candidates := h1s.FilterFunction(func(i int, s *goquery.Selection) bool {
+		return s.Closest(`article, main, [role="main"], [itemprop~="articleBody"]`).Length() > 0
+	})
+	if candidates.Length() == 0 {
+		candidates = h1s
+	}
+
+	reference := strings.TrimSpace(r.doc.Find(`meta[property="og:title"]`).First().AttrOr("content", ""))
+	if reference == "" {
+		reference = docTitle
+	}
+
+	best := ""
+	bestSimilarity := -1.0
+	candidates.Each(func(i int, s *goquery.Selection) {
+		text := getNormalized(r.headingText(s))
+		if text == "" {
+			return
+		}
+		if similarity := textSimilarity(text, reference); similarity > bestSimilarity {
+			best = text
+			bestSimilarity = similarity
+		}
+	})
+	return best
+}
+
+// getAuthorImage finds the URL of the author's profile image. The JSON-LD
+// author image is preferred; otherwise the first image inside a byline
+// element is used. Relative URLs are resolved against the document base.
+func (r *Readability) getAuthorImage(jsonLd map[string]string) string {
+	src := jsonLd["authorImage"]
+
+	if src == "" {
+		r.doc.Find(`[rel="author"] img, [itemprop~="author"] img, .author img, .byline img`).EachWithBreak(func(i int, img *goquery.Selection) bool {
+			src = strings.TrimSpace(img.AttrOr("src", ""))
+			if src == "" || strings.HasPrefix(src, "data:") {
+				src = strings.TrimSpace(img.AttrOr("data-src", ""))
+			}
+			return src == ""
+		})
+	}
+
+	if src == "" {
+		return ""
+	}
+	return r.resolveDocumentURL(src)
+}
+
+// getThemeColor returns the page's theme color from <meta name="theme-color">.
+// A declaration without a media query is preferred over the light/dark scheme
+// variants. The color is normalized to lowercase hex when possible.
+func (r *Readability) getThemeColor() string {
+	metas := r.doc.Find(`meta[name="theme-color" i][content]`)
+	meta := metas.Not("[media]").First()
+	if meta.Length() == 0 {
+		meta = metas.First()
+	}
+	return normalizeColor(meta.AttrOr("content", ""))
+}
+
+// rgbColorRe matches rgb() and rgba() colors with integer channels
+var rgbColorRe = regexp.MustCompile(`^rgba?\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*(?:,\s*([\d.]+)\s*)?\)$`)
+
+// hexColorRe matches 3, 4, 6 and 8 digit hex colors
+var hexColorRe = regexp.MustCompile(`^#([0-9a-f]{3,4}|[0-9a-f]{6}|[0-9a-f]{8})$`)
+
+// normalizeColor converts a CSS color to lowercase hex. Short hex colors are
+// expanded to six (or eight) digits and rgb()/rgba() colors are converted;
+// other values, such as color names, are returned lowercased.
+func normalizeColor(color string) string {
+	color = strings.ToLower(strings.TrimSpace(color))
+
+	if matches := hexColorRe.FindStringSubmatch(color); matches != nil {
+		digits := matches[1]
+		if len(digits) <= 4 {
+			var expanded strings.Builder
+			for _, c := range digits {
+				expanded.WriteRune(c)
+				expanded.WriteRune(c)
+			}
+			digits = expanded.String()
+		}
+		return "#" + digits
+	}
+
+	if matches := rgbColorRe.FindStringSubmatch(color); matches != nil {
+		hex := "#"
+		for _, channel := range matches[1:4] {
+			value, _ := strconv.Atoi(channel)
+			if value > 255 {
+				return color
+			}
+			hex += fmt.Sprintf("%02x", value)
+		}
+		if matches[4] != "" {
+			alpha, err := strconv.ParseFloat(matches[4], 64)
+			if err != nil || alpha > 1 {
+				return color
+			}
+			if alpha < 1 {
+				hex += fmt.Sprintf("%02x", int(alpha*255+0.5))
+			}
+		}
+		return hex
+	}
+
+	return color
+}
+
+// resolveDocumentURL resolves a URL against the document's <base href>, or
+// its og:url when there is no base element. The URL is returned unchanged
+// when neither is present or either fails to parse.
+func (r *Readability) resolveDocumentURL(uri string) string {
+	base := r.doc.Find("base[href]").First().AttrOr("href", "")
+	if base == "" {
+		base = r.doc.Find(`head meta[property="og:url"]`).First().AttrOr("content", "")
+	}
+	if base == "" {
+		return uri
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return uri
+	}
+	ref, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	return baseURL.ResolveReference(ref).String()
+}
+
+// getAlternateLinks lists the alternate versions of the page declared with
+// <link rel="alternate">, such as RSS and Atom feeds and translations, and the
+// AMP version declared with <link rel="amphtml">, in document order. URLs are
+// resolved with resolveDocumentURL. Alternate stylesheets are left out, and a
+// URL is listed once per type.
+func (r *Readability) getAlternateLinks() []AlternateLink {
+	var links []AlternateLink
+	seen := make(map[string]bool)
+	r.doc.Find(`link[rel~="alternate" i][href], link[rel~="amphtml" i][href]`).Each(func(i int, s *goquery.Selection) {
+		rel := strings.Fields(strings.ToLower(s.AttrOr("rel", "")))
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		if href == "" || slices.Contains(rel, "stylesheet") {
+			return
+		}
+
+		link := AlternateLink{
+			Type:     strings.ToLower(strings.TrimSpace(s.AttrOr("type", ""))),
+			Href:     r.resolveDocumentURL(href),
+			Title:    getNormalized(s.AttrOr("title", "")),
+			Hreflang: strings.TrimSpace(s.AttrOr("hreflang", "")),
+		}
+		if slices.Contains(rel, "amphtml") {
+			link.Type = "amp"
+		}
+		if key := link.Type + " " + link.Href; !seen[key] {
+			seen[key] = true
+			links = append(links, link)
+		}
+	})
+	return links
+}
+
+// continuationLinkRe matches the text of links that explicitly continue a
+// teaser to the full article
+var continuationLinkRe = regexp.MustCompile(`(?i)^(continue reading|keep reading|read (the )?(full|whole|entire) (story|article|post)|read the rest( of (the|this) (story|article|post))?)[^\pL\pN]*$`)
+
+// readMoreLinkRe matches "Read more" links, which continue a teaser only when
+// they end its content, as index pages use them for other articles too
+var readMoreLinkRe = regexp.MustCompile(`(?i)^read more[^\pL\pN]*$`)
+
+// getFullContentURL detects teaser pages, whose short content ends with a
+// "Continue reading" style link to the full article, and returns the resolved
+// URL of that link. Links must read as an explicit continuation such as
+// "Continue reading" or "Read the full story", or be a "Read more" link that is
+// the last link of the content; links back to the page itself (its canonical
+// URL) are ignored. It returns "" when the content is long enough or no such
+// link is found.
+func (r *Readability) getFullContentURL(article *goquery.Selection, textLength int) string {
+	if textLength >= r.options.CharThreshold {
+		return ""
+	}
+
+	canonical := r.getCanonicalURL()
+	fullContentURL := ""
+	lastLink := article.Find("a[href]").Last()
+
+	// Prefer links kept in the article, then look through the rest of the page
+	article.Find("a[href]").AddSelection(r.doc.Find("body a[href]")).EachWithBreak(func(i int, link *goquery.Selection) bool {
+		href := strings.TrimSpace(link.AttrOr("href", ""))
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			return true
+		}
+		text := getNormalized(link.Text())
+		if !continuationLinkRe.MatchString(text) && !(readMoreLinkRe.MatchString(text) && lastLink.IsSelection(link)) {
+			return true
+		}
+
+		resolved := r.resolveDocumentURL(href)
+		if resolved == canonical {
+			return true
+		}
+		fullContentURL = resolved
+		return false
+	})
+
+	return fullContentURL
+}
+
+// getExcerpt returns the text of the first paragraph of the article for use as
+// its excerpt. A paragraph that only echoes the title, as a kicker or a repeated
+// headline often does, is skipped for the next one; it is used only when no
+// other paragraph has text.
+func (r *Readability) getExcerpt(article *goquery.Selection) string {
+	title := strings.TrimSpace(r.articleTitle)
+	excerpt := ""
+	article.Find("p").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			return true
+		}
+		if title != "" && (strings.EqualFold(text, title) || textSimilarity(title, text) > TitleSimilarityThreshold) {
+			if excerpt == "" {
+				excerpt = text
+			}
+			return true
+		}
+		excerpt = text
+		return false
+	})
+	return excerpt
+}
+
+// getPrimaryDocumentURL detects landing pages whose real content is a linked
+// PDF, such as a report page holding little more than a "Download the report
+// (PDF)" link, and returns the resolved URL of that link. A PDF link is one whose
+// path ends in ".pdf" or that declares type="application/pdf"; links kept in the
+// article are preferred over the rest of the page. The page is a landing page
+// when its content is shorter than CharThreshold, or whatever its length when
+// its og:type is "document". It returns "" when the page is not a landing page.
+func (r *Readability) getPrimaryDocumentURL(article *goquery.Selection, textLength int) string {
+	ogType := strings.ToLower(strings.TrimSpace(r.doc.Find(`meta[property="og:type"]`).First().AttrOr("content", "")))
+	if textLength >= r.options.CharThreshold && ogType != "document" {
+		return ""
+	}
+
+	documentURL := ""
+	article.Find("a[href]").AddSelection(r.doc.Find("body a[href]")).EachWithBreak(func(i int, link *goquery.Selection) bool {
+		href := strings.TrimSpace(link.AttrOr("href", ""))
+		if href == "" || !isPDFLink(link, href) {
+			return true
+		}
+		documentURL = r.resolveDocumentURL(href)
+		return false
+	})
+
+	return documentURL
+}
+
+// isPDFLink reports whether a link points at a PDF document
+func isPDFLink(link *goquery.Selection, href string) bool {
+	if strings.EqualFold(strings.TrimSpace(link.AttrOr("type", "")), "application/pdf") {
+		return true
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Path), ".pdf")
+}
+
+// getCanonicalURL returns the page's canonical URL from <link rel="canonical">,
+// falling back to og:url
+func (r *Readability) getCanonicalURL() string {
+	canonical := strings.TrimSpace(r.doc.Find(`link[rel="canonical"]`).First().AttrOr("href", ""))
+	if canonical == "" {
+		canonical = strings.TrimSpace(r.doc.Find(`meta[property="og:url"]`).First().AttrOr("content", ""))
+	}
+	if canonical == "" {
+		return ""
+	}
+	return r.resolveDocumentURL(canonical)
+}
+
+// getVideoEmbeds lists the allowed video embeds in the article content, in
+// document order. Embeds are matched with the same AllowedVideoRegex used to
+// preserve them during cleanup.
+func (r *Readability) getVideoEmbeds(article *goquery.Selection) []VideoEmbed {
+	var videos []VideoEmbed
+	article.Find("iframe, embed, object").Each(func(i int, node *goquery.Selection) {
+		tag := strings.ToLower(goquery.NodeName(node))
+		if !r.isAllowedVideo(node, tag) {
+			return
+		}
+
+		// Use the first attribute that points at an allowed video host
+		src := ""
+		for _, attr := range node.Get(0).Attr {
+			if r.options.AllowedVideoRegex.MatchString(attr.Val) {
+				src = attr.Val
+				break
+			}
+		}
+		if src == "" && tag == "object" {
+			node.Find("param[value], embed[src]").EachWithBreak(func(i int, child *goquery.Selection) bool {
+				if val := child.AttrOr("value", child.AttrOr("src", "")); r.options.AllowedVideoRegex.MatchString(val) {
+					src = val
+				}
+				return src == ""
+			})
+		}
+		if src == "" {
+			return
+		}
+
+		video := VideoEmbed{
+			URL:   r.resolveVideoURL(src),
+			Title: strings.TrimSpace(node.AttrOr("title", node.AttrOr("aria-label", ""))),
+		}
+		if poster := node.AttrOr("poster", node.AttrOr("data-poster", "")); poster != "" {
+			video.Poster = r.resolveVideoURL(poster)
+		}
+		if u, err := url.Parse(video.URL); err == nil {
+			video.Provider = videoProvider(u.Hostname())
+		}
+		videos = append(videos, video)
+	})
+	return videos
+}
+
+// resolveVideoURL resolves an embed URL against the document, defaulting
+// protocol-relative URLs to https
+func (r *Readability) resolveVideoURL(uri string) string {
+	uri = r.resolveDocumentURL(strings.TrimSpace(uri))
+	if strings.HasPrefix(uri, "//") {
+		uri = "https:" + uri
+	}
+	return uri
+}
+
+// videoProvider derives a provider name from an embed host, e.g.
+// "www.youtube-nocookie.com" becomes "youtube" and "player.vimeo.com" "vimeo"
+func videoProvider(host string) string {
+	labels := strings.Split(strings.ToLower(host), ".")
+	if len(labels) < 2 {
+		return strings.ToLower(host)
+	}
+	return strings.TrimSuffix(labels[len(labels)-2], "-nocookie")
+}
+
+// codeLanguageClassPrefixes are the class prefixes highlighters use to label
+// the language of a code block, e.g. "language-go" or "lang-go"
+var codeLanguageClassPrefixes = []string{"language-", "lang-"}
+
+// labelCodeBlocks copies the language hint of each <pre> block, from a
+// data-language attribute or a language-* class on the block or its <code>, into
+// a data-lang attribute, so that it outlives class cleanup and stays in Content
+func labelCodeBlocks(article *goquery.Selection) {
+	article.Find("pre").Each(func(i int, pre *goquery.Selection) {
+		if strings.TrimSpace(pre.AttrOr("data-lang", "")) != "" {
+			return
+		}
+		if language := codeLanguage(pre.AddSelection(pre.ChildrenFiltered("code"))); language != "" {
+			pre.SetAttr("data-lang", language)
+		}
+	})
+}
+
+// codeLanguage returns the first language hint declared on the given elements
+func codeLanguage(nodes *goquery.Selection) string {
+	language := ""
+	nodes.EachWithBreak(func(i int, node *goquery.Selection) bool {
+		for _, attr := range []string{"data-lang", "data-language"} {
+			if value := strings.TrimSpace(node.AttrOr(attr, "")); value != "" {
+				language = value
+				return false
+			}
+		}
+		for _, class := range strings.Fields(node.AttrOr("class", "")) {
+			for _, prefix := range codeLanguageClassPrefixes {
+				if strings.HasPrefix(class, prefix) && len(class) > len(prefix) {
+					language = strings.TrimPrefix(class, prefix)
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return language
+}
+
+// getCodeBlocks lists the <pre> code blocks in the article content, in document
+// order, with the language labelled by labelCodeBlocks and the caption of their
+// enclosing figure
+func getCodeBlocks(article *goquery.Selection) []CodeBlock {
+	var blocks []CodeBlock
+	article.Find("pre").Each(func(i int, pre *goquery.Selection) {
+		// Nested <pre> blocks are part of their outer block
+		if pre.ParentsFiltered("pre").Length() > 0 {
+			return
+		}
+		code := strings.Trim(pre.Text(), "\n")
+		if strings.TrimSpace(code) == "" {
+			return
+		}
+		block := CodeBlock{
+			Language: strings.ToLower(strings.TrimSpace(pre.AttrOr("data-lang", ""))),
+			Code:     code,
+		}
+		if figure := pre.Closest("figure"); figure.Length() > 0 {
+			block.Caption = getNormalized(figure.ChildrenFiltered("figcaption").First().Text())
+		}
+		blocks = append(blocks, block)
+	})
+	return blocks
+}
+
+// getTables lists the data tables in the article content, in document order,
+// with their caption, summary attribute, header cells and body rows. Tables
+// are classified by markDataTables, or by isTableData when the article was not
+// prepared. Layout tables and tables holding other tables are left out.
+func (r *Readability) getTables(article *goquery.Selection) []Table {
+	var tables []Table
+	article.Find("table").Each(func(i int, table *goquery.Selection) {
+		if table.Find("table").Length() > 0 {
+			return
+		}
+		switch table.AttrOr("data-readability-table-type", "") {
+		case "presentation":
+			return
+		case "":
+			if !r.isTableData(table) {
+				return
+			}
+		}
+
+		result := Table{
+			Caption: getNormalized(table.Find("caption").First().Text()),
+			Summary: getNormalized(table.AttrOr("summary", "")),
+		}
+		table.Find("tr").Each(func(j int, tr *goquery.Selection) {
+			var cells []string
+			tr.ChildrenFiltered("th, td").Each(func(k int, cell *goquery.Selection) {
+				cells = append(cells, getNormalized(cell.Text()))
+			})
+			if len(cells) == 0 {
+				return
+			}
+			// Header cells come from the thead, or from a leading row of th cells
+			isHeader := tr.ParentsFiltered("thead").Length() > 0 ||
+				(result.Headers == nil && result.Rows == nil && tr.ChildrenFiltered("td").Length() == 0)
+			if isHeader && result.Headers == nil {
+				result.Headers = cells
+			} else {
+				result.Rows = append(result.Rows, cells)
+			}
+		})
+		tables = append(tables, result)
+	})
+	return tables
+}
+
 // checkByline checks if a node is a byline
 func (r *Readability) checkByline(node *goquery.Selection, matchString string) bool {
 	if r.articleByline != "" {
@@ -253,6 +792,43 @@ func (r *Readability) checkByline(node *goquery.Selection, matchString string) b
 	return false
 }
 
+// removeBylineElements removes the elements of the article that hold the
+// byline, once it has been captured into the metadata. Byline elements are
+// recognized as in checkByline, but also when they mix the author with a date
+// ("By Jane Doe | 3 May"), which checkByline leaves in place. They are only
+// removed when their short text mentions the captured byline, so that longer
+// author boxes and bylines of other people are kept. A byline element is
+// removed together with the ancestors that hold nothing longer than a byline,
+// so that a paragraph reading "By <a rel="author">Jane Doe</a>" goes as a whole.
+func (r *Readability) removeBylineElements(article *goquery.Selection, byline string) {
+	byline = strings.ToLower(getNormalized(byline))
+	if byline == "" {
+		return
+	}
+
+	article.Find("*").Each(func(i int, s *goquery.Selection) {
+		rel := s.AttrOr("rel", "")
+		itemprop := s.AttrOr("itemprop", "")
+		matchString := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if rel != "author" && !strings.Contains(itemprop, "author") && !RegexpByline.MatchString(matchString) {
+			return
+		}
+
+		text := getNormalized(s.Text())
+		if len(text) > 100 || !strings.Contains(strings.ToLower(text), byline) {
+			return
+		}
+		for parent := s.Parent(); parent.Length() > 0 && !parent.IsSelection(article); parent = parent.Parent() {
+			if len(getNormalized(parent.Text())) > 100 {
+				break
+			}
+			s = parent
+		}
+		r.logEvent("cleanup", "remove byline", s, "text", text)
+		s.Remove()
+	})
+}
+
 // getJSONLD extracts metadata from JSON-LD objects in the document
 func (r *Readability) getJSONLD() map[string]string {
 	// Create an empty map to store extracted metadata
@@ -300,6 +876,17 @@ func (r *Readability) getJSONLD() map[string]string {
 			metadata["byline"] = authorMatches[1]
 		}
 
+		// Extract author image, given as a URL string or an ImageObject
+		authorImageRe := regexp.MustCompile(`"author"\s*:\s*\[?\s*{[^{}]*?"image"\s*:\s*(?:"([^"]+)"|{[^{}]*?"url"\s*:\s*"([^"]+)")`)
+		authorImageMatches := authorImageRe.FindStringSubmatch(content)
+		if len(authorImageMatches) > 2 {
+			if authorImageMatches[1] != "" {
+				metadata["authorImage"] = authorImageMatches[1]
+			} else {
+				metadata["authorImage"] = authorImageMatches[2]
+			}
+		}
+
 		// Extract description
 		descRe := regexp.MustCompile(`"description"\s*:\s*"([^"]+)"`)
 		descMatches := descRe.FindStringSubmatch(content)
@@ -323,4 +910,217 @@ func (r *Readability) getJSONLD() map[string]string {
 	})
 
 	return metadata
-}
\ No newline at end of file
+}
+// getMicrodata extracts schema.org microdata from the first article item in
+// the document (an element with itemscope and an article itemtype). The keys
+// match those of getJSONLD so the two sources can be merged.
+func (r *Readability) getMicrodata() map[string]string {
+	metadata := make(map[string]string)
+
+	item := r.doc.Find("[itemscope][itemtype]").FilterFunction(func(i int, s *goquery.Selection) bool {
+		itemType := strings.TrimSpace(s.AttrOr("itemtype", ""))
+		if !strings.Contains(itemType, "schema.org") {
+			return false
+		}
+		itemType = itemType[strings.LastIndex(itemType, "/")+1:]
+		return RegexpJsonLdArticleTypes.MatchString(itemType)
+	}).First()
+	if item.Length() == 0 {
+		return metadata
+	}
+
+	if headline := item.Find(`[itemprop~="headline"]`).First(); headline.Length() > 0 {
+		metadata["title"] = microdataValue(headline)
+	}
+
+	if author := item.Find(`[itemprop~="author"]`).First(); author.Length() > 0 {
+		// A nested Person or Organization item carries the name in its own property
+		if name := author.Find(`[itemprop~="name"]`).First(); name.Length() > 0 {
+			author = name
+		}
+		metadata["byline"] = microdataValue(author)
+	}
+
+	if description := item.Find(`[itemprop~="description"]`).First(); description.Length() > 0 {
+		metadata["excerpt"] = microdataValue(description)
+	}
+
+	for _, prop := range []string{"datePublished", "dateCreated", "dateModified"} {
+		if date := item.Find(`[itemprop~="` + prop + `"]`).First(); date.Length() > 0 {
+			metadata["date"] = microdataValue(date)
+			break
+		}
+	}
+
+	// Drop properties that were present but empty
+	for key, value := range metadata {
+		if value == "" {
+			delete(metadata, key)
+		}
+	}
+
+	return metadata
+}
+
+// microdataValue returns the value of a microdata property element: the content
+// attribute when present, the datetime of a <time>, or else its text
+func microdataValue(s *goquery.Selection) string {
+	if content, exists := s.Attr("content"); exists {
+		return strings.TrimSpace(content)
+	}
+	if goquery.NodeName(s) == "time" {
+		if datetime, exists := s.Attr("datetime"); exists {
+			return strings.TrimSpace(datetime)
+		}
+	}
+	return getNormalized(s.Text())
+}
+
+// getMicrodataArticleBody returns the element marked itemprop="articleBody",
+// or nil when there is none or it holds too little text to be the article
+func (r *Readability) getMicrodataArticleBody() *goquery.Selection {
+	body := r.doc.Find(`body [itemprop~="articleBody"]`).First()
+	if body.Length() == 0 || len(getInnerText(body, true)) < MinContentTextLength {
+		return nil
+	}
+	return body
+}
+
+// MinTitleSimilarity is the token similarity at which two titles are taken to
+// name the same article
+const MinTitleSimilarity = 0.8
+
+// isConfidentTitle reports whether the resolved title can be trusted: it must
+// match a strong content heading (an h1 with itemprop="headline", or the only
+// h1 of the article or main element), or agree with at least two of the title
+// sources of the page: the JSON-LD headline, the og:title, twitter:title and
+// dc:title meta tags together, the content h1s, the ARIA label of the article,
+// and the <title>. A page with only a generic <title> has a single source and is not
+// confident.
+func (r *Readability) isConfidentTitle(title string, jsonLd map[string]string) bool {
+	title = getNormalized(title)
+	if title == "" {
+		return false
+	}
+
+	h1s := r.doc.Find("h1").FilterFunction(func(i int, s *goquery.Selection) bool {
+		return s.Closest(`article, main, [role="main"], [itemprop~="articleBody"]`).Length() > 0
+	})
+	strong := r.doc.Find(`h1[itemprop="headline"]`).First()
+	if strong.Length() == 0 && h1s.Length() == 1 {
+		strong = h1s
+	}
+	if strong.Length() > 0 && titlesAgree(title, r.headingText(strong)) {
+		return true
+	}
+
+	// Each source is a list of texts, any of which agreeing counts once: the
+	// meta tags are usually copied from one field, and a page can have
+	// several h1s
+	var metas, headings []string
+	for _, name := range []string{"og:title", "twitter:title", "dc:title", "dcterm:title"} {
+		meta := r.doc.Find(fmt.Sprintf(`meta[property=%q], meta[name=%q]`, name, name)).First()
+		metas = append(metas, meta.AttrOr("content", ""))
+	}
+	if h1s.Length() == 0 {
+		h1s = r.doc.Find("h1")
+	}
+	h1s.Each(func(i int, s *goquery.Selection) {
+		headings = append(headings, r.headingText(s))
+	})
+	sources := [][]string{
+		{jsonLd["title"]},
+		metas,
+		headings,
+		{r.getARIATitle()},
+		{r.doc.Find("head title").First().Text()},
+	}
+
+	agreeing := 0
+	for _, texts := range sources {
+		for _, text := range texts {
+			text = getNormalized(unescapeHtmlEntities(text))
+			if text != "" && titlesAgree(title, text) {
+				agreeing++
+				break
+			}
+		}
+	}
+	return agreeing >= 2
+}
+
+// titlesAgree reports whether two titles name the same article: they are equal
+// ignoring case, the longer contains the shorter of at least two words (as a
+// <title> holds the headline and the site name), or their tokens are at least MinTitleSimilarity similar
+func titlesAgree(a, b string) bool {
+	a = strings.ToLower(getNormalized(a))
+	b = strings.ToLower(getNormalized(b))
+	if a == "" || b == "" {
+		return false
+	}
+	shorter, longer := a, b
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+	if wordCount(shorter) >= 2 && strings.Contains(longer, shorter) {
+		return true
+	}
+	return textSimilarity(a, b) >= MinTitleSimilarity
+}
+
+// quoteAttributionAttr is the attribute on which labelQuoteAttributions records
+// the attribution of a quotation until the quotations are indexed
+const quoteAttributionAttr = "data-readability-attribution"
+
+// quoteAttributionDashRe matches the dash that introduces an attribution, as in
+// "— Jane Doe"
+var quoteAttributionDashRe = regexp.MustCompile(`^[\p{Pd}―~]+\s*`)
+
+// labelQuoteAttributions records the attribution of each <blockquote> on the
+// element, since the <footer> that usually holds it is removed during cleanup.
+// The attribution is the text of a <cite> element in the quote, or else the
+// <figcaption> of a <figure> holding only the quote, without a leading dash.
+func (r *Readability) labelQuoteAttributions() {
+	r.doc.Find("blockquote").Each(func(i int, quote *goquery.Selection) {
+		attribution := getNormalized(quote.Find("cite").First().Text())
+		if attribution == "" {
+			if figure := quote.Parent(); figure.Is("figure") && figure.ChildrenFiltered("blockquote").Length() == 1 {
+				attribution = getNormalized(figure.ChildrenFiltered("figcaption").First().Text())
+			}
+		}
+		attribution = quoteAttributionDashRe.ReplaceAllString(attribution, "")
+		if attribution != "" {
+			quote.SetAttr(quoteAttributionAttr, attribution)
+		}
+	})
+}
+
+// getQuotes lists the <blockquote> and <q> elements of the article in document
+// order, with their text, absolute cite URL and the attribution recorded by
+// labelQuoteAttributions, which is removed from the element. Quotations nested
+// in a listed blockquote are part of it.
+func (r *Readability) getQuotes(article *goquery.Selection) []Quote {
+	var quotes []Quote
+	article.Find("blockquote, q").Each(func(i int, quote *goquery.Selection) {
+		attribution := quote.AttrOr(quoteAttributionAttr, "")
+		quote.RemoveAttr(quoteAttributionAttr)
+		if quote.ParentsFiltered("blockquote").Length() > 0 {
+			return
+		}
+
+		text := quote.Clone()
+		text.Find("cite, footer").Remove()
+		result := Quote{
+			Text:        getNormalized(text.Text()),
+			Attribution: attribution,
+		}
+		if result.Text == "" {
+			return
+		}
+		if cite := strings.TrimSpace(quote.AttrOr("cite", "")); cite != "" {
+			result.CiteURL = r.resolveDocumentURL(cite)
+		}
+		quotes = append(quotes, result)
+	})
+	return quotes
+}
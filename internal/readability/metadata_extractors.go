@@ -0,0 +1,86 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// MetadataExtractor extracts metadata fields from a document. Extractors see the
+// document before any cleanup, so scripts and hidden elements are still present.
+type MetadataExtractor interface {
+	Extract(doc *goquery.Document) map[string]string
+}
+
+// OpenGraphExtractor extracts metadata from OpenGraph meta tags
+type OpenGraphExtractor struct{}
+
+// openGraphKeys maps OpenGraph properties to metadata keys
+var openGraphKeys = map[string]string{
+	"og:title":               "title",
+	"og:description":         "excerpt",
+	"og:site_name":           "siteName",
+	"og:image":               "image",
+	"og:url":                 "url",
+	"og:type":                "type",
+	"article:published_time": "date",
+	"article:author":         "byline",
+}
+
+// Extract implements MetadataExtractor
+func (OpenGraphExtractor) Extract(doc *goquery.Document) map[string]string {
+	metadata := make(map[string]string)
+	doc.Find("meta[property][content]").Each(func(_ int, s *goquery.Selection) {
+		key, ok := openGraphKeys[strings.ToLower(strings.TrimSpace(s.AttrOr("property", "")))]
+		if !ok || metadata[key] != "" {
+			return
+		}
+		if content := strings.TrimSpace(s.AttrOr("content", "")); content != "" {
+			metadata[key] = content
+		}
+	})
+	return metadata
+}
+
+// JSONLDExtractor extracts article metadata from JSON-LD script blocks
+type JSONLDExtractor struct{}
+
+// Extract implements MetadataExtractor
+func (JSONLDExtractor) Extract(doc *goquery.Document) map[string]string {
+	return NewFromDocument(doc, nil).getJSONLD()
+}
+
+// MicrodataExtractor extracts article metadata from schema.org microdata
+type MicrodataExtractor struct{}
+
+// Extract implements MetadataExtractor
+func (MicrodataExtractor) Extract(doc *goquery.Document) map[string]string {
+	return NewFromDocument(doc, nil).getMicrodata()
+}
+
+// extractMetadata runs the built-in extractors followed by the custom ones and
+// merges their results. A later extractor overrides a key set by an earlier one,
+// so custom extractors take precedence over the built-in sources.
+func (r *Readability) extractMetadata() map[string]string {
+	metadata := make(map[string]string)
+	merge := func(values map[string]string) {
+		for key, value := range values {
+			if value != "" {
+				metadata[key] = value
+			}
+		}
+	}
+
+	merge(OpenGraphExtractor{}.Extract(r.doc))
+	merge(r.getMicrodata())
+	if !r.options.DisableJSONLD {
+		merge(r.getJSONLD())
+	}
+	for _, extractor := range r.options.MetadataExtractors {
+		if extractor != nil {
+			merge(extractor.Extract(r.doc))
+		}
+	}
+
+	return metadata
+}
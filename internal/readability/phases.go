@@ -0,0 +1,52 @@
+package readability
+
+import "time"
+
+// Phase is a stage of the extraction that can be given its own time budget
+type Phase int
+
+// Extraction phases, in the order they run
+const (
+	PhaseParse   Phase = iota // Parsing the HTML into a document
+	PhaseExtract              // Finding, scoring and cleaning up the article content
+	PhaseText                 // Rendering PlainContent and PlainText from the content
+)
+
+// String returns the name of the phase
+func (p Phase) String() string {
+	switch p {
+	case PhaseParse:
+		return "parse"
+	case PhaseExtract:
+		return "extract"
+	case PhaseText:
+		return "text"
+	default:
+		return "unknown"
+	}
+}
+
+// runPhase runs fn as the given phase, returning a PhaseTimeoutError once the
+// budget for the phase is spent. Phases without a budget run to completion. A
+// phase that runs over is left to finish in the background, so fn must not
+// change anything the caller reads after a timeout.
+func runPhase(phase Phase, budgets map[Phase]time.Duration, fn func()) error {
+	budget := budgets[phase]
+	if budget <= 0 {
+		fn()
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(budget):
+		return &PhaseTimeoutError{Phase: phase, Budget: budget}
+	}
+}
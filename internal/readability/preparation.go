@@ -1,15 +1,23 @@
 package readability
 
 import (
+	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/mrjoshuak/readabiligo/internal/simplifiers"
+	"golang.org/x/net/html"
 )
 
 // prepArticle prepares the article node for display
 func (r *Readability) prepArticle(articleContent *goquery.Selection) {
+	// Only the links removed by the last attempt are reported
+	r.resetRemovedLinks()
+
 	// Clean styles
 	r.cleanStyles(articleContent)
 
@@ -19,6 +27,21 @@ func (r *Readability) prepArticle(articleContent *goquery.Selection) {
 	// Fix lazy-loaded images
 	r.fixLazyImages(articleContent)
 
+	// Keep the opening paragraphs through conditional cleaning (if requested)
+	if r.options.ProtectLeadParagraphs > 0 {
+		r.protectLeadParagraphs(articleContent)
+	}
+
+	// Keep the login or sign-up form of minimal pages, which is their content,
+	// along with the container giving it context
+	if r.contentType == ContentTypeMinimal {
+		if container := findMainFormContainer(articleContent); container != nil {
+			r.logEvent("prepare", "preserve form of minimal page", container)
+			container.AddClass("readability-preserve")
+			container.Find("form").AddClass("readability-preserve")
+		}
+	}
+
 	// IMPORTANT: Remove indexterm and noteref links
 	// These are technical metadata that Mozilla's implementation removes
 	// Critical for technical content comparison tests
@@ -49,7 +72,7 @@ func (r *Readability) prepArticle(articleContent *goquery.Selection) {
 
 	// Clean elements with share buttons
 	articleContent.Children().Each(func(i int, child *goquery.Selection) {
-		r.cleanMatchedNodes(child, func(node *goquery.Selection, matchString string) bool {
+		r.cleanMatchedNodes(child, "share", func(node *goquery.Selection, matchString string) bool {
 			return RegexpShareElements.MatchString(matchString) &&
 				len(getInnerText(node, true)) < r.options.CharThreshold
 		})
@@ -97,6 +120,22 @@ func (r *Readability) prepArticle(articleContent *goquery.Selection) {
 	// flattenNestedLayoutTables function in cleanup.go
 }
 
+// protectLeadParagraphs adds the readability-preserve class to the first
+// ProtectLeadParagraphs paragraphs of the article with at least
+// MinContentTextLength characters, which conditional cleaning then keeps along
+// with the containers holding them
+func (r *Readability) protectLeadParagraphs(articleContent *goquery.Selection) {
+	protected := 0
+	articleContent.Find("p").EachWithBreak(func(i int, p *goquery.Selection) bool {
+		if len(getNormalized(p.Text())) < MinContentTextLength {
+			return true
+		}
+		p.AddClass("readability-preserve")
+		protected++
+		return protected < r.options.ProtectLeadParagraphs
+	})
+}
+
 // prepDocument prepares the document for readability to scrape it
 func (r *Readability) prepDocument() {
 	// Remove all style tags in head
@@ -111,11 +150,335 @@ func (r *Readability) prepDocument() {
 	r.doc.Find("font").Each(func(i int, s *goquery.Selection) {
 		setNodeTag(s, "SPAN")
 	})
+
+	// Remove permalink anchors from headings
+	if r.options.StripHeaderAnchors {
+		r.removeHeaderAnchors()
+	}
+
+	// Remove leftover advertisement labels
+	if r.options.TrimBoilerplateHeadings {
+		r.removeBoilerplateLabels()
+	}
+
+	// Show collapsible content as ordinary sections
+	if r.options.ExpandDetails {
+		simplifiers.ExpandDetails(r.doc.Selection)
+	}
+}
+
+// removeHeaderAnchors removes the permalink anchors that documentation sites
+// add to headings, such as <a class="headerlink" href="#id">¶</a>. Anchors are
+// recognized by a permalink class or a fragment-only href. Symbol-only anchors
+// are removed; anchors wrapping the heading text are unwrapped to keep the text.
+func (r *Readability) removeHeaderAnchors() {
+	r.doc.Find("h1 a, h2 a, h3 a, h4 a, h5 a, h6 a").Each(func(i int, a *goquery.Selection) {
+		href := strings.TrimSpace(a.AttrOr("href", ""))
+		if !RegexpHeaderAnchorClass.MatchString(a.AttrOr("class", "")) && !strings.HasPrefix(href, "#") {
+			return
+		}
+
+		text := a.Text()
+		if strings.IndexFunc(text, func(c rune) bool { return unicode.IsLetter(c) || unicode.IsDigit(c) }) >= 0 {
+			a.Contents().Unwrap()
+			return
+		}
+		a.Remove()
+	})
+}
+
+// removeBoilerplateLabels removes the headings and blocks whose entire text is
+// one of the BoilerplateLabels, such as "Advertisement" or "Sponsored Content",
+// left behind when the ad they labelled was removed. The text is compared
+// without case and surrounding punctuation; blocks holding media are kept.
+func (r *Readability) removeBoilerplateLabels() {
+	if len(r.options.BoilerplateLabels) == 0 {
+		return
+	}
+	labels := make(map[string]bool, len(r.options.BoilerplateLabels))
+	for _, label := range r.options.BoilerplateLabels {
+		labels[strings.ToLower(getNormalized(label))] = true
+	}
+
+	r.doc.Find("h1, h2, h3, h4, h5, h6, p, div, section, aside, figcaption, small").Each(func(i int, block *goquery.Selection) {
+		text := strings.TrimFunc(getNormalized(block.Text()), func(c rune) bool {
+			return unicode.IsPunct(c) || unicode.IsSpace(c) || unicode.IsSymbol(c)
+		})
+		if !labels[strings.ToLower(text)] {
+			return
+		}
+		if block.Find("img, picture, video, iframe, embed, object, svg").Length() > 0 {
+			return
+		}
+		block.Remove()
+	})
+}
+
+// promoteTemplateContent unwraps <template> elements whose content should be
+// treated as part of the document. Declarative shadow roots are always rendered
+// by browsers, so they are unwrapped unconditionally. Ordinary templates are
+// inert, so only the template with the most article-like text is promoted, and
+// only when the visible document is too sparse to contain the article itself.
+func (r *Readability) promoteTemplateContent() {
+	// Declarative shadow DOM is part of the rendered page
+	r.doc.Find("template[shadowrootmode], template[shadowroot]").Each(func(i int, tmpl *goquery.Selection) {
+		tmpl.Contents().Unwrap()
+	})
+
+	templates := r.doc.Find("template")
+	if templates.Length() == 0 {
+		return
+	}
+
+	// Measure the text that is visible without any template content
+	body := r.doc.Find("body").First()
+	if body.Length() == 0 {
+		body = r.doc.Selection
+	}
+	visible := body.Clone()
+	visible.Find("template, script, style, noscript").Remove()
+	if len(getNormalized(visible.Text())) >= r.options.CharThreshold {
+		return
+	}
+
+	// Pick the template with the most article-like content
+	var best *goquery.Selection
+	bestLength := 0
+	templates.Each(func(i int, tmpl *goquery.Selection) {
+		if tmpl.Find("p").Length() == 0 {
+			return
+		}
+		length := len(getNormalized(tmpl.Text()))
+		if length >= r.options.CharThreshold && length > bestLength {
+			best = tmpl
+			bestLength = length
+		}
+	})
+
+	if best != nil {
+		r.logEvent("prepare", "promote template content", best, "length", bestLength)
+		best.Contents().Unwrap()
+	}
+}
+
+// promoteNoscriptContent replaces a <noscript> element with the HTML it holds
+// when the page renders its article with JavaScript and keeps a static copy in
+// <noscript> for clients without it. Only the <noscript> with the most
+// article-like content is promoted, and only when the visible document is too
+// sparse to contain the article itself.
+func (r *Readability) promoteNoscriptContent() {
+	noscripts := r.doc.Find("body noscript")
+	if noscripts.Length() == 0 {
+		return
+	}
+
+	// Measure the text that is visible without any noscript content
+	body := r.doc.Find("body").First()
+	visible := body.Clone()
+	visible.Find("template, script, style, noscript").Remove()
+	if len(getNormalized(visible.Text())) >= r.options.CharThreshold {
+		return
+	}
+
+	// Pick the noscript with the most article-like content. With scripting
+	// enabled, the parser keeps noscript content as raw text, so it is parsed here.
+	var best, bestContent *goquery.Selection
+	bestLength := 0
+	noscripts.Each(func(i int, noscript *goquery.Selection) {
+		content := noscript.Contents()
+		if noscript.Children().Length() == 0 {
+			fragment, err := goquery.NewDocumentFromReader(strings.NewReader(simplifiers.WrapFragment(noscript.Text())))
+			if err != nil {
+				return
+			}
+			content = fragment.Find("body").Contents()
+		}
+		if content.Filter("p").Length()+content.Find("p").Length() == 0 {
+			return
+		}
+		length := len(getNormalized(content.Text()))
+		if length >= r.options.CharThreshold && length > bestLength {
+			best, bestContent = noscript, content
+			bestLength = length
+		}
+	})
+
+	if best != nil {
+		r.logEvent("prepare", "promote noscript content", best, "length", bestLength)
+		best.ReplaceWithSelection(bestContent)
+	}
+}
+
+// foreignLanguageBlockSelector matches the block-level elements that
+// removeForeignLanguageContent removes when they declare another language
+const foreignLanguageBlockSelector = "article, aside, blockquote, div, dl, figure, footer, " +
+	"h1, h2, h3, h4, h5, h6, header, li, nav, ol, p, pre, section, table, ul"
+
+// removeForeignLanguageContent removes body block-level elements whose lang
+// attribute conflicts with the configured content language, such as
+// alternate-language navigation on a multilingual page. Languages are compared
+// by their primary subtag, so "en-GB" matches "en". Inline elements, like a
+// foreign phrase in a sentence, and elements without a lang attribute, which
+// inherit the document language, are kept.
+func (r *Readability) removeForeignLanguageContent() {
+	want := primaryLanguage(r.options.ContentLanguage)
+	if want == "" {
+		return
+	}
+
+	r.doc.Find("body [lang]").Filter(foreignLanguageBlockSelector).Each(func(i int, s *goquery.Selection) {
+		lang := primaryLanguage(s.AttrOr("lang", ""))
+		if lang == "" || lang == want {
+			return
+		}
+		r.logEvent("prepare", "remove foreign language content", s, "lang", s.AttrOr("lang", ""))
+		s.Remove()
+	})
+}
+
+// removeHiddenContent removes body elements that are hidden from readers with
+// the hidden attribute, aria-hidden="true", or an inline display:none or
+// visibility:hidden style. Such text is usually SEO keyword stuffing or
+// clipboard-protection filler rather than part of the article. Hidden elements
+// containing images are kept, as are the "fallback-image" elements that
+// isNodeVisible also exempts.
+func (r *Readability) removeHiddenContent() {
+	r.doc.Find("body [hidden], body [aria-hidden], body [style]").Each(func(i int, s *goquery.Selection) {
+		if !isHiddenElement(s) {
+			return
+		}
+		if s.HasClass("fallback-image") || s.Is("img, picture, video") || s.Find("img, picture, video").Length() > 0 {
+			return
+		}
+		r.logEvent("prepare", "remove hidden content", s)
+		s.Remove()
+	})
+}
+
+// consentBannerSelector matches the containers of common cookie consent banners
+const consentBannerSelector = "#onetrust-consent-sdk, #onetrust-banner-sdk, #CybotCookiebotDialog, " +
+	"#cookie-law-info-bar, #cookie-notice, #cookie-banner, #cookie-consent, .cookie-consent, " +
+	".cookie-banner, .cookie-notice, .cookie-bar, .cc-window, .qc-cmp2-container, [aria-label*=cookie i]"
+
+// consentPhrases are phrases typical of cookie consent banners
+var consentPhrases = []string{
+	"we use cookies", "this site uses cookies", "this website uses cookies", "accept cookies",
+	"accept all", "allow all cookies", "reject all", "cookie settings", "cookie preferences",
+	"manage cookies", "manage consent",
+}
+
+// consentBannerMaxTextLength is the longest text a consent banner found by its
+// phrases may have, so that articles about cookies are not mistaken for one
+const consentBannerMaxTextLength = 600
+
+// removeConsentBanners removes cookie and GDPR consent banners, which otherwise
+// tend to survive as a leading block of the content. Banners are found by the
+// ids and classes of common consent tools, and by their text: a short element
+// using two consent phrases ("we use cookies", "accept all"), or one phrase
+// when it is a fixed-position overlay or a dialog, or its id or class mentions
+// cookies or consent.
+func (r *Readability) removeConsentBanners() {
+	remove := func(s *goquery.Selection) {
+		r.logEvent("prepare", "remove consent banner", s)
+		s.Remove()
+	}
+
+	r.doc.Find("body").Find(consentBannerSelector).Each(func(i int, s *goquery.Selection) {
+		remove(s)
+	})
+
+	r.doc.Find("body div, body section, body aside, body dialog, body form").Each(func(i int, s *goquery.Selection) {
+		// Skip elements already removed with a banner around them
+		if s.ParentsFiltered("body").Length() == 0 {
+			return
+		}
+
+		text := strings.ToLower(strings.Join(strings.Fields(s.Text()), " "))
+		if text == "" || len(text) > consentBannerMaxTextLength {
+			return
+		}
+		phrases := 0
+		for _, phrase := range consentPhrases {
+			if strings.Contains(text, phrase) {
+				phrases++
+			}
+		}
+		if phrases == 0 {
+			return
+		}
+
+		if phrases >= 2 || isOverlayElement(s) {
+			remove(s)
+		}
+	})
+}
+
+// isOverlayElement reports whether an element is a fixed-position overlay, a
+// dialog, or named after cookies or consent
+func isOverlayElement(s *goquery.Selection) bool {
+	style := strings.ToLower(strings.Join(strings.Fields(s.AttrOr("style", "")), ""))
+	if strings.Contains(style, "position:fixed") || strings.Contains(style, "position:sticky") {
+		return true
+	}
+	if s.Is("dialog") {
+		return true
+	}
+	if role := strings.ToLower(s.AttrOr("role", "")); role == "dialog" || role == "alertdialog" {
+		return true
+	}
+	names := strings.ToLower(s.AttrOr("id", "") + " " + s.AttrOr("class", ""))
+	return strings.Contains(names, "cookie") || strings.Contains(names, "consent") || strings.Contains(names, "gdpr")
+}
+
+// isHiddenElement reports whether an element is hidden by its attributes or inline style
+func isHiddenElement(s *goquery.Selection) bool {
+	if _, hidden := s.Attr("hidden"); hidden {
+		return true
+	}
+	if strings.EqualFold(strings.TrimSpace(s.AttrOr("aria-hidden", "")), "true") {
+		return true
+	}
+	style := strings.ToLower(strings.Join(strings.Fields(s.AttrOr("style", "")), ""))
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// convertMathScripts replaces the <script type="math/tex"> elements in which
+// MathJax 2 keeps equation source with their LaTeX, between \( \) delimiters or
+// \[ \] for display equations, so the equations survive script removal. The
+// MathJax_Preview placeholders shown while MathJax loads are removed.
+func (r *Readability) convertMathScripts() {
+	r.doc.Find(".MathJax_Preview").Remove()
+	r.doc.Find(`script[type^="math/tex"]`).Each(func(i int, s *goquery.Selection) {
+		tex := strings.TrimSpace(s.Text())
+		if tex == "" {
+			s.Remove()
+			return
+		}
+		opening, closing := `\(`, `\)`
+		if strings.Contains(s.AttrOr("type", ""), "mode=display") {
+			opening, closing = `\[`, `\]`
+		}
+		s.ReplaceWithNodes(&html.Node{Type: html.TextNode, Data: opening + tex + closing})
+	})
+}
+
+// primaryLanguage returns the lowercased primary subtag of a language tag
+func primaryLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
 }
 
 // replaceBrs replaces 2 or more successive <br> elements with a single <p>
 func (r *Readability) replaceBrs(elem *goquery.Selection) {
 	elem.Find("br").Each(func(i int, br *goquery.Selection) {
+		// Runs too short for a paragraph break are reduced to line breaks
+		if r.options.CollapseBreaks && r.collapseShortBrRun(br) {
+			return
+		}
+
 		next := br.Next()
 
 		// Whether 2 or more <br> elements have been found and replaced
@@ -172,6 +535,27 @@ func (r *Readability) replaceBrs(elem *goquery.Selection) {
 	})
 }
 
+// collapseShortBrRun reduces a run of consecutive <br> elements starting at br
+// to at most MaxLineBreaks elements when the run is shorter than the paragraph
+// break threshold. It reports whether the run was handled; longer runs are left
+// for replaceBrs to turn into paragraphs.
+func (r *Readability) collapseShortBrRun(br *goquery.Selection) bool {
+	threshold, maxLineBreaks := simplifiers.BreakLimits(r.options.ParagraphBreakThreshold, r.options.MaxLineBreaks)
+
+	run := []*goquery.Selection{br}
+	for next := br.Next(); next.Length() > 0 && getNodeName(next) == "BR"; next = next.Next() {
+		run = append(run, next)
+	}
+	if len(run) >= threshold {
+		return false
+	}
+
+	for i := maxLineBreaks; i < len(run); i++ {
+		run[i].Remove()
+	}
+	return true
+}
+
 // fixLazyImages fixes lazy-loaded images
 func (r *Readability) fixLazyImages(root *goquery.Selection) {
 	root.Find("img, picture, figure").Each(func(i int, elem *goquery.Selection) {
@@ -237,16 +621,274 @@ func (r *Readability) fixLazyImages(root *goquery.Selection) {
 
 // postProcessContent runs any post-process modifications to article content
 func (r *Readability) postProcessContent(articleContent *goquery.Selection) {
+	// Drop the links and images whose URL scheme isn't allowed
+	r.removeDisallowedSchemes(articleContent)
+
 	// Fix relative URIs
 	r.fixRelativeUris(articleContent)
 
 	// Simplify nested elements
 	r.simplifyNestedElements(articleContent)
 
+	// Turn paragraphs with pasted bullets or numbers into lists (if enabled)
+	if r.options.NormalizeListMarkers {
+		normalizeListMarkers(articleContent)
+	}
+
+	// Caption figures that only describe their image in its alt text (if enabled)
+	if r.options.MergeImageAltIntoCaption {
+		captionFiguresFromAlt(articleContent)
+	}
+
+	// Rejoin lists that were split by removed elements, before classes are cleaned
+	if r.options.MergeListsAcrossParagraphs {
+		r.mergeAdjacentLists(articleContent)
+	}
+
 	// Clean classes if not keeping them
 	if !r.options.KeepClasses {
 		r.cleanClasses(articleContent)
 	}
+
+	// Drop ids generated by frameworks, keeping the ones written by authors
+	if r.options.StripAutogeneratedIDs {
+		r.removeAutogeneratedIDs(articleContent)
+	}
+
+	// Remove links that were left without content
+	if r.options.StripEmptyAnchors {
+		r.removeEmptyAnchors(articleContent)
+	}
+
+	// Make sure preserved ids are valid deep-link targets
+	if r.options.PreserveIDs {
+		r.dedupeIDs(articleContent)
+	}
+}
+
+// mergeAdjacentLists joins sibling lists of the same type that are separated
+// only by whitespace or comments, such as a list that was split in two by an
+// advertisement removed during cleanup. To avoid merging lists that are meant
+// to be distinct, lists are only merged when their class, type and reversed
+// attributes match, the second list has no id, and the second list of an <ol>
+// either has no start attribute or continues the numbering of the first.
+func (r *Readability) mergeAdjacentLists(articleContent *goquery.Selection) {
+	articleContent.Find("ul, ol").Each(func(i int, s *goquery.Selection) {
+		list := s.Get(0)
+		// Lists merged into an earlier list are no longer in the tree
+		if list.Parent == nil {
+			return
+		}
+
+		for {
+			next := nextListSibling(list)
+			if next == nil || !canMergeLists(list, next) {
+				return
+			}
+			for child := next.FirstChild; child != nil; child = next.FirstChild {
+				next.RemoveChild(child)
+				list.AppendChild(child)
+			}
+			next.Parent.RemoveChild(next)
+		}
+	})
+}
+
+// nextListSibling returns the element following a list if only whitespace and
+// comments separate them, or nil if there is meaningful content in between
+func nextListSibling(list *html.Node) *html.Node {
+	for n := list.NextSibling; n != nil; n = n.NextSibling {
+		switch {
+		case n.Type == html.CommentNode:
+			continue
+		case n.Type == html.TextNode && strings.TrimSpace(n.Data) == "":
+			continue
+		case n.Type == html.ElementNode:
+			return n
+		}
+		return nil
+	}
+	return nil
+}
+
+// canMergeLists reports whether the second list continues the first
+func canMergeLists(first, second *html.Node) bool {
+	if second.Data != first.Data {
+		return false
+	}
+
+	attrs := func(n *html.Node) map[string]string {
+		values := make(map[string]string)
+		for _, attr := range n.Attr {
+			values[attr.Key] = attr.Val
+		}
+		return values
+	}
+	a, b := attrs(first), attrs(second)
+
+	if strings.Join(strings.Fields(a["class"]), " ") != strings.Join(strings.Fields(b["class"]), " ") {
+		return false
+	}
+	if b["id"] != "" || a["type"] != b["type"] {
+		return false
+	}
+	if _, reversed := a["reversed"]; reversed {
+		return false
+	}
+	if _, reversed := b["reversed"]; reversed {
+		return false
+	}
+
+	// An explicit start on the second list must continue the first list's numbering
+	if startAttr, ok := b["start"]; ok && first.Data == "ol" {
+		start, err := strconv.Atoi(strings.TrimSpace(startAttr))
+		if err != nil {
+			return false
+		}
+		firstStart := 1
+		if value, ok := a["start"]; ok {
+			if firstStart, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return false
+			}
+		}
+		items := 0
+		for c := first.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "li" {
+				items++
+			}
+		}
+		return start == firstStart+items
+	}
+	return true
+}
+
+// removeEmptyAnchors removes anchors that have no text and contain no images or
+// other media. Named anchors (with an id or name) are kept when ids are preserved,
+// since they may be in-page link targets.
+func (r *Readability) removeEmptyAnchors(articleContent *goquery.Selection) {
+	articleContent.Find("a").Each(func(i int, a *goquery.Selection) {
+		if strings.TrimSpace(a.Text()) != "" {
+			return
+		}
+		if a.Find("img, picture, svg, video, audio, iframe, embed, object, canvas").Length() > 0 {
+			return
+		}
+		if r.options.PreserveIDs && (a.AttrOr("id", "") != "" || a.AttrOr("name", "") != "") {
+			return
+		}
+		a.Remove()
+	})
+}
+
+// removeAutogeneratedIDs removes the id attributes that match any of the
+// AutogeneratedIDPatterns, such as hex hashes and framework ids like
+// "gatsby-focus-wrapper", while human-readable ids like "section-intro" are kept
+func (r *Readability) removeAutogeneratedIDs(articleContent *goquery.Selection) {
+	articleContent.Find("[id]").AddBack().Each(func(i int, s *goquery.Selection) {
+		id := strings.TrimSpace(s.AttrOr("id", ""))
+		if id == "" {
+			return
+		}
+		for _, pattern := range r.options.AutogeneratedIDPatterns {
+			if pattern != nil && pattern.MatchString(id) {
+				s.RemoveAttr("id")
+				return
+			}
+		}
+	})
+}
+
+// dedupeIDs renames repeated id attributes so every id in the article is unique.
+// The first element keeps its id; later duplicates get a numeric suffix
+// ("intro", "intro-2", "intro-3", ...).
+func (r *Readability) dedupeIDs(articleContent *goquery.Selection) {
+	seen := make(map[string]bool)
+	articleContent.Find("[id]").AddBack().Each(func(i int, s *goquery.Selection) {
+		id, exists := s.Attr("id")
+		if !exists || id == "" {
+			return
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			return
+		}
+
+		for n := 2; ; n++ {
+			candidate := fmt.Sprintf("%s-%d", id, n)
+			if !seen[candidate] {
+				seen[candidate] = true
+				s.SetAttr("id", candidate)
+				return
+			}
+		}
+	})
+}
+
+// removeDisallowedSchemes unwraps the links and removes the images whose URL
+// scheme is not in AllowedSchemes, keeping the text of the links. Relative URLs
+// have no scheme and are always kept. javascript: URLs are always removed since
+// they won't work after scripts are removed, and data: URLs are only allowed on
+// images, never on links. An empty AllowedSchemes allows every other scheme.
+func (r *Readability) removeDisallowedSchemes(articleContent *goquery.Selection) {
+	articleContent.Find("a[href]").Each(func(i int, link *goquery.Selection) {
+		scheme := getURLScheme(link.AttrOr("href", ""))
+		if scheme != "data" && r.isAllowedScheme(scheme) {
+			return
+		}
+		if contents := link.Contents(); contents.Length() > 0 {
+			contents.Unwrap()
+		} else {
+			link.Remove()
+		}
+	})
+
+	articleContent.Find("img").Each(func(i int, img *goquery.Selection) {
+		if !r.isAllowedScheme(getURLScheme(img.AttrOr("src", ""))) {
+			img.Remove()
+			return
+		}
+		// Drop a srcset offering any candidate with a disallowed scheme
+		if srcset, exists := img.Attr("srcset"); exists {
+			for _, match := range RegexpSrcsetUrl.FindAllStringSubmatch(srcset, -1) {
+				if !r.isAllowedScheme(getURLScheme(match[1])) {
+					img.RemoveAttr("srcset")
+					break
+				}
+			}
+		}
+	})
+}
+
+// isAllowedScheme checks if URLs with the given lowercase scheme may be kept
+func (r *Readability) isAllowedScheme(scheme string) bool {
+	if scheme == "" {
+		return true
+	}
+	if scheme == "javascript" {
+		return false
+	}
+	if len(r.options.AllowedSchemes) == 0 {
+		return true
+	}
+	for _, allowed := range r.options.AllowedSchemes {
+		if strings.EqualFold(allowed, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// getURLScheme returns the lowercase scheme of a URL, or "" for relative URLs.
+// Like browsers, it ignores surrounding whitespace and any tab or newline
+// inside the URL, so "java\tscript:" is read as javascript.
+func getURLScheme(uri string) string {
+	uri = strings.TrimSpace(strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(uri))
+	match := RegexpURLScheme.FindStringSubmatch(uri)
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(match[1])
 }
 
 // fixRelativeUris converts relative URIs to absolute ones
@@ -284,6 +926,11 @@ func (r *Readability) fixRelativeUris(articleContent *goquery.Selection) {
 			return uri
 		}
 
+		// Keep in-page anchors relative when ids are preserved for deep-linking
+		if r.options.PreserveIDs && strings.HasPrefix(uri, "#") {
+			return uri
+		}
+
 		// Otherwise, resolve against base URI
 		base, err := url.Parse(baseURI)
 		if err != nil {
@@ -305,22 +952,14 @@ func (r *Readability) fixRelativeUris(articleContent *goquery.Selection) {
 			return
 		}
 
-		// Remove javascript: URIs since they won't work after scripts are removed
-		if strings.HasPrefix(href, "javascript:") {
-			// If the link has only text content, convert to text node
-			if link.Children().Length() == 0 {
-				link.ReplaceWithHtml(link.Text())
-			} else {
-				// Replace with a <span> to preserve children
-				span := r.createElement("span")
-				link.ReplaceWithSelection(span)
-				link.Children().Each(func(i int, child *goquery.Selection) {
-					span.AppendSelection(child)
-				})
-			}
-		} else {
-			// Convert to absolute URI
-			link.SetAttr("href", toAbsoluteURI(href))
+		// Convert to absolute URI
+		link.SetAttr("href", toAbsoluteURI(href))
+	})
+
+	// Fix quotation and edit sources
+	articleContent.Find("blockquote[cite], q[cite], del[cite], ins[cite]").Each(func(i int, quote *goquery.Selection) {
+		if cite := strings.TrimSpace(quote.AttrOr("cite", "")); cite != "" {
+			quote.SetAttr("cite", toAbsoluteURI(cite))
 		}
 	})
 
@@ -0,0 +1,83 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PullQuoteMode selects what extraction does with pull-quotes, the enlarged
+// restatements of a sentence of the article that magazine layouts set apart
+type PullQuoteMode int
+
+// Pull-quote modes
+const (
+	PullQuoteKeep    PullQuoteMode = iota // Leave pull-quotes in the content
+	PullQuoteRemove                       // Remove pull-quotes from the content
+	PullQuoteExtract                      // Remove pull-quotes from the content and list their text
+)
+
+const (
+	// pullQuoteClassSelector matches the elements marked as pull-quotes by
+	// their class
+	pullQuoteClassSelector = `.pullquote, .pull-quote, .pull_quote, .pullQuote, .PullQuote`
+
+	// pullQuoteDuplicateSelector matches the elements that are pull-quotes when
+	// they repeat text of the article
+	pullQuoteDuplicateSelector = `aside, blockquote`
+
+	// MaxPullQuoteLength is the maximum text length of an aside or blockquote
+	// taken for a pull-quote
+	MaxPullQuoteLength = 300
+
+	// MinPullQuoteWords is the minimum number of words an aside or blockquote
+	// must share with the article to be taken for a pull-quote
+	MinPullQuoteWords = 4
+)
+
+// pullQuoteMarks are the quotation marks trimmed from the text of a pull-quote
+const pullQuoteMarks = `"'“”‘’«»„`
+
+// removePullQuotes removes the pull-quotes of the page and returns their text
+// in document order. Pull-quotes are elements with a pull-quote class, and
+// asides and blockquotes of at most MaxPullQuoteLength characters whose words
+// appear again elsewhere in the page. Pull-quotes nested in another one are
+// part of it.
+func (r *Readability) removePullQuotes() []string {
+	body := r.doc.Find("body")
+	bodyText := " " + strings.Join(tokenize(body.Text()), " ") + " "
+
+	var pullQuotes []string
+	var found []*goquery.Selection
+	body.Find(pullQuoteClassSelector + ", " + pullQuoteDuplicateSelector).Each(func(i int, s *goquery.Selection) {
+		for _, outer := range found {
+			if outer.Contains(s.Get(0)) {
+				return
+			}
+		}
+
+		text := strings.Trim(getNormalized(s.Text()), pullQuoteMarks+" ")
+		if text == "" {
+			return
+		}
+		if !s.Is(pullQuoteClassSelector) {
+			words := tokenize(text)
+			if len(text) > MaxPullQuoteLength || len(words) < MinPullQuoteWords {
+				return
+			}
+			// The element's own text is one occurrence, so a repeat needs two
+			if strings.Count(bodyText, " "+strings.Join(words, " ")+" ") < 2 {
+				return
+			}
+		}
+
+		r.logEvent("cleanup", "remove pull-quote", s)
+		found = append(found, s)
+		pullQuotes = append(pullQuotes, text)
+	})
+
+	for _, s := range found {
+		s.Remove()
+	}
+	return pullQuotes
+}
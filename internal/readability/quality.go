@@ -0,0 +1,104 @@
+package readability
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Thresholds of the extraction quality self-test
+const (
+	// MinTextToMarkupRatio is the share of the article HTML that should be text.
+	// Content below it is mostly tags, such as a navigation list or an empty
+	// container picked by mistake.
+	MinTextToMarkupRatio = 0.1
+
+	// MinBodyTextCoverage is the share of the page's body text the article
+	// should hold. Below it, the article is likely a small box beside the real
+	// content.
+	MinBodyTextCoverage = 0.05
+
+	// MaxMojibakeRatio is the share of the article text's characters that may
+	// be garbled before the text is reported as likely mis-decoded
+	MaxMojibakeRatio = 0.005
+
+	// MinMojibakeSequences is the number of garbled sequences needed for the
+	// report, so a single stray symbol in a short text isn't enough
+	MinMojibakeSequences = 3
+)
+
+// mojibakeRe matches UTF-8 text decoded as Latin-1 or Windows-1252: the lead
+// byte of a two-byte sequence read as "Ã" or "Â" followed by a continuation
+// byte, such as "Ã©" for "é", and the three-byte punctuation read as "â€" and a
+// third character, such as "â€™" for "’". Replacement characters left by
+// invalid bytes are matched as well.
+var mojibakeRe = regexp.MustCompile(`[ÃÂ][\x{80}-\x{BF}€‚ƒ„…†‡ˆ‰Š‹ŒŽ‘’“”•–—˜™š›œžŸ]|â€.|\x{FFFD}`)
+
+// getBodyTextLength returns the length of the normalized text of the page body
+func (r *Readability) getBodyTextLength() int {
+	body := r.doc.Find("body").Clone()
+	body.Find("style, template").Remove()
+	return len(getNormalized(body.Text()))
+}
+
+// assessExtraction checks that the extracted article looks like real content:
+// that its HTML is not mostly markup and that it holds more than a tiny
+// fraction of the page's body text. It returns a confidence between 0 and 1,
+// lowered in proportion to how far a check falls short of its threshold, and a
+// warning for each failed check. The coverage check is skipped when the body
+// text length is unknown.
+func assessExtraction(article *goquery.Selection, content string, bodyTextLength int) (float64, []string) {
+	textLength := len(getNormalized(article.Text()))
+	confidence := 1.0
+	var warnings []string
+
+	if len(content) > 0 {
+		ratio := float64(textLength) / float64(len(content))
+		if ratio < MinTextToMarkupRatio {
+			confidence = math.Min(confidence, ratio/MinTextToMarkupRatio)
+			warnings = append(warnings, fmt.Sprintf(
+				"content is mostly markup: text is %.0f%% of the HTML", ratio*100))
+		}
+	}
+
+	if bodyTextLength > 0 {
+		coverage := float64(textLength) / float64(bodyTextLength)
+		if coverage < MinBodyTextCoverage {
+			confidence = math.Min(confidence, coverage/MinBodyTextCoverage)
+			warnings = append(warnings, fmt.Sprintf(
+				"content holds only %.0f%% of the page text", coverage*100))
+		}
+	}
+
+	return confidence, warnings
+}
+
+// mojibakeWarning returns a warning when the article text looks garbled by a
+// wrong character encoding, as when a page declares ISO-8859-1 but is written
+// in UTF-8: when garbled sequences, replacement characters and control
+// characters other than whitespace make up more than MaxMojibakeRatio of the
+// text. It returns "" for clean text.
+func mojibakeWarning(text string) string {
+	matches := mojibakeRe.FindAllString(text, -1)
+	suspicious := len(matches)
+	runes := 0
+	for _, c := range text {
+		runes++
+		if unicode.IsControl(c) && !unicode.IsSpace(c) {
+			suspicious++
+		}
+	}
+	if suspicious < MinMojibakeSequences || float64(suspicious)/float64(runes) <= MaxMojibakeRatio {
+		return ""
+	}
+
+	example := ""
+	if len(matches) > 0 {
+		example = fmt.Sprintf(" such as %q", matches[0])
+	}
+	return fmt.Sprintf("text looks garbled: %d suspicious characters%s, "+
+		"the page may have been decoded with the wrong character encoding", suspicious, example)
+}
@@ -2,17 +2,21 @@ package readability
 
 import (
 	"fmt"
+	"log/slog"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/mrjoshuak/readabiligo/internal/extractors"
+	"github.com/mrjoshuak/readabiligo/internal/simplifiers"
 	"golang.org/x/net/html"
 )
 
 // ReadabilityOptions defines configuration options for the Readability parser
 type ReadabilityOptions struct {
-	Debug                bool     // Debug mode
+	Debug                bool     // Debug mode, logging debug events to stderr unless a Logger is set
+	Logger               *slog.Logger // Logger of debug events, such as the elements removed during cleanup (nil = none)
 	MaxElemsToParse      int      // Maximum elements to parse (0 = no limit)
 	NbTopCandidates      int      // Number of top candidates to consider
 	CharThreshold        int      // Minimum character threshold
@@ -21,8 +25,67 @@ type ReadabilityOptions struct {
 	DisableJSONLD        bool     // Whether to disable JSON-LD processing
 	AllowedVideoRegex    *regexp.Regexp // Regex for allowed videos
 	PreserveImportantLinks bool     // Whether to preserve important links like "More information..." in cleaned elements
-	DetectContentType    bool     // Whether to enable content type detection
-	ContentType          ContentType // Content type to use for extraction (or auto-detected if DetectContentType is true)
+	ContentType          ContentType // Content type assigned to every page (ContentTypeArticle when unknown)
+	ContentTypeRules     []ContentTypeRule // Selector rules that override the content type; the first match wins
+	ExtractTemplates     bool     // Whether to promote article-like <template> content when the visible DOM is sparse
+	UseNoscriptFallback  bool     // Whether to promote article-like <noscript> content when the visible DOM is sparse
+	PreserveIDs          bool     // Whether to keep element ids usable for in-page deep links
+	StripAutogeneratedIDs bool    // Whether to remove ids matching AutogeneratedIDPatterns
+	AutogeneratedIDPatterns []*regexp.Regexp // Patterns of ids generated by frameworks rather than authors
+	ContentLanguage      string   // Primary language of the content; body elements declaring another lang are removed
+	ExtractAuthorImage   bool     // Whether to extract the author's profile image URL
+	ExtractThemeColor    bool     // Whether to extract the page's theme color
+	ExtractAlternates    bool     // Whether to list the page's feeds, AMP version and other alternates
+	StripHiddenText      bool     // Whether to remove hidden elements (display:none, hidden, aria-hidden) before extraction
+	StripConsentBanners  bool     // Whether to remove cookie and GDPR consent banners before extraction
+	StripBylineFromContent bool   // Whether to remove the byline element from the content once captured
+	CollapseBreaks       bool     // Whether to collapse short <br> runs into line breaks instead of paragraphs
+	ParagraphBreakThreshold int   // Minimum <br> run that becomes a paragraph when collapsing (0 = default)
+	MaxLineBreaks        int      // Maximum <br> kept from shorter runs when collapsing (0 = default)
+	ExtractVideos        bool     // Whether to index the video embeds kept in the content
+	ExtractCodeBlocks    bool     // Whether to index the code blocks kept in the content, with their language and caption
+	ExtractTables        bool     // Whether to index the data tables kept in the content, with their caption and summary
+	ExtractQuotes        bool     // Whether to index the quotations kept in the content, with their source and attribution
+	DetectPrimaryDocument bool    // Whether to detect landing pages whose real content is a linked PDF
+	TrackRemovedLinks    bool     // Whether to record the links removed from the article during cleanup, with the reason
+	DiscussionMode       bool     // Whether to walk the comments of the page into a thread of posts
+	LiveBlogMode         bool     // Whether to extract the timestamped entries of a live blog in chronological order
+	ExtractStructuredContent bool // Whether to read the schema.org Recipe and HowTo of the JSON-LD
+	ExtractContactInfo   bool     // Whether to collect the email addresses and phone numbers of the content
+	StrictTitle          bool     // Whether to fail with ErrNoTitle when no confident title is found
+	MinContentScore      float64  // Adjusted top candidate score below which extraction fails with ErrNoContent (0 = disabled)
+	StripHeaderAnchors   bool     // Whether to remove permalink anchors (e.g. "¶") from headings
+	TrimBoilerplateHeadings bool  // Whether to remove blocks whose entire text is an advertisement label
+	BoilerplateLabels    []string // Advertisement labels, such as "Advertisement" or "Sponsored" (empty disables)
+	NormalizeHeadingWhitespace bool // Whether heading text used as the title is put on one line, with <br> read as a space
+	StripEmptyAnchors    bool     // Whether to remove links left without text or media
+	LinkDensityModifier  float64  // Added to the link density thresholds of conditional cleaning
+	CMSContentClasses    []string // CMS content container classes that get a scoring bonus (empty disables)
+	UnlikelyRoles        []string // ARIA roles whose elements are dropped, in addition to UnlikelyRoles
+	AllowedRoles         []string // ARIA roles exempted from role-based removal
+	AllowedSchemes       []string // URL schemes of the links and images kept in the article (empty allows all but javascript)
+	KeepStructure        bool     // Whether conditional cleaning keeps lists and headed list sections
+	ExpandDetails        bool     // Whether to turn <details> into a visible section headed by its summary
+	Dehyphenate          bool     // Whether to join words broken across lines with a hyphen
+	NormalizeListMarkers bool     // Whether to turn paragraphs with pasted bullets or numbers into lists
+	MergeImageAltIntoCaption bool // Whether to caption figures without a figcaption with their image's alt text
+	PreserveGalleries    bool     // Whether conditional cleaning keeps galleries of captioned images
+	MaxImages            int      // Maximum number of images kept in the article, always including the lead image (0 = unlimited)
+	ImageDimensionInference bool  // Whether to infer missing image dimensions from the image URL
+	ProtectLeadParagraphs int     // Number of leading paragraphs of the content region that conditional cleaning keeps (0 = none)
+	MergeListsAcrossParagraphs bool // Whether to rejoin adjacent lists that cleanup split in two
+	AssumeTimezone       *time.Location // Location of publication dates without timezone information (nil = only RFC 3339 dates)
+	MetadataExtractors   []MetadataExtractor // Custom metadata extractors, run after the built-in ones
+	PostExtractHook      func(*goquery.Selection) // Called on the grabbed article node before the final cleanup
+	PreserveMath         bool     // Whether to keep MathML attributes and MathJax LaTeX source
+	RenderMathAsText     bool     // Whether to replace MathML equations with a plain text rendering
+	ReorderColumns       bool     // Whether to put multi-column layouts into reading order
+	StripLeadingSymbols  bool     // Whether to remove decorative emoji, bullets and arrows from the start of the title
+	ParallelScoring      bool     // Whether to score content elements concurrently
+	PullQuoteMode        PullQuoteMode // What to do with pull-quotes that repeat text of the article
+	PreserveSemanticStyles bool   // Whether to turn bold, italic, line-through and underline styles into elements
+	DisableFallback      bool     // Whether to keep the strict first attempt instead of retrying with relaxed flags
+	RetryFlags           []int    // Flags cleared in turn when the article is too short (nil = RetryFlags, empty disables the retries)
 }
 
 // defaultReadabilityOptions returns the default options
@@ -37,8 +100,58 @@ func defaultReadabilityOptions() ReadabilityOptions {
 		DisableJSONLD:        false,
 		AllowedVideoRegex:    RegexpVideos,
 		PreserveImportantLinks: false, // Default to false to match ReadabiliPy's behavior
-		DetectContentType:    true,    // Enable content type detection by default
-		ContentType:          ContentTypeUnknown, // Auto-detect by default
+		ContentType:          ContentTypeUnknown, // Falls back to ContentTypeArticle
+		ExtractTemplates:     false,
+		UseNoscriptFallback:  false,
+		PreserveIDs:          false,
+		StripAutogeneratedIDs: false,
+		AutogeneratedIDPatterns: AutogeneratedIDPatterns,
+		ContentLanguage:      "",
+		ExtractAuthorImage:   false,
+		ExtractThemeColor:    false,
+		ExtractAlternates:    false,
+		StripHiddenText:      true,
+		StripConsentBanners:  true,
+		StripBylineFromContent: false,
+		CollapseBreaks:       false,
+		ExtractVideos:        false,
+		ExtractCodeBlocks:    false,
+		ExtractTables:        false,
+		ExtractQuotes:        false,
+		DetectPrimaryDocument: false,
+		TrackRemovedLinks:    false,
+		DiscussionMode:       false,
+		LiveBlogMode:         false,
+		ExtractStructuredContent: false,
+		ExtractContactInfo:   false,
+		StrictTitle:          false,
+		MinContentScore:      0,
+		StripHeaderAnchors:   true,
+		TrimBoilerplateHeadings: true,
+		BoilerplateLabels:    BoilerplateLabels,
+		NormalizeHeadingWhitespace: true,
+		StripEmptyAnchors:    true,
+		LinkDensityModifier:  0,
+		CMSContentClasses:    CMSContentClasses,
+		AllowedSchemes:       AllowedSchemes,
+		RetryFlags:           RetryFlags,
+		KeepStructure:        false,
+		ExpandDetails:        false,
+		Dehyphenate:          false,
+		NormalizeListMarkers: false,
+		MergeImageAltIntoCaption: false,
+		PreserveGalleries:    false,
+		MaxImages:            0,
+		ImageDimensionInference: false,
+		ProtectLeadParagraphs: 0,
+		MergeListsAcrossParagraphs: true,
+		PreserveSemanticStyles: false,
+		RenderMathAsText:     false,
+		ReorderColumns:       false,
+		StripLeadingSymbols:  false,
+		ParallelScoring:      false,
+		PullQuoteMode:        PullQuoteKeep,
+		DisableFallback:      false,
 	}
 }
 
@@ -53,6 +166,75 @@ type ReadabilityArticle struct {
 	SiteName     string      // Site name
 	Date         time.Time   // Publication date
 	ContentType  ContentType // Detected content type
+	Lang         string      // Document language from the <html lang> attribute
+	AuthorImageURL string    // Author profile image URL (only when ExtractAuthorImage is set)
+	ThemeColor     string    // Page theme color (only when ExtractThemeColor is set)
+	Metadata       map[string]string // Metadata from the built-in and custom metadata extractors
+	AlternateLinks []AlternateLink   // Feeds, AMP version and other alternates of the page (only when ExtractAlternates is set)
+	ImageCount     int               // Number of images in the article before MaxImages was applied
+	Videos       []VideoEmbed // Video embeds kept in the content (only when ExtractVideos is set)
+	CodeBlocks   []CodeBlock  // Code blocks kept in the content (only when ExtractCodeBlocks is set)
+	Tables       []Table      // Data tables kept in the content (only when ExtractTables is set)
+	Quotes       []Quote      // Quotations kept in the content (only when ExtractQuotes is set)
+	IsTruncated  bool        // Whether the content is a teaser linking to the full article
+	FullContentURL string    // URL of the full article when IsTruncated is set
+	IsDocumentLanding bool   // Whether the page is a landing page for a linked document (only when DetectPrimaryDocument is set)
+	PrimaryDocumentURL string // URL of the linked document when IsDocumentLanding is set
+	RemovedLinks []RemovedLink // Links removed from the article during cleanup (only when TrackRemovedLinks is set)
+	Thread       []ThreadPost  // Posts of the page's comment thread (only when DiscussionMode is set)
+	Updates      []LiveUpdate  // Entries of a live blog in chronological order (only when LiveBlogMode is set)
+	Recipe       *Recipe       // Recipe from the JSON-LD (only when ExtractStructuredContent is set)
+	HowTo        *HowTo        // How-to from the JSON-LD (only when ExtractStructuredContent is set)
+	ContactInfo  *ContactInfo  // Email addresses and phone numbers of the content (only when ExtractContactInfo is set)
+	PullQuotes   []string      // Text of the pull-quotes removed from the content (only when PullQuoteMode is PullQuoteExtract)
+	Origin       ContentOrigin // Node the content was extracted from and how it was chosen
+	Confidence   float64       // Confidence from 0 to 1 that the content is the real article
+	Warnings     []string      // Problems found by the extraction quality self-test
+}
+
+// VideoEmbed describes a video embedded in the article content
+type VideoEmbed struct {
+	URL      string // Embed URL
+	Provider string // Provider name derived from the embed host (e.g. "youtube")
+	Title    string // Title from the embed's title attribute
+	Poster   string // Poster image URL, if the embed declares one
+}
+
+// CodeBlock describes a preformatted code block in the article content
+type CodeBlock struct {
+	Language string // Language from a data-lang attribute or a language-* class, lowercased
+	Caption  string // Caption from the enclosing figure's figcaption, such as a filename
+	Code     string // Code text, with its line breaks and indentation
+}
+
+// Table describes a data table in the article content
+type Table struct {
+	Caption string     // Text of the <caption> element
+	Summary string     // Value of the summary attribute
+	Headers []string   // Header cells, from the thead or a leading row of th cells
+	Rows    [][]string // Cells of the other rows
+}
+
+// Quote describes a quotation in the article content
+type Quote struct {
+	Text        string // Text of the quotation, without its attribution
+	CiteURL     string // Absolute URL of the cite attribute
+	Attribution string // Who or what is quoted, from a <cite> element or the figure caption
+}
+
+// AlternateLink describes an alternate version of the page
+type AlternateLink struct {
+	Type     string // MIME type, such as "application/rss+xml", or "amp" for the AMP version
+	Href     string // URL of the alternate version
+	Title    string // Title from the link's title attribute
+	Hreflang string // Language of a translated version
+}
+
+// RemovedLink describes a link removed from the article during cleanup
+type RemovedLink struct {
+	Href   string // Link target
+	Text   string // Anchor text
+	Reason string // Why the element holding the link was removed, e.g. "footer" or "link-density"
 }
 
 // Readability implements the Readability algorithm
@@ -66,6 +248,11 @@ type Readability struct {
 	attempts         []int             // Extraction attempts
 	flags            int               // Flags controlling the algorithm
 	contentType      ContentType       // Detected or specified content type
+	contentOrigin    ContentOrigin     // Node the content was extracted from
+	topScore         float64           // Best adjusted score of the top candidates of the extraction attempts
+	removedLinks     []RemovedLink     // Links removed during cleanup (only when TrackRemovedLinks is set)
+	removedLinkNodes map[*html.Node]bool // Anchors already in removedLinks
+	logger           *slog.Logger      // Logger of debug events
 }
 
 // NodeInfo holds information about a node
@@ -96,6 +283,7 @@ func NewFromDocument(doc *goquery.Document, opts *ReadabilityOptions) *Readabili
 		options: options,
 		flags:   FlagStripUnlikelys | FlagWeightClasses | FlagCleanConditionally,
 		contentType: options.ContentType,
+		logger:      newLogger(options),
 	}
 
 	return r
@@ -103,10 +291,12 @@ func NewFromDocument(doc *goquery.Document, opts *ReadabilityOptions) *Readabili
 
 // NewFromHTML creates a new Readability parser from HTML string
 func NewFromHTML(html string, opts *ReadabilityOptions) (*Readability, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	// Fragments (no <html> or <body>) are parsed as body content
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(simplifiers.WrapFragment(html)))
 	if err != nil {
 		return nil, WrapParseError(err, "NewFromHTML", "failed to parse HTML document")
 	}
+	simplifiers.EnsureBody(doc)
 
 	return NewFromDocument(doc, opts), nil
 }
@@ -130,18 +320,29 @@ func (r *Readability) Parse() (*ReadabilityArticle, error) {
 		}
 	}
 
+	// Let the first matching content type rule override the content type
+	r.applyContentTypeRules()
+
 	// For compatibility, still set a content type but use standard algorithm
 	if r.contentType == ContentTypeUnknown {
 		// Default to Article type for all content
 		r.contentType = ContentTypeArticle
-		if r.options.Debug {
-			fmt.Printf("DEBUG: Using standard Mozilla algorithm for all content types\n")
-		}
+		r.logEvent("parse", "use standard algorithm for all content types", nil)
 	}
 	
 	// Set standard flags for all content types (consistent with Mozilla's implementation)
 	r.flags = FlagStripUnlikelys | FlagWeightClasses | FlagCleanConditionally
 
+	// Promote server-rendered <template> content (if enabled)
+	if r.options.ExtractTemplates {
+		r.promoteTemplateContent()
+	}
+
+	// Promote the static copy of script-rendered content (if enabled)
+	if r.options.UseNoscriptFallback {
+		r.promoteNoscriptContent()
+	}
+
 	// Unwrap noscript images
 	r.unwrapNoscriptImages()
 
@@ -151,9 +352,88 @@ func (r *Readability) Parse() (*ReadabilityArticle, error) {
 		jsonLd = r.getJSONLD()
 	}
 
+	// Read the recipe and how-to of the JSON-LD before scripts are removed (if enabled)
+	var recipe *Recipe
+	var howTo *HowTo
+	if r.options.ExtractStructuredContent && !r.options.DisableJSONLD {
+		recipe, howTo = r.getStructuredContent()
+	}
+
+	// Fill in metadata missing from JSON-LD with schema.org microdata
+	for key, value := range r.getMicrodata() {
+		if jsonLd[key] == "" {
+			jsonLd[key] = value
+		}
+	}
+
+	// Run the metadata extractors before scripts and hidden elements are removed
+	extractedMetadata := r.extractMetadata()
+
+	// Keep the LaTeX source of MathJax equations (if math is preserved or
+	// rendered as text)
+	if r.options.PreserveMath || r.options.RenderMathAsText {
+		r.convertMathScripts()
+	}
+
+	// Replace MathML equations with text (if requested and math isn't preserved)
+	if r.options.RenderMathAsText && !r.options.PreserveMath {
+		r.renderMathAsText()
+	}
+
 	// Remove scripts
 	r.removeScripts()
 
+	// Measure the page text for the extraction quality self-test
+	bodyTextLength := r.getBodyTextLength()
+
+	// Drop content declared in another language (if a content language is set)
+	if r.options.ContentLanguage != "" {
+		r.removeForeignLanguageContent()
+	}
+
+	// Drop cookie consent banners
+	if r.options.StripConsentBanners {
+		r.removeConsentBanners()
+	}
+
+	// Drop text hidden from readers, unless paywalled content is being un-hidden
+	if r.options.StripHiddenText && r.contentType != ContentTypePaywall {
+		r.removeHiddenContent()
+	}
+
+	// Put column layouts into reading order while their styles are still there
+	if r.options.ReorderColumns {
+		r.reorderColumns()
+	}
+
+	// Turn meaningful inline styles into elements before styles are stripped
+	if r.options.PreserveSemanticStyles {
+		r.convertSemanticStyles()
+	}
+
+	// Walk the comment thread before comments are removed as unlikely candidates
+	var thread []ThreadPost
+	if r.options.DiscussionMode {
+		thread = r.getThread()
+	}
+
+	// Collect the live blog entries before their container is scored
+	var updates []LiveUpdate
+	if r.options.LiveBlogMode {
+		updates = r.getLiveUpdates()
+	}
+
+	// Record quote attributions before the footers holding them are removed
+	if r.options.ExtractQuotes {
+		r.labelQuoteAttributions()
+	}
+
+	// Take pull-quotes out before their repeated text is scored as content
+	var pullQuotes []string
+	if r.options.PullQuoteMode != PullQuoteKeep {
+		pullQuotes = r.removePullQuotes()
+	}
+
 	// Prepare document
 	r.prepDocument()
 
@@ -161,26 +441,61 @@ func (r *Readability) Parse() (*ReadabilityArticle, error) {
 	metadata := r.getArticleMetadata(jsonLd)
 	r.articleTitle = metadata["title"]
 
+	// Look for a date on the page when the metadata has none, while the byline
+	// that usually holds it is still there (if a location for dates is set)
+	var pageDate time.Time
+	if metadata["date"] == "" && r.options.AssumeTimezone != nil {
+		if pageHTML, err := goquery.OuterHtml(r.doc.Selection); err == nil {
+			pageDate = extractors.ExtractDateIn(pageHTML, r.options.AssumeTimezone)
+		}
+	}
+
+	// Reject pages without a title that can be trusted (if requested)
+	if r.options.StrictTitle && !r.isConfidentTitle(r.articleTitle, jsonLd) {
+		return nil, WrapExtractionError(ErrNoTitle, "Parse", "")
+	}
+
 	// Grab article content
 	article := r.grabArticle()
 	if article == nil {
 		return nil, WrapExtractionError(ErrNoContent, "Parse", "")
 	}
 
+	// Reject pages without a candidate that looks like an article (if requested)
+	if r.options.MinContentScore > 0 && r.topScore < r.options.MinContentScore {
+		return nil, WrapExtractionError(&ContentScoreError{Score: r.topScore, MinScore: r.options.MinContentScore}, "Parse", "")
+	}
+
+	// Collect the contact details before links with schemes that aren't
+	// allowed, such as tel:, are unwrapped (if enabled)
+	var contactInfo *ContactInfo
+	if r.options.ExtractContactInfo {
+		contactInfo = getContactInfo(article)
+	}
+
+	// Drop the in-content byline once it has been captured (if requested),
+	// before the classes that identify it are cleaned
+	if r.options.StripBylineFromContent {
+		r.removeBylineElements(article, metadata["byline"])
+	}
+
+	// Record code languages as data-lang before language-* classes are cleaned
+	if r.options.ExtractCodeBlocks {
+		labelCodeBlocks(article)
+	}
+
 	// Post-process content
 	r.postProcessContent(article)
 
+	// Let the caller transform the article before the final cleanup
+	if r.options.PostExtractHook != nil {
+		r.options.PostExtractHook(article)
+	}
+
 	// If no excerpt in metadata, use the first paragraph
 	excerpt := metadata["excerpt"]
 	if excerpt == "" {
-		article.Find("p").EachWithBreak(func(i int, s *goquery.Selection) bool {
-			text := strings.TrimSpace(s.Text())
-			if text != "" {
-				excerpt = text
-				return false // stop iteration
-			}
-			return true // continue
-		})
+		excerpt = r.getExcerpt(article)
 	}
 
 	// Apply standard content cleanup (ignoring content type)
@@ -190,9 +505,7 @@ func (r *Readability) Parse() (*ReadabilityArticle, error) {
 	// Additional cleanup step: make sure footers are removed
 	// This is needed because in some cases, the clean function in prepArticle
 	// might not have removed footer elements, especially if grabArticle returned the body
-	if r.options.Debug {
-		fmt.Printf("DEBUG: Final cleanup pass to remove any remaining footer elements\n")
-	}
+	r.logEvent("cleanup", "final pass over remaining footers", nil)
 	
 	// Apply the final cleanup to handle footer elements and important links
 	if r.options.PreserveImportantLinks {
@@ -202,6 +515,24 @@ func (r *Readability) Parse() (*ReadabilityArticle, error) {
 	
 	// Apply the final cleanup to handle footer elements
 	r.finalCleanupFooters(article)
+
+	// Make sure no link ends up inside another link
+	flattenNestedAnchors(article)
+
+	// Cap the number of images, keeping the lead image
+	imageCount := r.limitImages(article, extractedMetadata["image"])
+
+	// Join words broken across lines with a hyphen (if enabled)
+	if r.options.Dehyphenate {
+		dehyphenate(article)
+	}
+
+	// Index the quotations that survived cleanup, dropping the attributions
+	// recorded on them (if enabled)
+	var quotes []Quote
+	if r.options.ExtractQuotes {
+		quotes = r.getQuotes(article)
+	}
 	
 	// Get text content from the cleaned article
 	textContent := getInnerText(article, true)
@@ -216,11 +547,95 @@ func (r *Readability) Parse() (*ReadabilityArticle, error) {
 		Excerpt:     excerpt,
 		SiteName:    metadata["siteName"],
 		ContentType: r.contentType,
+		Lang:        strings.TrimSpace(r.doc.Find("html").AttrOr("lang", "")),
+		AuthorImageURL: metadata["authorImage"],
+		ThemeColor:     metadata["themeColor"],
+		Metadata:       extractedMetadata,
+		ImageCount:     imageCount,
+		Origin:         r.contentOrigin,
+	}
+
+	// Flag content that is mostly markup or a tiny part of the page
+	result.Confidence, result.Warnings = assessExtraction(article, result.Content, bodyTextLength)
+
+	// Flag text garbled by a wrong character encoding
+	if warning := mojibakeWarning(textContent); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
 	}
 
-	// Try to parse the date
+	// Flag role options that are likely typos
+	result.Warnings = append(result.Warnings, r.unknownRoleWarnings()...)
+
+	// List the alternate versions of the page (if enabled)
+	if r.options.ExtractAlternates {
+		result.AlternateLinks = r.getAlternateLinks()
+	}
+
+	// Index the video embeds that survived cleanup (if enabled)
+	if r.options.ExtractVideos {
+		result.Videos = r.getVideoEmbeds(article)
+	}
+
+	// Index the code blocks that survived cleanup (if enabled)
+	if r.options.ExtractCodeBlocks {
+		result.CodeBlocks = getCodeBlocks(article)
+	}
+
+	// Index the data tables that survived cleanup (if enabled)
+	if r.options.ExtractTables {
+		result.Tables = r.getTables(article)
+	}
+
+	// Report the quotations (if enabled)
+	result.Quotes = quotes
+
+	// Detect teaser pages that link to the full article
+	if fullContentURL := r.getFullContentURL(article, result.Length); fullContentURL != "" {
+		result.IsTruncated = true
+		result.FullContentURL = fullContentURL
+	}
+
+	// Report the comment thread (if enabled)
+	result.Thread = thread
+
+	// Report the live blog entries (if enabled)
+	result.Updates = updates
+
+	// Report the recipe and how-to (if enabled)
+	result.Recipe, result.HowTo = recipe, howTo
+
+	// Report the contact details (if enabled)
+	result.ContactInfo = contactInfo
+
+	// Report the pull-quotes (if enabled)
+	if r.options.PullQuoteMode == PullQuoteExtract {
+		result.PullQuotes = pullQuotes
+	}
+
+	// Report the links removed during cleanup (if enabled)
+	if r.options.TrackRemovedLinks {
+		result.RemovedLinks = r.removedLinks
+	}
+
+	// Detect landing pages whose real content is a linked PDF (if enabled)
+	if r.options.DetectPrimaryDocument {
+		if documentURL := r.getPrimaryDocumentURL(article, result.Length); documentURL != "" {
+			result.IsDocumentLanding = true
+			result.PrimaryDocumentURL = documentURL
+		}
+	}
+
+	// Try to parse the date, falling back to other formats when it isn't RFC 3339
+	// and to the date found on the page when there is none, if a location for
+	// dates without timezone information is set
 	if date, err := time.Parse(time.RFC3339, metadata["date"]); err == nil {
 		result.Date = date
+	} else if metadata["date"] != "" && r.options.AssumeTimezone != nil {
+		if date := extractors.ParseFlexibleDateFormatIn(metadata["date"], r.options.AssumeTimezone); !date.IsZero() {
+			result.Date = date
+		}
+	} else {
+		result.Date = pageDate
 	}
 
 	return result, nil
@@ -0,0 +1,203 @@
+package readability
+
+import (
+	"math"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Region selection constants for FindArticleRegions
+const (
+	// RegionScoreRatio is the minimum score of a region relative to the best region
+	RegionScoreRatio = 0.25
+
+	// RegionMinTextLength is the minimum text length of a region
+	RegionMinTextLength = 100
+)
+
+// regionElements are the elements that can hold an article of their own
+var regionElements = map[string]bool{
+	"article": true, "div": true, "li": true, "main": true, "section": true,
+}
+
+// FindArticleRegions finds the distinct article-like regions of a page, such as
+// the posts of a forum thread or the entries of a category page. Each region is
+// returned as a standalone HTML document, in document order, so that it can be
+// extracted on its own; its title is the region's first heading, or the page
+// title when it has none.
+//
+// Paragraphs are scored as in Parse and their scores are added to their parent
+// and grandparent. Regions score at least RegionScoreRatio of the best region
+// and hold at least RegionMinTextLength characters of text. The innermost such
+// regions are chosen, so that a thread container is not returned alongside its
+// posts, and each is then widened to the scored ancestors that contain no other
+// region. Regions never overlap.
+func FindArticleRegions(htmlContent string) ([]string, error) {
+	r, err := NewFromHTML(htmlContent, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.removeScripts()
+
+	regions := r.findArticleRegions()
+	documents := make([]string, 0, len(regions))
+	for _, region := range regions {
+		document, err := r.regionDocument(region)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// findArticleRegions returns the non-overlapping article-like regions of the document
+func (r *Readability) findArticleRegions() []*goquery.Selection {
+	body := r.doc.Find("body").First()
+	body.Find("nav, aside, form, style, noscript").Remove()
+	body.Find("header, footer").Not("article header, article footer").Remove()
+
+	// Score paragraphs into their parent and grandparent
+	scores := make(map[*html.Node]float64)
+	var candidates []*goquery.Selection
+	body.Find("p, pre").Each(func(i int, p *goquery.Selection) {
+		text := strings.Join(strings.Fields(p.Text()), " ")
+		if len(text) < MinContentTextLength {
+			return
+		}
+		contentScore := BaseContentScore
+		contentScore += float64(strings.Count(text, ",")) * CommaBonus
+		contentScore += math.Min(float64(len(text))/TextLengthDivisor, MaxLengthBonus)
+
+		ancestor := p
+		for level := 0; level < 2; level++ {
+			ancestor = ancestor.Parent()
+			node := ancestor.Get(0)
+			if node == nil || node.Type != html.ElementNode || node.Data == "body" || node.Data == "html" {
+				return
+			}
+			if !regionElements[node.Data] {
+				continue
+			}
+			if _, ok := scores[node]; !ok {
+				initialScore := float64(getClassWeight(ancestor))
+				if node.Data == "div" {
+					initialScore += DivInitialScore
+				}
+				scores[node] = initialScore
+				candidates = append(candidates, ancestor)
+			}
+			scores[node] += contentScore / float64(level+1)
+		}
+	})
+
+	// Keep the candidates that score well enough, adjusted for link density
+	adjusted := make(map[*html.Node]float64, len(candidates))
+	topScore := 0.0
+	for _, candidate := range candidates {
+		node := candidate.Get(0)
+		adjusted[node] = scores[node] * (1 - regionLinkDensity(candidate))
+		topScore = math.Max(topScore, adjusted[node])
+	}
+	var qualified []*goquery.Selection
+	for _, candidate := range candidates {
+		text := strings.Join(strings.Fields(candidate.Text()), " ")
+		if adjusted[candidate.Get(0)] >= topScore*RegionScoreRatio && len(text) >= RegionMinTextLength {
+			qualified = append(qualified, candidate)
+		}
+	}
+
+	// Choose the innermost qualified candidates
+	var regions []*goquery.Selection
+	for _, candidate := range qualified {
+		innermost := true
+		for _, other := range qualified {
+			if other != candidate && containsNode(candidate.Get(0), other.Get(0)) {
+				innermost = false
+				break
+			}
+		}
+		if innermost {
+			regions = append(regions, candidate)
+		}
+	}
+
+	// Widen each region to the scored ancestors that hold no other region
+	for i, region := range regions {
+		for {
+			parent := region.Parent()
+			node := parent.Get(0)
+			if node == nil {
+				break
+			}
+			if _, scored := scores[node]; !scored {
+				break
+			}
+			shared := false
+			for j, other := range regions {
+				if j != i && containsNode(node, other.Get(0)) {
+					shared = true
+					break
+				}
+			}
+			if shared {
+				break
+			}
+			region = parent
+		}
+		regions[i] = region
+	}
+
+	return regions
+}
+
+// regionLinkDensity returns the ratio of link text to all text in a region
+func regionLinkDensity(s *goquery.Selection) float64 {
+	textLength := len(strings.Join(strings.Fields(s.Text()), " "))
+	if textLength == 0 {
+		return 0
+	}
+	linkLength := 0
+	s.Find("a").Each(func(i int, a *goquery.Selection) {
+		linkLength += len(strings.Join(strings.Fields(a.Text()), " "))
+	})
+	return float64(linkLength) / float64(textLength)
+}
+
+// containsNode reports whether descendant is inside ancestor
+func containsNode(ancestor, descendant *html.Node) bool {
+	for n := descendant.Parent; n != nil; n = n.Parent {
+		if n == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// regionDocument wraps a region in a standalone document that keeps the page's
+// language and base URL, titled by the region's first heading
+func (r *Readability) regionDocument(region *goquery.Selection) (string, error) {
+	title := getNormalized(region.Find("h1, h2, h3").First().Text())
+	if title == "" {
+		title = getNormalized(r.doc.Find("title").First().Text())
+	}
+
+	content, err := goquery.OuterHtml(region)
+	if err != nil {
+		return "", err
+	}
+
+	var document strings.Builder
+	document.WriteString("<!DOCTYPE html><html")
+	if lang := r.doc.Find("html").AttrOr("lang", ""); lang != "" {
+		document.WriteString(` lang="` + html.EscapeString(lang) + `"`)
+	}
+	document.WriteString("><head><title>" + html.EscapeString(title) + "</title>")
+	if base := r.doc.Find("head base[href]").First(); base.Length() > 0 {
+		document.WriteString(`<base href="` + html.EscapeString(base.AttrOr("href", "")) + `">`)
+	}
+	document.WriteString("</head><body>" + content + "</body></html>")
+	return document.String(), nil
+}
@@ -0,0 +1,250 @@
+package readability
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Limits that keep the memory used by ExtractStreaming constant
+const (
+	// StreamMaxDepth is the maximum number of open elements tracked at once
+	StreamMaxDepth = 256
+
+	// StreamMaxBlockLength is the length at which a block is flushed even if
+	// its element has not ended yet
+	StreamMaxBlockLength = 64 * 1024
+)
+
+// Heuristics used by ExtractStreaming to reject boilerplate blocks
+const (
+	// StreamLinkDensityThreshold is the maximum ratio of link text to total text
+	StreamLinkDensityThreshold = 0.5
+
+	// StreamMinCharsPerTag is the minimum number of text characters per tag in a
+	// block; menus and widgets have many tags around little text
+	StreamMinCharsPerTag = 10
+)
+
+// streamSkippedElements are elements whose contents are never article text
+var streamSkippedElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true,
+	"button": true, "select": true, "textarea": true, "iframe": true, "svg": true,
+	"math": true, "dialog": true,
+}
+
+// streamBlockElements are elements that start and end a block of text
+var streamBlockElements = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "figcaption": true, "figure": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true,
+	"li": true, "main": true, "ol": true, "p": true, "pre": true, "section": true,
+	"table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+// streamVoidElements are elements that never have an end tag
+var streamVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "param": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// streamElement is an open element on the ExtractStreaming stack
+type streamElement struct {
+	tag  string
+	skip bool
+}
+
+// streamState holds the state of a single ExtractStreaming pass
+type streamState struct {
+	stack    []streamElement
+	overflow int
+	skip     int
+	links    int
+
+	text       strings.Builder
+	linkLength int
+	tags       int
+	onBlock    func(Block)
+}
+
+// ExtractStreaming extracts text blocks from an HTML document in a single pass
+// over its tokens, calling onBlock for each block as soon as it is complete.
+// No DOM tree is built, so memory use stays constant regardless of document size.
+//
+// Instead of scoring candidates, each block is judged on its own: text inside
+// navigation, forms, scripts, hidden elements and elements whose class or id
+// suggests boilerplate is skipped, and blocks with high link density or many
+// tags around little text are dropped. This is less accurate than full
+// extraction: boilerplate that looks like prose is kept, and short paragraphs
+// of real content may be dropped.
+func ExtractStreaming(r io.Reader, onBlock func(Block)) error {
+	z := html.NewTokenizer(r)
+	state := &streamState{onBlock: onBlock}
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			state.flush()
+			if err := z.Err(); err != io.EOF {
+				return err
+			}
+			return nil
+
+		case html.TextToken:
+			if state.skip == 0 {
+				state.addText(string(z.Text()))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			tag := token.Data
+			boilerplate := isStreamBoilerplate(token)
+			if streamBlockElements[tag] || boilerplate {
+				state.flush()
+			}
+			state.closeImplied(tag)
+			if state.skip == 0 {
+				state.tags++
+			}
+			if token.Type == html.SelfClosingTagToken || streamVoidElements[tag] {
+				continue
+			}
+			state.push(tag, boilerplate)
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if streamBlockElements[tag] {
+				state.flush()
+			}
+			state.pop(tag)
+		}
+	}
+}
+
+// isStreamBoilerplate reports whether the contents of an element should be
+// skipped, based on its tag and attributes
+func isStreamBoilerplate(token html.Token) bool {
+	if streamSkippedElements[token.Data] {
+		return true
+	}
+	if !isNodeVisible(&html.Node{Type: html.ElementNode, Data: token.Data, Attr: token.Attr}) {
+		return true
+	}
+	if token.Data == "html" || token.Data == "body" || token.Data == "article" || token.Data == "main" {
+		return false
+	}
+
+	var class, id, role string
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "class":
+			class = attr.Val
+		case "id":
+			id = attr.Val
+		case "role":
+			role = attr.Val
+		}
+	}
+	if contains(UnlikelyRoles, role) {
+		return true
+	}
+	matchString := class + " " + id
+	return RegexpUnlikelyCandidates.MatchString(matchString) && !RegexpMaybeCandidate.MatchString(matchString)
+}
+
+// push opens an element
+func (s *streamState) push(tag string, skip bool) {
+	if len(s.stack) >= StreamMaxDepth {
+		s.overflow++
+		return
+	}
+	s.stack = append(s.stack, streamElement{tag: tag, skip: skip})
+	if skip {
+		s.skip++
+	}
+	if tag == "a" {
+		s.links++
+	}
+}
+
+// pop closes the most recent open element with the given tag, and any elements
+// opened after it. End tags without a matching open element are ignored.
+func (s *streamState) pop(tag string) {
+	if s.overflow > 0 {
+		s.overflow--
+		return
+	}
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if s.stack[i].tag == tag {
+			for len(s.stack) > i {
+				s.popLast()
+			}
+			return
+		}
+	}
+}
+
+// popLast closes the innermost open element
+func (s *streamState) popLast() {
+	last := s.stack[len(s.stack)-1]
+	s.stack = s.stack[:len(s.stack)-1]
+	if last.skip {
+		s.skip--
+	}
+	if last.tag == "a" {
+		s.links--
+	}
+}
+
+// closeImplied closes the paragraphs and list items that a new element ends
+// implicitly, so that documents without end tags don't grow the stack
+func (s *streamState) closeImplied(tag string) {
+	if !streamBlockElements[tag] || s.overflow > 0 {
+		return
+	}
+	for len(s.stack) > 0 {
+		last := s.stack[len(s.stack)-1].tag
+		if last == "p" || (last == tag && (tag == "li" || tag == "dt" || tag == "dd")) {
+			s.popLast()
+			continue
+		}
+		return
+	}
+}
+
+// addText appends text to the current block
+func (s *streamState) addText(text string) {
+	s.text.WriteString(text)
+	if s.links > 0 {
+		s.linkLength += len(strings.TrimSpace(text))
+	}
+	if s.text.Len() >= StreamMaxBlockLength {
+		s.flush()
+	}
+}
+
+// flush ends the current block, passing it to the callback if it looks like content
+func (s *streamState) flush() {
+	text := getNormalized(s.text.String())
+	linkLength := s.linkLength
+	tags := s.tags
+
+	s.text.Reset()
+	s.linkLength = 0
+	s.tags = 0
+
+	if len(text) < MinContentTextLength {
+		return
+	}
+	if float64(linkLength)/float64(len(text)) > StreamLinkDensityThreshold {
+		return
+	}
+	if tags > 0 && len(text)/tags < StreamMinCharsPerTag {
+		return
+	}
+	s.onBlock(Block{Text: text})
+}
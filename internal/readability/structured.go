@@ -0,0 +1,217 @@
+package readability
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Recipe is the schema.org Recipe of a page, from its JSON-LD
+type Recipe struct {
+	Name        string      // Name of the dish
+	Description string      // Short description of the recipe
+	Ingredients []string    // Ingredients with their quantities, as written
+	Steps       []HowToStep // Instructions in order
+	PrepTime    string      // Preparation time as an ISO 8601 duration, e.g. "PT15M"
+	CookTime    string      // Cooking time as an ISO 8601 duration
+	TotalTime   string      // Total time as an ISO 8601 duration
+	Yield       string      // Quantity produced, e.g. "4 servings"
+}
+
+// HowTo is the schema.org HowTo of a page, from its JSON-LD
+type HowTo struct {
+	Name        string      // Name of the task
+	Description string      // Short description of the task
+	Supplies    []string    // Supplies consumed by the task
+	Tools       []string    // Tools used but not consumed by the task
+	Steps       []HowToStep // Steps in order
+	TotalTime   string      // Total time as an ISO 8601 duration, e.g. "PT30M"
+	Yield       string      // Result of the task, e.g. "1 bookshelf"
+}
+
+// HowToStep is a step of a Recipe or HowTo
+type HowToStep struct {
+	Section string // Name of the section holding the step, if the steps are grouped
+	Name    string // Short name of the step, if any
+	Text    string // Instructions of the step
+}
+
+// jsonLDTagRe matches the HTML tags that some sites put in JSON-LD text values
+var jsonLDTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// jsonLDCDATARe matches the CDATA markers that some sites wrap JSON-LD in
+var jsonLDCDATARe = regexp.MustCompile(`^\s*<!\[CDATA\[|\]\]>\s*$`)
+
+// getStructuredContent returns the first Recipe and the first HowTo described
+// by the JSON-LD of the page, looking into arrays and @graph lists. Scripts
+// that aren't valid JSON are skipped.
+func (r *Readability) getStructuredContent() (*Recipe, *HowTo) {
+	var recipe *Recipe
+	var howTo *HowTo
+	r.doc.Find("script[type='application/ld+json']").Each(func(i int, s *goquery.Selection) {
+		content := jsonLDCDATARe.ReplaceAllString(s.Text(), "")
+		var data any
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			r.logEvent("parse", "skip invalid JSON-LD", s, "error", err.Error())
+			return
+		}
+		for _, item := range jsonLDItems(data) {
+			switch {
+			case recipe == nil && jsonLDHasType(item, "Recipe"):
+				recipe = parseRecipe(item)
+			case howTo == nil && jsonLDHasType(item, "HowTo"):
+				howTo = parseHowTo(item)
+			}
+		}
+	})
+	return recipe, howTo
+}
+
+// jsonLDItems returns the objects of a JSON-LD document: the document itself,
+// the elements of a top-level array and the members of @graph lists
+func jsonLDItems(data any) []map[string]any {
+	var items []map[string]any
+	switch v := data.(type) {
+	case []any:
+		for _, element := range v {
+			items = append(items, jsonLDItems(element)...)
+		}
+	case map[string]any:
+		items = append(items, v)
+		if graph, ok := v["@graph"]; ok {
+			items = append(items, jsonLDItems(graph)...)
+		}
+	}
+	return items
+}
+
+// jsonLDHasType reports whether a JSON-LD object has the given @type, which
+// may be one of several types
+func jsonLDHasType(item map[string]any, typ string) bool {
+	for _, t := range jsonLDStrings(item["@type"]) {
+		if t == typ || strings.HasSuffix(t, "/"+typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRecipe reads a schema.org Recipe object
+func parseRecipe(item map[string]any) *Recipe {
+	ingredients := item["recipeIngredient"]
+	if ingredients == nil {
+		// Older markup uses the deprecated ingredients property
+		ingredients = item["ingredients"]
+	}
+	return &Recipe{
+		Name:        jsonLDText(item["name"]),
+		Description: jsonLDText(item["description"]),
+		Ingredients: jsonLDStrings(ingredients),
+		Steps:       jsonLDSteps(item["recipeInstructions"], ""),
+		PrepTime:    jsonLDText(item["prepTime"]),
+		CookTime:    jsonLDText(item["cookTime"]),
+		TotalTime:   jsonLDText(item["totalTime"]),
+		Yield:       jsonLDYield(item["recipeYield"]),
+	}
+}
+
+// parseHowTo reads a schema.org HowTo object
+func parseHowTo(item map[string]any) *HowTo {
+	return &HowTo{
+		Name:        jsonLDText(item["name"]),
+		Description: jsonLDText(item["description"]),
+		Supplies:    jsonLDStrings(item["supply"]),
+		Tools:       jsonLDStrings(item["tool"]),
+		Steps:       jsonLDSteps(item["step"], ""),
+		TotalTime:   jsonLDText(item["totalTime"]),
+		Yield:       jsonLDYield(item["yield"]),
+	}
+}
+
+// jsonLDSteps reads the instructions of a Recipe or the steps of a HowTo,
+// given as text, a list of texts, HowToStep objects, or HowToSection and
+// ItemList objects grouping further steps. Steps without text are skipped.
+func jsonLDSteps(value any, section string) []HowToStep {
+	var steps []HowToStep
+	switch v := value.(type) {
+	case string:
+		if text := jsonLDText(v); text != "" {
+			steps = append(steps, HowToStep{Section: section, Text: text})
+		}
+	case []any:
+		for _, element := range v {
+			steps = append(steps, jsonLDSteps(element, section)...)
+		}
+	case map[string]any:
+		if elements, ok := v["itemListElement"]; ok {
+			if jsonLDHasType(v, "HowToSection") {
+				section = jsonLDText(v["name"])
+			}
+			return jsonLDSteps(elements, section)
+		}
+		step := HowToStep{Section: section, Name: jsonLDText(v["name"]), Text: jsonLDText(v["text"])}
+		if step.Text == "" {
+			step.Text, step.Name = step.Name, ""
+		}
+		// The name of many steps is only the start of their text
+		if step.Name != "" && strings.HasPrefix(step.Text, strings.TrimRight(step.Name, ".…")) {
+			step.Name = ""
+		}
+		if step.Text != "" {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// jsonLDStrings reads a JSON-LD value that may be a single value or a list,
+// of texts or of objects with a name, such as HowToSupply
+func jsonLDStrings(value any) []string {
+	var texts []string
+	switch v := value.(type) {
+	case []any:
+		for _, element := range v {
+			texts = append(texts, jsonLDStrings(element)...)
+		}
+	case map[string]any:
+		if text := jsonLDText(v["name"]); text != "" {
+			texts = append(texts, text)
+		}
+	default:
+		if text := jsonLDText(v); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// jsonLDYield reads a yield, which sites give as a number, a text or a list of
+// both such as ["4", "4 servings"], preferring the most descriptive text
+func jsonLDYield(value any) string {
+	yields := jsonLDStrings(value)
+	for _, yield := range yields {
+		if _, err := strconv.ParseFloat(yield, 64); err != nil {
+			return yield
+		}
+	}
+	if len(yields) > 0 {
+		return yields[0]
+	}
+	return ""
+}
+
+// jsonLDText returns a JSON-LD text or number as normalized plain text, with
+// any HTML tags and character references removed
+func jsonLDText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return getNormalized(html.UnescapeString(jsonLDTagRe.ReplaceAllString(v, " ")))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
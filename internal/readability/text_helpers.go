@@ -108,24 +108,8 @@ func textSimilarity(textA, textB string) float64 {
 		return 0.0
 	}
 	
-	textA = strings.ToLower(textA)
-	textB = strings.ToLower(textB)
-	
-	// Tokenize: split by any non-word characters and filter out empty tokens
-	tokenizeAndFilter := func(text string) []string {
-		tokens := RegexpTokenize.Split(text, -1)
-		filtered := []string{}
-		for _, token := range tokens {
-			token = strings.TrimSpace(token)
-			if token != "" {
-				filtered = append(filtered, token)
-			}
-		}
-		return filtered
-	}
-	
-	tokensA := tokenizeAndFilter(textA)
-	tokensB := tokenizeAndFilter(textB)
+	tokensA := tokenize(textA)
+	tokensB := tokenize(textB)
 	
 	// Count matching tokens
 	matches := 0
@@ -150,6 +134,19 @@ func textSimilarity(textA, textB string) float64 {
 	return float64(matches) / float64(lenA+lenB-matches)
 }
 
+// tokenize lowercases text and splits it into words at non-word characters
+func tokenize(text string) []string {
+	tokens := RegexpTokenize.Split(strings.ToLower(text), -1)
+	filtered := []string{}
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token != "" {
+			filtered = append(filtered, token)
+		}
+	}
+	return filtered
+}
+
 // getCharCount counts occurrences of a specific character in a node's text
 func getCharCount(s *goquery.Selection, delimiter string) int {
 	if s == nil || s.Length() == 0 {
@@ -301,6 +298,14 @@ func getTextDensity(s *goquery.Selection, tagNames []string) float64 {
 	return float64(tagTextLength) / float64(textLength)
 }
 
+// getHeadingText returns the text of a heading on a single line: <br> line
+// breaks are read as spaces and runs of whitespace are collapsed
+func getHeadingText(s *goquery.Selection) string {
+	heading := s.Clone()
+	heading.Find("br").ReplaceWithHtml(" ")
+	return getNormalized(heading.Text())
+}
+
 // getNormalized returns a normalized string with whitespace trimmed
 func getNormalized(text string) string {
 	return strings.TrimSpace(RegexpNormalize.ReplaceAllString(text, " "))
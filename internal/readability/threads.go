@@ -0,0 +1,117 @@
+package readability
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Selectors of the parts of a discussion thread
+const (
+	// threadPostSelector matches the comments of common comment systems and
+	// forums: schema.org comments, WordPress and Disqus style .comment elements,
+	// and Reddit's shreddit-comment
+	threadPostSelector = `[itemtype$="schema.org/Comment"], .comment, shreddit-comment`
+
+	// threadAuthorSelector matches the author of a post
+	threadAuthorSelector = `[itemprop="author"], [rel="author"], .comment-author, .author, .username, .user`
+
+	// threadTimeSelector matches the timestamp of a post
+	threadTimeSelector = `time, .comment-date, .comment-time, .timestamp, .date`
+
+	// threadBodySelector matches the text of a post
+	threadBodySelector = `[itemprop="text"], .comment-body, .comment-content, .comment-text, [slot="comment"], .md`
+)
+
+// ThreadPost is a post of a discussion thread
+type ThreadPost struct {
+	Author      string // Author name
+	Timestamp   string // Timestamp as written: the datetime attribute when present, otherwise the text
+	Body        string // Text of the post, with paragraphs separated by blank lines
+	Depth       int    // Nesting depth; top-level posts are at depth 0
+	ParentIndex int    // Index of the post this one replies to, or -1 for top-level posts
+}
+
+// getThread walks the comments of a discussion page into a flat list of posts
+// in document order. A post is nested as deep as its number of enclosing
+// comments, or as the depth or data-depth attribute of flat layouts declares,
+// and replies to the closest earlier post nested less deeply; its Depth is one
+// more than its parent's. The author, timestamp and body are looked for in the
+// post itself, leaving out its replies. Posts without text are skipped.
+func (r *Readability) getThread() []ThreadPost {
+	var posts []ThreadPost
+	r.doc.Find(threadPostSelector).Each(func(i int, s *goquery.Selection) {
+		// Leave out the replies nested in the post
+		own := s.Clone()
+		own.Find(threadPostSelector).Remove()
+		own.Find("ol.children, ul.children, .replies, .comment-replies").Remove()
+
+		post := ThreadPost{
+			Author:      getNormalized(own.Find(threadAuthorSelector).First().Text()),
+			Timestamp:   threadPostTimestamp(own),
+			Body:        threadPostBody(own),
+			Depth:       threadPostDepth(s),
+			ParentIndex: -1,
+		}
+		if post.Body == "" {
+			return
+		}
+
+		for j := len(posts) - 1; j >= 0; j-- {
+			if posts[j].Depth < post.Depth {
+				post.ParentIndex = j
+				break
+			}
+		}
+		if post.ParentIndex == -1 {
+			post.Depth = 0
+		} else {
+			post.Depth = posts[post.ParentIndex].Depth + 1
+		}
+		posts = append(posts, post)
+	})
+	return posts
+}
+
+// threadPostDepth returns the nesting depth of a post
+func threadPostDepth(s *goquery.Selection) int {
+	for _, attr := range []string{"depth", "data-depth"} {
+		if depth, err := strconv.Atoi(strings.TrimSpace(s.AttrOr(attr, ""))); err == nil && depth >= 0 {
+			return depth
+		}
+	}
+	return s.ParentsFiltered(threadPostSelector).Length()
+}
+
+// threadPostTimestamp returns the timestamp of a post, preferring a machine
+// readable datetime attribute
+func threadPostTimestamp(post *goquery.Selection) string {
+	timestamp := post.Find(threadTimeSelector).First()
+	if datetime := strings.TrimSpace(timestamp.AttrOr("datetime", "")); datetime != "" {
+		return datetime
+	}
+	return getNormalized(timestamp.Text())
+}
+
+// threadPostBody returns the text of a post. Without a dedicated body element,
+// the post's text is used once its author, timestamp and reply links are removed.
+func threadPostBody(post *goquery.Selection) string {
+	body := post.Find(threadBodySelector).First()
+	if body.Length() == 0 {
+		body = post
+		body.Find(threadAuthorSelector + ", " + threadTimeSelector + `, .reply, .comment-reply-link, .comment-meta`).Remove()
+	}
+
+	paragraphs := body.Find("p")
+	if paragraphs.Length() == 0 {
+		return getNormalized(body.Text())
+	}
+	var texts []string
+	paragraphs.Each(func(i int, p *goquery.Selection) {
+		if text := getNormalized(p.Text()); text != "" {
+			texts = append(texts, text)
+		}
+	})
+	return strings.Join(texts, "\n\n")
+}
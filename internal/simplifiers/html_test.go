@@ -49,6 +49,18 @@ func TestSimplifyHTML(t *testing.T) {
 			opts:    ContentOptions{},
 			wantErr: true,
 		},
+		{
+			name:  "bare fragment",
+			input: `<div><p>Hello</p><p>World</p></div>`,
+			opts:  ContentOptions{},
+			want:  `<html><head></head><body><div><p>Hello</p><p>World</p></div></body></html>`,
+		},
+		{
+			name:  "document without body",
+			input: `<html><head></head><frameset><frame src="a.html"></frameset></html>`,
+			opts:  ContentOptions{},
+			want:  `<html><head></head><frame src="a.html"></frame><body></body></html>`,
+		},
 		{
 			name:  "remove blacklisted elements",
 			input: `<body><p>Text</p><script>alert('hello');</script><button>Click me</button></body>`,
@@ -98,6 +110,41 @@ func TestSimplifyHTML(t *testing.T) {
 			},
 			want: `<html><head></head><body><p>First</p><p>Second</p></body></html>`,
 		},
+		{
+			name:  "blacklist removes forms",
+			input: `<body><p>Sign in</p><form action="/login"><label>Username</label><input name="username"><button>Log in</button></form></body>`,
+			opts: ContentOptions{
+				RemoveBlacklist: true,
+			},
+			want: `<html><head></head><body><p>Sign in</p></body></html>`,
+		},
+		{
+			name:  "blacklist keeps forms",
+			input: `<body><p>Sign in</p><form action="/login"><label>Username</label><input name="username"><button>Log in</button></form></body>`,
+			opts: ContentOptions{
+				RemoveBlacklist: true,
+				KeepForms:       true,
+			},
+			want: `<html><head></head><body><p>Sign in</p><form action="/login"><label>Username</label><input name="username"/><button>Log in</button></form></body></html>`,
+		},
+		{
+			name:  "blacklist expands details",
+			input: `<body><details open><summary>How do I reset my password?</summary><p>Use the reset link.</p></details></body>`,
+			opts: ContentOptions{
+				RemoveBlacklist: true,
+				ExpandDetails:   true,
+			},
+			want: `<html><head></head><body><section><h3>How do I reset my password?</h3><p>Use the reset link.</p></section></body></html>`,
+		},
+		{
+			name:  "blacklist keeps math when preserving",
+			input: `<body><p><math display="block"><mi>π</mi><msup><mi>r</mi><mn>2</mn></msup></math></p></body>`,
+			opts: ContentOptions{
+				RemoveBlacklist: true,
+				PreserveMath:    true,
+			},
+			want: `<html><head></head><body><p><math display="block"><mi>π</mi><msup><mi>r</mi><mn>2</mn></msup></math></p></body></html>`,
+		},
 		{
 			name:  "wrap bare text",
 			input: `<body>Bare text <div>Inside div</div></body>`,
@@ -232,7 +279,7 @@ func TestRemoveBlacklist(t *testing.T) {
 		t.Fatalf("Failed to parse test HTML: %v", err)
 	}
 
-	removeBlacklist(doc)
+	removeBlacklist(doc, ContentOptions{})
 
 	// Check that blacklisted elements are removed
 	if doc.Find("script").Length() > 0 || doc.Find("button").Length() > 0 {
@@ -252,7 +299,7 @@ func TestUnwrapElements(t *testing.T) {
 		t.Fatalf("Failed to parse test HTML: %v", err)
 	}
 
-	unwrapElements(doc)
+	unwrapElements(doc, ContentOptions{})
 
 	// Check that unwrapped elements are removed
 	if doc.Find("span").Length() > 0 || doc.Find("b").Length() > 0 {
@@ -265,6 +312,46 @@ func TestUnwrapElements(t *testing.T) {
 	}
 }
 
+func TestUnwrapElementsPreserveKbdAndSamp(t *testing.T) {
+	input := `<body><p>Press <kbd class="key" title="Control">Ctrl</kbd>+<kbd>C</kbd> to see <samp data-line="1">Stopped</samp> in <code>bash</code></p></body>`
+
+	tests := []struct {
+		name string
+		opts ContentOptions
+		want string
+	}{
+		{
+			name: "unwrapped by default",
+			opts: ContentOptions{},
+			want: `<p>Press <kbd class="key" title="Control">Ctrl</kbd>+<kbd>C</kbd> to see Stopped in bash</p>`,
+		},
+		{
+			name: "preserved",
+			opts: ContentOptions{PreserveKbdAndSamp: true},
+			want: `<p>Press <kbd>Ctrl</kbd>+<kbd>C</kbd> to see <samp>Stopped</samp> in bash</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("Failed to parse test HTML: %v", err)
+			}
+
+			unwrapElements(doc, tt.opts)
+
+			got, err := doc.Find("body").Html()
+			if err != nil {
+				t.Fatalf("Failed to render HTML: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("unwrapElements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestProcessSpecialElements(t *testing.T) {
 	html := `<body><p><q>Quote</q> and <sub>subscript</sub> and <sup>superscript</sup></p></body>`
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
@@ -272,7 +359,7 @@ func TestProcessSpecialElements(t *testing.T) {
 		t.Fatalf("Failed to parse test HTML: %v", err)
 	}
 
-	processSpecialElements(doc)
+	processSpecialElements(doc, QuoteStyleStraight)
 
 	// Check that special elements are unwrapped
 	if doc.Find("q").Length() > 0 || doc.Find("sub").Length() > 0 || doc.Find("sup").Length() > 0 {
@@ -286,6 +373,37 @@ func TestProcessSpecialElements(t *testing.T) {
 	}
 }
 
+func TestProcessSpecialElementsQuoteStyle(t *testing.T) {
+	tests := []struct {
+		name  string
+		style QuoteStyle
+		want  string
+	}{
+		{"straight", QuoteStyleStraight, `"Quote"`},
+		{"curly", QuoteStyleCurly, "“Quote”"},
+		{"guillemets", QuoteStyleGuillemets, "«Quote»"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := `<body><p>She said <q>Quote</q> twice.</p></body>`
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+			if err != nil {
+				t.Fatalf("Failed to parse test HTML: %v", err)
+			}
+
+			processSpecialElements(doc, tt.style)
+
+			if doc.Find("q").Length() > 0 {
+				t.Errorf("processSpecialElements() failed to unwrap q element")
+			}
+			if text := doc.Find("p").Text(); !strings.Contains(text, tt.want) {
+				t.Errorf("processSpecialElements() = %q, want it to contain %q", text, tt.want)
+			}
+		})
+	}
+}
+
 func TestUnnestParagraphs(t *testing.T) {
 	// Create a direct test for the unnestParagraphs function
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head></head><body><p>Before <div>Inside</div> After</p></body></html>`))
@@ -314,7 +432,7 @@ func TestInsertParagraphBreaks(t *testing.T) {
 		t.Fatalf("Failed to parse test HTML: %v", err)
 	}
 
-	insertParagraphBreaks(doc)
+	insertParagraphBreaks(doc, ContentOptions{})
 
 	// Check that br elements are removed
 	if doc.Find("br").Length() > 0 {
@@ -338,6 +456,59 @@ func TestInsertParagraphBreaks(t *testing.T) {
 	}
 }
 
+func TestInsertParagraphBreaksCollapse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  ContentOptions
+		want  string
+	}{
+		{
+			name:  "five breaks make one paragraph boundary",
+			input: `<p>First<br><br><br><br><br>Second</p>`,
+			opts:  ContentOptions{CollapseBreaks: true},
+			want:  `<html><head></head><body><p>First</p><p>Second</p></body></html>`,
+		},
+		{
+			name:  "two breaks collapse to a single break",
+			input: `<p>First<br><br>Second</p>`,
+			opts:  ContentOptions{CollapseBreaks: true},
+			want:  `<html><head></head><body><p>First<br/>Second</p></body></html>`,
+		},
+		{
+			name:  "single break is kept",
+			input: `<p>First<br>Second</p>`,
+			opts:  ContentOptions{CollapseBreaks: true},
+			want:  `<html><head></head><body><p>First<br/>Second</p></body></html>`,
+		},
+		{
+			name:  "custom limits",
+			input: `<p>First<br><br><br><br>Second<br><br><br><br><br>Third</p>`,
+			opts:  ContentOptions{CollapseBreaks: true, ParagraphBreakThreshold: 5, MaxLineBreaks: 2},
+			want:  `<html><head></head><body><p>First<br/><br/>Second</p><p>Third</p></body></html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Failed to parse test HTML: %v", err)
+			}
+
+			insertParagraphBreaks(doc, tt.opts)
+
+			got, err := doc.Html()
+			if err != nil {
+				t.Fatalf("Failed to get HTML: %v", err)
+			}
+			if got = StripHTMLWhitespace(got); got != tt.want {
+				t.Errorf("insertParagraphBreaks() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWrapBareText(t *testing.T) {
 	html := `<body>Bare text <div>Inside div</div></body>`
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
@@ -357,3 +528,125 @@ func TestWrapBareText(t *testing.T) {
 		t.Errorf("wrapBareText() incorrectly wrapped div content")
 	}
 }
+
+func TestMinifyHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "whitespace between blocks is removed",
+			input: "<div>\n  <p>First</p>\n  <p>Second</p>\n</div>\n",
+			want:  "<div><p>First</p><p>Second</p></div>",
+		},
+		{
+			name:  "whitespace between inline elements becomes a space",
+			input: "<p><em>one</em>\n   <strong>two</strong></p>",
+			want:  "<p><em>one</em> <strong>two</strong></p>",
+		},
+		{
+			name:  "text is copied unchanged",
+			input: "<p>  Spaced   text\n here </p>",
+			want:  "<p>  Spaced   text\n here </p>",
+		},
+		{
+			name:  "pre contents are preserved",
+			input: "<div>\n<pre>\n  <code>x := 1</code>\n\n  <code>y := 2</code>\n</pre>\n</div>",
+			want:  "<div><pre>\n  <code>x := 1</code>\n\n  <code>y := 2</code>\n</pre></div>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinifyHTML(tt.input); got != tt.want {
+				t.Errorf("MinifyHTML() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollapseAttributeWhitespace(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "multi-line alt is collapsed",
+			input: "<p><img src=\"a.png\" alt=\"  A heron\n\t  standing in   reeds \"/></p>",
+			want:  "<p><img src=\"a.png\" alt=\"A heron standing in reeds\"/></p>",
+		},
+		{
+			name:  "title and aria-label are collapsed",
+			input: "<a href=\"/x\" title=\"Read\n more\" aria-label=\" Next \">x</a>",
+			want:  "<a href=\"/x\" title=\"Read more\" aria-label=\"Next\">x</a>",
+		},
+		{
+			name:  "other attributes and text are copied unchanged",
+			input: "<P CLASS=\"a  b\" data-x=\"1\n2\">  some\n text </P>",
+			want:  "<P CLASS=\"a  b\" data-x=\"1\n2\">  some\n text </P>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CollapseAttributeWhitespace(tt.input); got != tt.want {
+				t.Errorf("CollapseAttributeWhitespace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateHTML(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxLength int
+		want      string
+		truncated bool
+	}{
+		{
+			name:      "content under the limit is unchanged",
+			input:     "<div><p>Short text.</p></div>",
+			maxLength: 100,
+			want:      "<div><p>Short text.</p></div>",
+		},
+		{
+			name:      "cut at a paragraph boundary",
+			input:     "<div><p>First paragraph.</p><p>Second paragraph.</p><p>Third paragraph.</p></div>",
+			maxLength: 40,
+			want:      "<div><p>First paragraph.</p><p>Second paragraph.</p></div>",
+			truncated: true,
+		},
+		{
+			name:      "first block is kept even when too long",
+			input:     "<div><p>A paragraph longer than the limit.</p><p>Next.</p></div>",
+			maxLength: 10,
+			want:      "<div><p>A paragraph longer than the limit.</p></div>",
+			truncated: true,
+		},
+		{
+			name:      "list items are kept whole and emptied containers removed",
+			input:     "<div><ul><li>One item here</li><li>Two items here</li></ul><section><p>Later text.</p></section></div>",
+			maxLength: 20,
+			want:      "<div><ul><li>One item here</li></ul></div>",
+			truncated: true,
+		},
+		{
+			name:      "zero means no limit",
+			input:     "<div><p>First paragraph.</p><p>Second paragraph.</p></div>",
+			maxLength: 0,
+			want:      "<div><p>First paragraph.</p><p>Second paragraph.</p></div>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := TruncateHTML(tt.input, tt.maxLength)
+			if got != tt.want || truncated != tt.truncated {
+				t.Errorf("TruncateHTML() =\n%q, %v\nwant\n%q, %v", got, truncated, tt.want, tt.truncated)
+			}
+		})
+	}
+}
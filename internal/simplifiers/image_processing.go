@@ -1,6 +1,8 @@
 package simplifiers
 
 import (
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -14,11 +16,87 @@ var decorativeImagePatterns = []string{
 	"tracking", "analytics", "pixel.gif", "1x1", "1px", "badge",
 }
 
-// IsRelevantImage determines if an image is relevant to the content
-func IsRelevantImage(s *goquery.Selection) bool {
-	// Check size attributes
-	width, _ := strconv.Atoi(s.AttrOr("width", "0"))
-	height, _ := strconv.Atoi(s.AttrOr("height", "0"))
+// Patterns of image URLs that carry the image's dimensions
+var (
+	// urlSizeRe matches a size in a file name or path segment, as in
+	// "photo-1200x800.jpg" or "/800x600/photo.jpg"
+	urlSizeRe = regexp.MustCompile(`(?i)(?:^|[-_.@/])(\d{1,5})x(\d{1,5})(?:[-_.@/]|$)`)
+
+	// urlTransformRe matches the width or height parameter of a CDN
+	// transformation, as in the path segment "w_1200,h_800"
+	urlTransformRe = regexp.MustCompile(`(?i)^([wh])_(\d{1,5})$`)
+)
+
+// InferImageDimensions returns the width and height an image URL says the image
+// has, from a size in the file name ("photo-1200x800.jpg"), a CDN transformation
+// ("/w_1200,h_800/") or the query ("?w=1200&h=800", "?width=1200"). Dimensions
+// the URL doesn't give are 0.
+func InferImageDimensions(src string) (width, height int) {
+	u, err := url.Parse(strings.TrimSpace(src))
+	if err != nil {
+		return 0, 0
+	}
+
+	// The size closest to the file name is the one of this rendition
+	if matches := urlSizeRe.FindAllStringSubmatch(u.Path, -1); len(matches) > 0 {
+		last := matches[len(matches)-1]
+		width, _ = strconv.Atoi(last[1])
+		height, _ = strconv.Atoi(last[2])
+	}
+
+	for _, segment := range strings.Split(u.Path, "/") {
+		for _, param := range strings.Split(segment, ",") {
+			match := urlTransformRe.FindStringSubmatch(param)
+			if match == nil {
+				continue
+			}
+			value, _ := strconv.Atoi(match[2])
+			if strings.EqualFold(match[1], "w") && width == 0 {
+				width = value
+			} else if strings.EqualFold(match[1], "h") && height == 0 {
+				height = value
+			}
+		}
+	}
+
+	query := u.Query()
+	for _, key := range []string{"w", "width"} {
+		if value, err := strconv.Atoi(query.Get(key)); err == nil && width == 0 {
+			width = value
+		}
+	}
+	for _, key := range []string{"h", "height"} {
+		if value, err := strconv.Atoi(query.Get(key)); err == nil && height == 0 {
+			height = value
+		}
+	}
+	return width, height
+}
+
+// ImageDimensions returns the width and height of an image from its width and
+// height attributes. With infer set, a dimension without an attribute is
+// inferred from the image URL. Unknown dimensions are 0.
+func ImageDimensions(s *goquery.Selection, infer bool) (width, height int) {
+	width, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(s.AttrOr("width", "")), "px"))
+	height, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(s.AttrOr("height", "")), "px"))
+	if infer && (width == 0 || height == 0) {
+		inferredWidth, inferredHeight := InferImageDimensions(s.AttrOr("src", ""))
+		if width == 0 {
+			width = inferredWidth
+		}
+		if height == 0 {
+			height = inferredHeight
+		}
+	}
+	return width, height
+}
+
+// IsRelevantImage determines if an image is relevant to the content. The
+// image is sized by its attributes, or with ImageDimensionInference set, also by
+// its URL.
+func IsRelevantImage(s *goquery.Selection, opts ContentOptions) bool {
+	// Check size attributes, or the size given by the URL
+	width, height := ImageDimensions(s, opts.ImageDimensionInference)
 
 	// Small images are likely decorative
 	if width > 0 && height > 0 && width < 100 && height < 100 {
@@ -77,11 +155,11 @@ func isGenericAltText(alt string) bool {
 }
 
 // ProcessImages processes images in the document
-func ProcessImages(doc *goquery.Document) *goquery.Document {
+func ProcessImages(doc *goquery.Document, opts ContentOptions) *goquery.Document {
 	// Find all images
 	doc.Find("img").Each(func(i int, s *goquery.Selection) {
 		// Check if the image is relevant
-		if !IsRelevantImage(s) {
+		if !IsRelevantImage(s, opts) {
 			// Remove non-relevant images
 			s.Remove()
 		} else {
@@ -102,11 +180,11 @@ func ProcessImages(doc *goquery.Document) *goquery.Document {
 }
 
 // EnhanceImages enhances images in the document (for backward compatibility with tests)
-func EnhanceImages(doc *goquery.Document) *goquery.Document {
+func EnhanceImages(doc *goquery.Document, opts ContentOptions) *goquery.Document {
 	// Find all images
 	doc.Find("img").Each(func(i int, s *goquery.Selection) {
 		// Check if the image is relevant
-		if !IsRelevantImage(s) {
+		if !IsRelevantImage(s, opts) {
 			// Remove non-relevant images
 			s.Remove()
 		} else {
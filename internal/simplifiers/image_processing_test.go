@@ -12,6 +12,7 @@ func TestIsRelevantImage(t *testing.T) {
 		name     string
 		html     string
 		selector string
+		opts     ContentOptions
 		expected bool
 	}{
 		{
@@ -50,6 +51,19 @@ func TestIsRelevantImage(t *testing.T) {
 			selector: ".content img",
 			expected: true,
 		},
+		{
+			name:     "Small size in URL without inference",
+			html:     `<img src="thumb-50x50.jpg">`,
+			selector: "img",
+			expected: true,
+		},
+		{
+			name:     "Small size in URL with inference",
+			html:     `<img src="thumb-50x50.jpg">`,
+			selector: "img",
+			opts:     ContentOptions{ImageDimensionInference: true},
+			expected: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -60,7 +74,7 @@ func TestIsRelevantImage(t *testing.T) {
 			}
 
 			img := doc.Find(test.selector)
-			result := IsRelevantImage(img)
+			result := IsRelevantImage(img, test.opts)
 			if result != test.expected {
 				t.Errorf("Expected IsRelevantImage to return %v, got %v", test.expected, result)
 			}
@@ -87,7 +101,7 @@ func TestProcessImages(t *testing.T) {
 	}
 
 	// Process images
-	ProcessImages(doc)
+	ProcessImages(doc, ContentOptions{})
 
 	// Check that relevant images have the data-relevant-image attribute
 	doc.Find("img[data-relevant-image='true']").Each(func(i int, s *goquery.Selection) {
@@ -221,7 +235,7 @@ func TestEnhanceImages(t *testing.T) {
 	}
 
 	// Enhance images
-	EnhanceImages(doc)
+	EnhanceImages(doc, ContentOptions{})
 
 	// Check that relevant images have lazy loading
 	doc.Find("img[data-relevant-image='true']").Each(func(i int, s *goquery.Selection) {
@@ -247,3 +261,28 @@ func TestEnhanceImages(t *testing.T) {
 		}
 	})
 }
+
+func TestInferImageDimensions(t *testing.T) {
+	tests := []struct {
+		src           string
+		width, height int
+	}{
+		{"https://cdn.example.com/images/photo-1200x800.jpg", 1200, 800},
+		{"https://cdn.example.com/800x600/photo.jpg", 800, 600},
+		{"https://res.example.com/image/upload/w_1200,h_630/photo.jpg", 1200, 630},
+		{"https://images.example.com/photo.jpg?w=1200", 1200, 0},
+		{"https://images.example.com/photo.jpg?width=640&height=480", 640, 480},
+		{"/tracking/pixel_1x1.gif", 1, 1},
+		{"https://example.com/photo.jpg", 0, 0},
+		{"https://example.com/2024x/report.jpg", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			width, height := InferImageDimensions(tt.src)
+			if width != tt.width || height != tt.height {
+				t.Errorf("InferImageDimensions(%q) = %dx%d, want %dx%d", tt.src, width, height, tt.width, tt.height)
+			}
+		})
+	}
+}
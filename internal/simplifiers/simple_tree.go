@@ -59,21 +59,21 @@ func SimpleTreeFromHTMLString(html string) (*SimpleTree, error) {
 
 	// Remove blacklisted elements
 	if opts.RemoveBlacklist {
-		removeBlacklist(doc)
+		removeBlacklist(doc, opts)
 	}
 
 	// Unwrap elements where we want to keep the text but drop the containing tag
 	if opts.UnwrapElements {
-		unwrapElements(doc)
+		unwrapElements(doc, opts)
 	}
 
 	// Process elements with special innerText handling
 	if opts.ProcessSpecial {
-		processSpecialElements(doc)
+		processSpecialElements(doc, opts.QuoteStyle)
 	}
 
 	// Process unknown elements
-	processUnknownElements(doc)
+	processUnknownElements(doc, opts.PreserveMath)
 
 	// Consolidate text, joining any consecutive NavigableStrings together.
 	// Must come before any whitespace operations (eg. remove_empty_strings_and_elements or normalise_strings)
@@ -95,7 +95,7 @@ func SimpleTreeFromHTMLString(html string) (*SimpleTree, error) {
 	// Must come after remove_empty_strings_and_elements so that consecutive <br>s can be identified
 	// Re-consolidates strings at the end, so must come before normalise_strings
 	if opts.InsertBreaks {
-		insertParagraphBreaks(doc)
+		insertParagraphBreaks(doc, opts)
 	}
 
 	// Wrap any remaining bare text in a suitable block level element
@@ -161,16 +161,42 @@ func SimpleTreeFromHTMLString(html string) (*SimpleTree, error) {
 
 // PlainContent generates plain content from HTML with optional content digests and node indexes
 func PlainContent(html string, addContentDigests, addNodeIndexes bool) (string, error) {
+	return PlainContentWithOptions(html, ContentOptions{
+		AddContentDigests: addContentDigests,
+		AddNodeIndexes:    addNodeIndexes,
+	})
+}
+
+// PlainContentWithOptions generates plain content from HTML using the given content options.
+// Only the options that apply to already-extracted content are honored; the blacklist,
+// unwrapping and paragraph restructuring steps belong to SimplifyHTML.
+func PlainContentWithOptions(html string, opts ContentOptions) (string, error) {
 	// Parse the HTML
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return "", fmt.Errorf("parsing HTML: %w", err)
 	}
 
-	// Apply the simplification steps
-	opts := ContentOptions{
-		AddContentDigests: addContentDigests,
-		AddNodeIndexes:    addNodeIndexes,
+	// Keep data tables out of the remaining passes
+	var tables []string
+	if opts.TablesVerbatim {
+		tables = setAsideDataTables(doc)
+	}
+
+	// Keep keyboard input and sample output as bare tags (if requested)
+	if opts.PreserveKbdAndSamp {
+		stripKbdAndSampAttributes(doc)
+	}
+
+	// Render inline quotations with the requested quotation marks (if other
+	// than the default, which leaves <q> elements as they are)
+	if opts.QuoteStyle != QuoteStyleStraight {
+		convertQuotes(doc, opts.QuoteStyle)
+	}
+
+	// Canonicalize punctuation before the general text normalization runs
+	if opts.NormalizePunctuation {
+		normalizePunctuationNodes(doc, opts.DashReplacement)
 	}
 
 	// Make all elements plain
@@ -211,7 +237,18 @@ func PlainContent(html string, addContentDigests, addNodeIndexes bool) (string,
 	// Fix HTML entities in the output
 	renderedHTML = strings.ReplaceAll(renderedHTML, "&#34;", "\"")
 
-	return renderedHTML, nil
+	return restoreDataTables(renderedHTML, tables), nil
+}
+
+// normalizePunctuationNodes applies NormalizePunctuation to all text nodes in the document
+func normalizePunctuationNodes(doc *goquery.Document, dashReplacement string) {
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		s.Contents().Each(func(_ int, c *goquery.Selection) {
+			if goquery.NodeName(c) == "#text" {
+				c.Get(0).Data = NormalizePunctuation(c.Get(0).Data, dashReplacement)
+			}
+		})
+	})
 }
 
 // normalizeStrings normalizes all text nodes in the document
@@ -2,6 +2,8 @@ package simplifiers
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -118,6 +120,69 @@ func PreserveTableStructure(html string) string {
 	return html
 }
 
+// verbatimTableAttr marks the placeholders left by setAsideDataTables
+const verbatimTableAttr = "data-verbatim-table"
+
+// verbatimTablePlaceholderRE matches a rendered placeholder and any attributes added to it
+var verbatimTablePlaceholderRE = regexp.MustCompile(`<table ` + verbatimTableAttr + `="(\d+)"([^>]*)>\s*</table>`)
+
+// isDataTable reports whether a table holds tabular data rather than layout
+func isDataTable(table *goquery.Selection) bool {
+	if table.Find("table").Length() > 0 {
+		return false
+	}
+	role := strings.ToLower(table.AttrOr("role", ""))
+	if role == "presentation" || role == "none" {
+		return false
+	}
+	if table.AttrOr("summary", "") != "" ||
+		table.Find("caption, thead, th, col, colgroup, [rowspan], [colspan]").Length() > 0 {
+		return true
+	}
+	rows := table.Find("tr")
+	return rows.Length() >= 2 && rows.First().Find("td").Length() >= 2
+}
+
+// setAsideDataTables replaces data tables with empty placeholders so that later
+// passes cannot alter their cell structure, and returns the table HTML in
+// placeholder order. Class and style attributes are stripped from each table.
+func setAsideDataTables(doc *goquery.Document) []string {
+	var tables []string
+	doc.Find("table").Each(func(_ int, table *goquery.Selection) {
+		if table.ParentsFiltered("table").Length() > 0 || !isDataTable(table) {
+			return
+		}
+
+		clean := table.Clone()
+		clean.Find("*").AddBack().RemoveAttr("class").RemoveAttr("style")
+		tableHTML, err := goquery.OuterHtml(clean)
+		if err != nil {
+			return
+		}
+
+		table.ReplaceWithHtml(fmt.Sprintf(`<table %s="%d"></table>`, verbatimTableAttr, len(tables)))
+		tables = append(tables, tableHTML)
+	})
+	return tables
+}
+
+// restoreDataTables puts the tables set aside by setAsideDataTables back into
+// rendered HTML. Attributes added to a placeholder, such as node indexes, are
+// carried over to the restored table.
+func restoreDataTables(rendered string, tables []string) string {
+	if len(tables) == 0 {
+		return rendered
+	}
+	return verbatimTablePlaceholderRE.ReplaceAllStringFunc(rendered, func(placeholder string) string {
+		match := verbatimTablePlaceholderRE.FindStringSubmatch(placeholder)
+		i, err := strconv.Atoi(match[1])
+		if err != nil || i >= len(tables) {
+			return placeholder
+		}
+		return strings.Replace(tables[i], "<table", "<table"+match[2], 1)
+	})
+}
+
 // EstimateReadingTime estimates the reading time in minutes
 func EstimateReadingTime(text string) int {
 	// Average reading speed is about 200-250 words per minute
@@ -4,12 +4,17 @@
 package simplifiers
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"sync"
 	"unicode"
 	"unicode/utf8"
 
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	xunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 )
 
@@ -391,6 +396,142 @@ func normalizeTextUncached(text string) string {
 	return text
 }
 
+// punctuationReplacements maps typographic quotes and spaces to their plain equivalents
+var punctuationReplacements = []string{
+	"\u2018", "'", // left single quotation mark
+	"\u2019", "'", // right single quotation mark
+	"\u201a", "'", // single low-9 quotation mark
+	"\u201b", "'", // single high-reversed-9 quotation mark
+	"\u201c", "\"", // left double quotation mark
+	"\u201d", "\"", // right double quotation mark
+	"\u201e", "\"", // double low-9 quotation mark
+	"\u201f", "\"", // double high-reversed-9 quotation mark
+	"\u00a0", " ", // non-breaking space
+	"\u202f", " ", // narrow non-breaking space
+	"\u2007", " ", // figure space
+}
+
+// dashCharacters lists the dash characters replaced by NormalizePunctuation
+var dashCharacters = []string{
+	"\u2012", // figure dash
+	"\u2013", // en dash
+	"\u2014", // em dash
+	"\u2015", // horizontal bar
+}
+
+// NormalizePunctuation canonicalizes typographic punctuation for search and indexing.
+// Curly quotes become straight quotes and non-breaking spaces become regular spaces.
+// En and em dashes are replaced with dashReplacement; an empty dashReplacement leaves
+// dashes untouched. Unlike NormalizeText, no other Unicode normalization is applied.
+func NormalizePunctuation(text, dashReplacement string) string {
+	if text == "" {
+		return ""
+	}
+
+	pairs := append([]string{}, punctuationReplacements...)
+	if dashReplacement != "" {
+		for _, dash := range dashCharacters {
+			pairs = append(pairs, dash, dashReplacement)
+		}
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Byte order marks that may start a document
+const (
+	bomUTF8    = "\xef\xbb\xbf"
+	bomUTF16BE = "\xfe\xff"
+	bomUTF16LE = "\xff\xfe"
+)
+
+// StripByteOrderMark removes a leading byte order mark from a document. UTF-16
+// documents, which are identified by their byte order mark, are transcoded to
+// UTF-8. Documents without a byte order mark are returned unchanged.
+func StripByteOrderMark(document string) string {
+	switch {
+	case strings.HasPrefix(document, bomUTF8):
+		return document[len(bomUTF8):]
+	case strings.HasPrefix(document, bomUTF16BE), strings.HasPrefix(document, bomUTF16LE):
+		decoded, _, err := transform.String(xunicode.BOMOverride(transform.Nop), document)
+		if err != nil {
+			return document
+		}
+		return decoded
+	}
+	return document
+}
+
+// DecodeHTML decodes a raw HTML document to UTF-8 and returns the name of the
+// encoding it was decoded from. Unless an encoding is forced, the encoding is
+// determined as browsers do: from a byte order mark, then from a charset
+// declared in a <meta> tag, then by checking for valid UTF-8, and finally
+// falling back to windows-1252. An error is returned for an unknown forced
+// encoding.
+func DecodeHTML(content []byte, forced string) (string, string, error) {
+	enc, name, _ := charset.DetermineEncoding(content, "")
+	if forced != "" {
+		if enc, name = charset.Lookup(forced); enc == nil {
+			return "", "", fmt.Errorf("unknown encoding %q", forced)
+		}
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return "", "", fmt.Errorf("decoding %s: %w", name, err)
+	}
+	return string(decoded), name, nil
+}
+
+// Elements whose end tag may be omitted (plus void elements, which never have
+// one). Leaving these open at the end of a document is valid HTML.
+var optionalEndTags = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true,
+	"dt": true, "dd": true, "option": true, "optgroup": true, "colgroup": true,
+	"caption": true, "thead": true, "tbody": true, "tfoot": true, "tr": true,
+	"td": true, "th": true, "rb": true, "rt": true, "rtc": true, "rp": true,
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// IsTruncatedHTML reports whether a document appears to have been cut off, as
+// when a connection drops mid-download: it ends inside a tag, or it leaves an
+// element open whose end tag is required. Elements HTML lets authors leave
+// unclosed, such as <body>, <html>, <p> and <li>, are not counted.
+func IsTruncatedHTML(document string) bool {
+	if strings.LastIndex(document, "<") > strings.LastIndex(document, ">") {
+		return true
+	}
+	var open []string
+	tokenizer := html.NewTokenizer(strings.NewReader(document))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			for _, name := range open {
+				if !optionalEndTags[name] {
+					return true
+				}
+			}
+			return false
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			if !optionalEndTags[string(name)] {
+				open = append(open, string(name))
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			// An end tag implicitly closes anything left open inside it
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == string(name) {
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+}
+
 // IsControlCategory checks if a rune belongs to a Unicode control category
 // This function is optimized to use a map-based lookup for categories
 func IsControlCategory(r rune, categories ...string) bool {
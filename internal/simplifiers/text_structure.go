@@ -116,6 +116,106 @@ func CountSentences(text string) int {
 	return count
 }
 
+// sentenceAbbreviations are abbreviations whose trailing period does not end a sentence
+var sentenceAbbreviations = map[string]bool{
+	"dr": true, "mr": true, "mrs": true, "ms": true, "prof": true,
+	"jr": true, "sr": true, "vs": true, "fig": true, "vol": true,
+	"inc": true, "ltd": true, "approx": true,
+}
+
+// ambiguousAbbreviations are abbreviations that are also common words. Their
+// period only continues the sentence when they are capitalized and followed by
+// a number ("No. 5") or, for the others, a capitalized name ("St. Paul").
+var ambiguousAbbreviations = map[string]bool{
+	"no": true, "st": true, "co": true,
+}
+
+// SplitSentences splits text into sentences at terminal punctuation followed by
+// whitespace and a word that does not start in lowercase. Periods in decimals,
+// after known abbreviations ("Dr.", "Mr."), after dotted abbreviations ("e.g.",
+// "U.S.") and after initials ("J.") do not end a sentence. Closing quotes and
+// brackets stay with the sentence they close.
+func SplitSentences(text string) []string {
+	runes := []rune(text)
+	var sentences []string
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+
+		// Include repeated punctuation and closing quotes or brackets
+		end := i + 1
+		for end < len(runes) && strings.ContainsRune(".!?\"')]”’»", runes[end]) {
+			end++
+		}
+
+		// A boundary needs whitespace followed by a word that does not start in lowercase
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			i = end - 1
+			continue
+		}
+		next := end
+		for next < len(runes) && unicode.IsSpace(runes[next]) {
+			next++
+		}
+		if next < len(runes) && unicode.IsLower(runes[next]) {
+			i = end - 1
+			continue
+		}
+		if r == '.' && end == i+1 && isAbbreviation(runes[:i], runes[next:]) {
+			continue
+		}
+
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+		i = end - 1
+	}
+
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// isAbbreviation reports whether the word at the end of text, which precedes a
+// period, is an abbreviation or an initial. The text after the period and the
+// whitespace that follows it decides ambiguous abbreviations.
+func isAbbreviation(text, following []rune) bool {
+	begin := len(text)
+	for begin > 0 && !unicode.IsSpace(text[begin-1]) {
+		begin--
+	}
+	word := strings.TrimLeft(string(text[begin:]), "\"'(“‘[«")
+	if word == "" {
+		return false
+	}
+
+	if sentenceAbbreviations[strings.ToLower(word)] {
+		return true
+	}
+	if lower := strings.ToLower(word); ambiguousAbbreviations[lower] {
+		if !unicode.IsUpper([]rune(word)[0]) || len(following) == 0 {
+			return false
+		}
+		if lower == "no" {
+			return unicode.IsDigit(following[0])
+		}
+		return unicode.IsUpper(following[0])
+	}
+
+	// Initials ("J.") and dotted abbreviations ("e.g", "U.S")
+	for _, part := range strings.Split(word, ".") {
+		if len([]rune(part)) != 1 || !unicode.IsLetter([]rune(part)[0]) {
+			return false
+		}
+	}
+	return strings.Contains(word, ".") || unicode.IsUpper([]rune(word)[0])
+}
+
 // CountWords counts the number of words in a text
 func CountWords(text string) int {
 	// Split by whitespace and count non-empty strings
@@ -1,6 +1,7 @@
 package simplifiers
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -129,6 +130,69 @@ func TestCountWords(t *testing.T) {
 	}
 }
 
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "Empty text",
+			text:     "",
+			expected: nil,
+		},
+		{
+			name:     "Single sentence without punctuation",
+			text:     "No terminal punctuation",
+			expected: []string{"No terminal punctuation"},
+		},
+		{
+			name:     "Title abbreviation",
+			text:     "Dr. Smith went home. He slept.",
+			expected: []string{"Dr. Smith went home.", "He slept."},
+		},
+		{
+			name:     "Dotted abbreviation",
+			text:     "Some fruit, e.g. Apples, is sweet. Other fruit is not!",
+			expected: []string{"Some fruit, e.g. Apples, is sweet.", "Other fruit is not!"},
+		},
+		{
+			name:     "Decimal number",
+			text:     "Pi is roughly 3.14 in value. It never ends?",
+			expected: []string{"Pi is roughly 3.14 in value.", "It never ends?"},
+		},
+		{
+			name:     "Initials",
+			text:     "The book is by J. R. Tolkien. Read it.",
+			expected: []string{"The book is by J. R. Tolkien.", "Read it."},
+		},
+		{
+			name:     "Closing quote and lowercase continuation",
+			text:     `He said "Stop." Then he left... and never returned. The end`,
+			expected: []string{`He said "Stop."`, "Then he left... and never returned.", "The end"},
+		},
+		{
+			name:     "Ambiguous abbreviations as words",
+			text:     "The engineer said no. The council will vote.",
+			expected: []string{"The engineer said no.", "The council will vote."},
+		},
+		{
+			name:     "Ambiguous abbreviations before a number or name",
+			text:     "Room No. 5 is near St. Paul. It is quiet.",
+			expected: []string{"Room No. 5 is near St. Paul.", "It is quiet."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := SplitSentences(test.text)
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("Expected %q, got %q", test.expected, result)
+			}
+		})
+	}
+}
+
 func TestCalculateReadingLevel(t *testing.T) {
 	tests := []struct {
 		name     string
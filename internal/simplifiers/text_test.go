@@ -123,6 +123,161 @@ func TestNormalizeText(t *testing.T) {
 	}
 }
 
+func TestNormalizePunctuation(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		dashReplacement string
+		want            string
+	}{
+		{
+			name:            "curly quotes become straight",
+			input:           "\u201cIt\u2019s fine,\u201d she said",
+			dashReplacement: "-",
+			want:            "\"It's fine,\" she said",
+		},
+		{
+			name:            "dashes use the replacement",
+			input:           "pages 10\u201312 \u2014 roughly",
+			dashReplacement: "-",
+			want:            "pages 10-12 - roughly",
+		},
+		{
+			name:            "empty replacement keeps dashes",
+			input:           "before\u2014after",
+			dashReplacement: "",
+			want:            "before\u2014after",
+		},
+		{
+			name:            "non-breaking spaces become regular spaces",
+			input:           "100\u00a0km and 5\u202f%",
+			dashReplacement: "-",
+			want:            "100 km and 5 %",
+		},
+		{
+			name:            "other characters are untouched",
+			input:           "caf\u00e9 \u2026 \u00a9",
+			dashReplacement: "-",
+			want:            "caf\u00e9 \u2026 \u00a9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizePunctuation(tt.input, tt.dashReplacement); got != tt.want {
+				t.Errorf("NormalizePunctuation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripByteOrderMark(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "utf-8 byte order mark is removed",
+			input: "\xef\xbb\xbf<p>caf\u00e9</p>",
+			want:  "<p>caf\u00e9</p>",
+		},
+		{
+			name:  "utf-16 big endian is transcoded",
+			input: "\xfe\xff\x00<\x00p\x00>\x00c\x00a\x00f\x00\xe9",
+			want:  "<p>caf\u00e9",
+		},
+		{
+			name:  "utf-16 little endian is transcoded",
+			input: "\xff\xfe<\x00p\x00>\x00c\x00a\x00f\x00\xe9\x00",
+			want:  "<p>caf\u00e9",
+		},
+		{
+			name:  "input without a byte order mark is unchanged",
+			input: "<p>caf\u00e9 \ufeff</p>",
+			want:  "<p>caf\u00e9 \ufeff</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripByteOrderMark(tt.input); got != tt.want {
+				t.Errorf("StripByteOrderMark() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTruncatedHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"complete document", "<html><body><p>Done.</p></body></html>", false},
+		{"fragment without root tags", "<p>Done.</p>", false},
+		{"omitted optional end tags", "<html><body><ul><li>One<li>Two</ul><p>Done.", false},
+		{"unclosed inner element closed by parent", "<body><div><span>Done.</div></body>", false},
+		{"unclosed article", "<html><body><article><p>Cut off", true},
+		{"unclosed span", "<HTML><body><p><span>Cut off", true},
+		{"ends inside a tag", "<p>Cut off</p><div cla", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTruncatedHTML(tt.input); got != tt.want {
+				t.Errorf("IsTruncatedHTML(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		forced   string
+		want     string
+		wantName string
+	}{
+		{
+			name:     "declared charset is used",
+			input:    `<meta charset="iso-8859-1"><p>caf` + "\xe9</p>",
+			want:     `<meta charset="iso-8859-1"><p>caf` + "\u00e9</p>",
+			wantName: "windows-1252",
+		},
+		{
+			name:     "valid utf-8 is sniffed",
+			input:    "<p>caf\u00e9</p>",
+			want:     "<p>caf\u00e9</p>",
+			wantName: "utf-8",
+		},
+		{
+			name:     "forced encoding overrides the declaration",
+			input:    `<meta charset="utf-8"><p>caf` + "\xe9</p>",
+			forced:   "windows-1252",
+			want:     `<meta charset="utf-8"><p>caf` + "\u00e9</p>",
+			wantName: "windows-1252",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, name, err := DecodeHTML([]byte(tt.input), tt.forced)
+			if err != nil {
+				t.Fatalf("DecodeHTML() error = %v", err)
+			}
+			if got != tt.want || name != tt.wantName {
+				t.Errorf("DecodeHTML() = %q, %q, want %q, %q", got, name, tt.want, tt.wantName)
+			}
+		})
+	}
+
+	if _, _, err := DecodeHTML([]byte("<p></p>"), "no-such-encoding"); err == nil {
+		t.Errorf("DecodeHTML() with an unknown encoding returned no error")
+	}
+}
+
 func TestStripHTMLWhitespace(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -20,9 +20,14 @@ package readabiligo
 import (
 	"fmt"
 	"io"
+	"log/slog"
+	"regexp"
+	"sync"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/mrjoshuak/readabiligo/internal/readability"
+	"github.com/mrjoshuak/readabiligo/internal/simplifiers"
 )
 
 // Extractor defines the interface for article extraction.
@@ -35,6 +40,62 @@ type Extractor interface {
 	ExtractFromReader(r io.Reader, options *ExtractionOptions) (*Article, error)
 }
 
+// ExtendedExtractor is implemented by the extractors returned by New in
+// addition to Extractor. It is kept apart from Extractor so that existing
+// implementations and mocks of Extractor keep compiling; assert to it to use
+// the methods it adds:
+//
+//	ext := readabiligo.New().(readabiligo.ExtendedExtractor)
+type ExtendedExtractor interface {
+	Extractor
+
+	// ExtractWithVariants extracts both the strict and the lenient result from an HTML string
+	ExtractWithVariants(html string, options *ExtractionOptions) (*ArticleVariants, error)
+
+	// ExtractStreaming streams text blocks from an io.Reader to a callback in a single pass
+	ExtractStreaming(r io.Reader, onBlock func(Block)) error
+
+	// ExtractAll extracts each distinct article-like region of an HTML string
+	ExtractAll(html string, options *ExtractionOptions) ([]*Article, error)
+
+	// ExtractBatch extracts a batch of documents, optionally concurrently
+	ExtractBatch(inputs []BatchInput, options *ExtractionOptions) []BatchResult
+}
+
+// ErrNoTitle is returned, wrapped, by extractions with WithStrictTitle when no
+// confident title can be resolved. Check for it with errors.Is.
+var ErrNoTitle = readability.ErrNoTitle
+
+// ErrNoContent is returned, wrapped, by extractions that find no article
+// content, including those rejected by WithMinContentScore. Check for it with
+// errors.Is.
+var ErrNoContent = readability.ErrNoContent
+
+// ContentScoreError is returned, wrapped, by extractions with
+// WithMinContentScore when the top candidate scores below the minimum. It
+// matches ErrNoContent with errors.Is, and errors.As gives the score found.
+type ContentScoreError = readability.ContentScoreError
+
+// ErrTimeout is returned, wrapped, by extractions with WithPhaseTimeouts when a
+// phase runs over its budget. Check for it with errors.Is.
+var ErrTimeout = readability.ErrTimeout
+
+// PhaseTimeoutError is returned, wrapped, by extractions with WithPhaseTimeouts
+// when the parse or extract phase runs over its budget. It matches ErrTimeout
+// with errors.Is, and errors.As gives the phase and its budget.
+type PhaseTimeoutError = readability.PhaseTimeoutError
+
+// Phase is a stage of the extraction that can be given its own time budget
+// with WithPhaseTimeouts
+type Phase = readability.Phase
+
+// Extraction phases, in the order they run
+const (
+	PhaseParse   = readability.PhaseParse   // Parsing the HTML into a document
+	PhaseExtract = readability.PhaseExtract // Finding, scoring and cleaning up the article content
+	PhaseText    = readability.PhaseText    // Rendering PlainContent and PlainText from the content
+)
+
 // Option represents a function that modifies ExtractionOptions.
 // This follows the functional options pattern for configuring the extractor.
 type Option func(*ExtractionOptions)
@@ -69,27 +130,883 @@ func WithPreserveImportantLinks(enable bool) Option {
 }
 
 // WithDetectContentType is maintained for backward compatibility but does nothing.
-// The content type detection has been removed to follow Mozilla's Readability.js algorithm,
-// which uses a unified approach for all content types.
-// Deprecated: This option no longer has any effect.
+// Content types are never detected: every page is extracted with Mozilla's unified
+// algorithm and Article.ContentType is ContentTypeArticle unless a content type is
+// assigned with WithContentTypeRule or WithContentType.
+//
+// Deprecated: This option has no effect. Use WithContentTypeRule to assign a
+// content type to the pages a selector matches.
 func WithDetectContentType(enable bool) Option {
 	return func(o *ExtractionOptions) {
-		// No-op for backward compatibility
 		o.DetectContentType = enable
 	}
 }
 
-// WithContentType is maintained for backward compatibility but does nothing.
-// The content type specialization has been removed to follow Mozilla's Readability.js algorithm,
-// which uses a unified approach for all content types.
-// Deprecated: This option no longer has any effect.
+// WithContentType assigns a content type to every page, reported in
+// Article.ContentType. As with WithContentTypeRule, ContentTypePaywall keeps the
+// article text hidden behind the paywall and ContentTypeMinimal keeps the login
+// or sign-up form of the page; other content types only label the page. A
+// matching WithContentTypeRule takes precedence.
+//
+// Deprecated: Use WithContentTypeRule, which assigns the content type only to
+// the pages its selector matches, e.g. WithContentTypeRule("html", contentType)
+// for every page.
 func WithContentType(contentType ContentType) Option {
 	return func(o *ExtractionOptions) {
-		// No-op for backward compatibility
 		o.ContentType = contentType
 	}
 }
 
+// WithExtractTemplates enables or disables promotion of <template> content.
+// Some component-based pages server-render the article body into <template>
+// elements, which are never displayed and would otherwise be discarded. When
+// enabled and the visible document is sparse, a template holding substantial
+// article-like content is unwrapped into the document before scoring.
+func WithExtractTemplates(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractTemplates = enable
+	}
+}
+
+// WithUseNoscriptFallback enables or disables promotion of <noscript> content.
+// Some JavaScript-rendered pages keep a static copy of the article in a
+// <noscript> element, which is otherwise discarded. When enabled and the
+// visible document is sparse, a <noscript> holding substantial article-like
+// content is unwrapped into the document before scoring.
+func WithUseNoscriptFallback(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.UseNoscriptFallback = enable
+	}
+}
+
+// WithQuoteStyle sets the quotation marks used when inline <q> quotations are
+// rendered as text in PlainContent. With the default QuoteStyleStraight, <q>
+// elements are left as they are; curly quotes and guillemets replace them with
+// their text in those marks for typographically correct output.
+func WithQuoteStyle(style QuoteStyle) Option {
+	return func(o *ExtractionOptions) {
+		o.QuoteStyle = style
+	}
+}
+
+// WithComputeReadingLevel enables or disables reading level scoring.
+// When enabled, Article.ReadabilityScore is set to the Flesch-Kincaid grade
+// level of the extracted text. The syllable and sentence heuristics are
+// English-centric, so scoring is skipped for documents declaring another language.
+func WithComputeReadingLevel(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ComputeReadingLevel = enable
+	}
+}
+
+// WithPreserveIDs enables or disables id preservation for deep-linking.
+// When enabled, element ids in the extracted content are guaranteed to be
+// unique (later duplicates receive a numeric suffix) and fragment-only links
+// are kept relative instead of being resolved against the base URL, so
+// in-page anchors keep working in the rendered content.
+func WithPreserveIDs(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.PreserveIDs = enable
+	}
+}
+
+// WithStripAutogeneratedIDs enables or disables removal of generated ids.
+// Framework and tool generated ids, such as "gatsby-focus-wrapper", React
+// useId values or long hex hashes, are noise in the extracted content, while
+// ids written by authors, such as "section-intro", are useful deep-link
+// targets. When enabled, ids matching the patterns of WithAutogeneratedIDPatterns
+// are removed and all other ids are kept. It combines with WithPreserveIDs.
+func WithStripAutogeneratedIDs(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StripAutogeneratedIDs = enable
+	}
+}
+
+// WithAutogeneratedIDPatterns sets the patterns of generated ids removed by
+// WithStripAutogeneratedIDs, replacing DefaultAutogeneratedIDPatterns. An id is
+// removed when any pattern matches it.
+func WithAutogeneratedIDPatterns(patterns ...*regexp.Regexp) Option {
+	return func(o *ExtractionOptions) {
+		o.AutogeneratedIDPatterns = patterns
+	}
+}
+
+// DefaultAutogeneratedIDPatterns returns the default patterns of generated ids:
+// hex hashes of at least 8 digits, UUIDs, React useId values, and the ids of
+// common frameworks such as Gatsby, Next.js, Nuxt, Ember and Radix.
+func DefaultAutogeneratedIDPatterns() []*regexp.Regexp {
+	return append([]*regexp.Regexp(nil), readability.AutogeneratedIDPatterns...)
+}
+
+// WithExcludePlainText sets CSS selectors whose text is left out of the PlainText blocks.
+// Matching elements (and anything nested inside them) remain in Content and
+// PlainContent; only the plain-text stream is affected. This is useful for keeping
+// code blocks or tables out of text fed to summarizers, e.g. WithExcludePlainText("pre", "table").
+func WithExcludePlainText(selectors ...string) Option {
+	return func(o *ExtractionOptions) {
+		o.ExcludePlainTextSelectors = selectors
+	}
+}
+
+// WithNormalizePunctuation enables or disables punctuation canonicalization in the plain-text output.
+// When enabled, curly quotes become straight quotes, en and em dashes are replaced
+// (see WithDashReplacement) and non-breaking spaces become regular spaces, which
+// makes PlainContent and PlainText easier to match for search and indexing.
+func WithNormalizePunctuation(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.NormalizePunctuation = enable
+	}
+}
+
+// WithDashReplacement sets the string that en and em dashes are replaced with
+// when punctuation normalization is enabled. The default is a single hyphen;
+// an empty string leaves dashes untouched.
+func WithDashReplacement(replacement string) Option {
+	return func(o *ExtractionOptions) {
+		o.DashReplacement = replacement
+	}
+}
+
+// WithContentLanguage sets the primary language of the content to extract.
+// Block-level elements carrying a conflicting lang attribute (for example lang="fr"
+// navigation on an English page) are removed before scoring. Inline foreign
+// phrases and elements without a lang attribute are kept, and regional variants
+// such as "en-GB" match "en".
+func WithContentLanguage(lang string) Option {
+	return func(o *ExtractionOptions) {
+		o.ContentLanguage = lang
+	}
+}
+
+// WithExtractAuthorImage enables or disables extraction of the author's profile image.
+// When enabled, Article.AuthorImageURL is taken from the JSON-LD author image or,
+// failing that, an image inside the byline element (e.g. ".author img"), resolved
+// against the document's base URL.
+func WithExtractAuthorImage(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractAuthorImage = enable
+	}
+}
+
+// WithExtractThemeColor enables or disables extraction of the page's theme color.
+// When enabled, Article.ThemeColor is taken from <meta name="theme-color"> and
+// normalized to lowercase hex when possible, so previews can match the colors
+// of the source site.
+func WithExtractThemeColor(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractThemeColor = enable
+	}
+}
+
+// WithExtractAlternates enables or disables listing of the page's alternates.
+// When enabled, Article.AlternateLinks lists the <link rel="alternate"> feeds
+// and translations and the <link rel="amphtml"> AMP version declared by the
+// page, with URLs made absolute against the page's base URL, for crawlers that
+// discover feeds or build a site graph.
+func WithExtractAlternates(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractAlternates = enable
+	}
+}
+
+// WithCollapseConsecutiveBreaks enables or disables collapsing of consecutive <br> elements.
+// By default any run of two or more <br> starts a new paragraph. When enabled, only runs
+// of at least ParagraphBreakThreshold (3) do, and shorter runs are reduced to at most
+// MaxLineBreaks (1) line breaks, which evens out spacing in pasted email or word content.
+func WithCollapseConsecutiveBreaks(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.CollapseBreaks = enable
+	}
+}
+
+// WithBreakCollapseLimits sets the limits used by WithCollapseConsecutiveBreaks:
+// the number of consecutive <br> that make a paragraph break, and the maximum
+// number of <br> kept from shorter runs.
+func WithBreakCollapseLimits(paragraphThreshold, maxLineBreaks int) Option {
+	return func(o *ExtractionOptions) {
+		o.ParagraphBreakThreshold = paragraphThreshold
+		o.MaxLineBreaks = maxLineBreaks
+	}
+}
+
+// WithTablesVerbatim enables or disables verbatim data tables in PlainContent.
+// When enabled, data tables are copied into PlainContent as they appear in Content,
+// minus class and style attributes, instead of going through the text normalization
+// passes. Cell structure such as rowspan and colspan is preserved for rendering.
+func WithTablesVerbatim(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.TablesVerbatim = enable
+	}
+}
+
+// WithExtractVideos enables or disables indexing of video embeds.
+// When enabled, Article.Videos lists the YouTube, Vimeo and other allowed video
+// embeds that are kept in Content, with their URL, provider, title and poster.
+// The embeds themselves stay in Content either way.
+func WithExtractVideos(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractVideos = enable
+	}
+}
+
+// WithExtractCodeBlocks enables or disables indexing of code blocks.
+// When enabled, Article.CodeBlocks lists the <pre> blocks kept in Content with
+// their code, their language from a data-lang attribute or a class such as
+// "language-go", and the figcaption of an enclosing <figure>, for rendering with
+// syntax highlighting. The language is also kept in Content as data-lang.
+func WithExtractCodeBlocks(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractCodeBlocks = enable
+	}
+}
+
+// WithExtractTables enables or disables indexing of data tables.
+// When enabled, Article.Tables lists the data tables kept in Content with their
+// header cells and rows as text, along with the <caption> and summary attribute
+// that describe them. Layout tables are left out.
+func WithExtractTables(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractTables = enable
+	}
+}
+
+// WithExtractQuotes enables or disables indexing of quotations.
+// When enabled, Article.Quotes lists the <blockquote> and <q> elements kept in
+// Content with their text, the absolute URL of their cite attribute, and their
+// attribution, taken from a <cite> element in the quote or the caption of the
+// figure around it.
+func WithExtractQuotes(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractQuotes = enable
+	}
+}
+
+// WithDetectPrimaryDocument enables or disables detection of document landing
+// pages. Some article pages are only a landing page for a linked PDF, with a
+// short summary and a "Download the report (PDF)" link. When enabled and the
+// content is that sparse, or the page's og:type is "document",
+// Article.IsDocumentLanding is set and Article.PrimaryDocumentURL holds the
+// URL of the PDF, so callers know the real content is the document. The PDF is
+// not fetched or parsed.
+func WithDetectPrimaryDocument(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.DetectPrimaryDocument = enable
+	}
+}
+
+// WithTrackRemovedLinks enables or disables tracking of removed links.
+// When enabled, Article.RemovedLinks lists every link that was in the article
+// region but was removed during cleanup, such as footer, navigation, share and
+// link-heavy related blocks, with the reason it was removed. Links dropped with
+// the rest of the page before the article region is chosen are not listed.
+func WithTrackRemovedLinks(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.TrackRemovedLinks = enable
+	}
+}
+
+// WithNormalizeHeadingWhitespace enables or disables heading text normalization.
+// When enabled (the default), a heading that spans several lines, with <br>
+// line breaks or line-wrapped markup, is put on a single line when it becomes
+// the Title or a PlainText heading block: line breaks are read as spaces and
+// runs of whitespace are collapsed. Content keeps the heading's markup.
+func WithNormalizeHeadingWhitespace(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.NormalizeHeadingWhitespace = enable
+	}
+}
+
+// WithHeadingBlocks enables or disables heading blocks in PlainText. When
+// enabled, the headings of the content are included in PlainText as blocks of
+// type BlockHeading with their level, so StructuredText can mark them up. By
+// default PlainText holds only paragraphs and list items.
+func WithHeadingBlocks(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.HeadingBlocks = enable
+	}
+}
+
+// WithDiscussionMode enables or disables discussion extraction.
+// When enabled, the comments of the page, such as the comments under a blog
+// post or the nested replies of a forum or Reddit-style discussion, are walked
+// into Article.Thread as posts with their author, timestamp and text, keeping
+// the reply hierarchy through Depth and ParentIndex. The article itself is
+// extracted as usual.
+func WithDiscussionMode(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.DiscussionMode = enable
+	}
+}
+
+// WithLiveBlogMode enables or disables live blog extraction.
+// Live blogs list timestamped updates, often newest first, in a container that
+// scores poorly as a single article. When enabled, the entries are recognized
+// by live blog markup, such as schema.org liveBlogUpdate items, or as a run of
+// sibling blocks that each hold a timestamp, and are extracted into
+// Article.Updates in chronological order. The article is extracted as usual.
+func WithLiveBlogMode(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.LiveBlogMode = enable
+	}
+}
+
+// WithExtractStructuredContent enables or disables reading of recipes and how-tos.
+// Recipe and how-to pages describe their ingredients and steps in schema.org
+// JSON-LD, which is more reliable than the list markup the article algorithm
+// has to work with. When enabled, the first Recipe and HowTo of the JSON-LD
+// fill Article.Recipe and Article.HowTo. The content is extracted as usual.
+func WithExtractStructuredContent(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractStructuredContent = enable
+	}
+}
+
+// WithExtractKeywords sets the number of keywords listed in Article.Keywords,
+// for tagging articles without tag metadata. Keywords are the words of the
+// content that appear most often, left out stopwords of the language the page
+// declares (English when it declares none), short words and numbers. This is
+// a simple frequency ranking, not linguistic analysis. 0, the default, lists
+// none.
+func WithExtractKeywords(n int) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractKeywords = n
+	}
+}
+
+// WithPullQuoteMode sets what extraction does with pull-quotes, the enlarged
+// restatements of a sentence that magazine layouts set apart, which repeat that
+// sentence in the text. Pull-quotes are elements with a pull-quote class, and
+// short asides and blockquotes whose text appears again in the page.
+// PullQuoteRemove removes them from the content, and PullQuoteExtract also
+// lists their text in Article.PullQuotes. The default, PullQuoteKeep, leaves
+// them in place.
+func WithPullQuoteMode(mode PullQuoteMode) Option {
+	return func(o *ExtractionOptions) {
+		o.PullQuoteMode = mode
+	}
+}
+
+// WithExtractContactInfo enables or disables collection of contact details.
+// When enabled, Article.ContactInfo lists the email addresses and phone
+// numbers of mailto: and tel: links and of the text of the content, without
+// duplicates. Phone numbers are reduced to their digits, so differently
+// formatted copies of a number are listed once. It suits contact and about pages.
+func WithExtractContactInfo(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExtractContactInfo = enable
+	}
+}
+
+// WithContentBoundaryMarkers enables or disables provenance comments in Content.
+// When enabled, Content starts with a comment naming the node the content was
+// extracted from, its score and how it was chosen, such as
+// "<!-- readabiligo:content-start selector=div#main score=42.5 source=candidate -->",
+// and ends with "<!-- readabiligo:content-end -->", for auditing stored output.
+func WithContentBoundaryMarkers(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ContentBoundaryMarkers = enable
+	}
+}
+
+// WithStripLeadingSymbols enables or disables removal of decorative title
+// prefixes. Some titles start with emoji, bullets or arrows, as in "🔥 Hot
+// Take", that clutter their display. When enabled, these are removed from the
+// start of Title, while symbols that can be part of it, such as "#" or "$", are
+// kept.
+func WithStripLeadingSymbols(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StripLeadingSymbols = enable
+	}
+}
+
+// WithStrictTitle enables or disables strict title resolution.
+// When enabled, extraction fails with ErrNoTitle unless the title is confident:
+// it matches a strong content heading (an h1 marked as the headline, or the only
+// h1 of the article), or at least two title sources agree on it, such as the
+// og:title and the <title>. A page with only a generic <title> is rejected.
+func WithStrictTitle(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StrictTitle = enable
+	}
+}
+
+// WithMinContentScore rejects pages whose best content candidate is weak.
+// Extraction fails with a ContentScoreError, which matches ErrNoContent, when
+// the score of the top candidate, lowered by its link density, is below
+// minScore in every attempt, instead of falling back to the whole body. The
+// error holds the score found, to calibrate the floor; articles of a few
+// paragraphs score about 20. An article body marked with microdata is not
+// scored and always passes. 0 disables the check.
+func WithMinContentScore(minScore float64) Option {
+	return func(o *ExtractionOptions) {
+		o.MinContentScore = minScore
+	}
+}
+
+// WithStripHeaderAnchors enables or disables removal of heading permalink anchors.
+// Documentation sites often add links such as <a class="headerlink" href="#id">¶</a>
+// to headings. These are removed by default, leaving the heading text; pass false
+// to keep them.
+func WithStripHeaderAnchors(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StripHeaderAnchors = enable
+	}
+}
+
+// WithTrimBoilerplateHeadings enables or disables removal of advertisement labels.
+// Labels such as "Advertisement" or "Sponsored Content" often remain as small
+// headings or paragraphs after the ads they labelled are removed. Blocks whose
+// entire text is one of the labels set with WithBoilerplateLabels are removed
+// by default; blocks that merely mention advertising are kept.
+func WithTrimBoilerplateHeadings(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.TrimBoilerplateHeadings = enable
+	}
+}
+
+// WithBoilerplateLabels sets the advertisement labels removed by
+// WithTrimBoilerplateHeadings, replacing the default list of
+// DefaultBoilerplateLabels. Labels are matched against the whole text of a
+// block, ignoring case and surrounding punctuation.
+func WithBoilerplateLabels(labels ...string) Option {
+	return func(o *ExtractionOptions) {
+		o.BoilerplateLabels = labels
+	}
+}
+
+// DefaultBoilerplateLabels returns the advertisement labels removed by default,
+// such as "Advertisement", "Sponsored Content" and "Promoted".
+func DefaultBoilerplateLabels() []string {
+	return append([]string(nil), readability.BoilerplateLabels...)
+}
+
+// WithStripEmptyAnchors enables or disables removal of content-less links.
+// Cleanup can leave anchors with no text and no children, which are useless and
+// can break rendering. These are removed by default; anchors wrapping an image
+// or other media are always kept.
+func WithStripEmptyAnchors(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StripEmptyAnchors = enable
+	}
+}
+
+// WithStripHiddenText enables or disables removal of hidden text.
+// Elements hidden with display:none, visibility:hidden, the hidden attribute or
+// aria-hidden="true" usually hold SEO keyword stuffing or copy-protection filler
+// rather than article text, so they are removed by default. Removal is skipped
+// for paywall content, whose hidden text is deliberately un-hidden.
+func WithStripHiddenText(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StripHiddenText = enable
+	}
+}
+
+// WithStripConsentBanners enables or disables removal of cookie consent banners.
+// GDPR consent banners, such as OneTrust's, often survive extraction as a
+// leading block of the content. When enabled, as it is by default, banners are
+// removed by the ids and classes of common consent tools and by their text
+// ("we use cookies", "accept all").
+func WithStripConsentBanners(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StripConsentBanners = enable
+	}
+}
+
+// WithStripBylineFromContent enables or disables removal of the in-content byline.
+// When enabled, once the byline has been captured into Article.Byline, the
+// byline element (such as a .byline or .author element, or a rel="author" link)
+// that repeats it is removed from Content, where it is redundant. It is disabled
+// by default for compatibility.
+func WithStripBylineFromContent(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.StripBylineFromContent = enable
+	}
+}
+
+// WithPreserveLinks enables or disables link lists on plain text blocks.
+// When enabled, each Block in PlainText carries the href and anchor text of
+// the links it contains, so consumers can map links to their context without
+// re-parsing the content.
+func WithPreserveLinks(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.PreserveLinks = enable
+	}
+}
+
+// WithMinifyOutput enables or disables whitespace minification of the output.
+// When enabled, insignificant whitespace between elements is removed from Content
+// and PlainContent, which can reduce the payload size of large articles. Text and
+// the contents of <pre> elements are left unchanged.
+func WithMinifyOutput(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.MinifyOutput = enable
+	}
+}
+
+// WithCollapseWhitespaceInAttributes enables or disables whitespace collapsing in
+// attribute values. When enabled, runs of spaces and newlines left from the
+// source markup in alt, title and aria-label values are collapsed to single
+// spaces and trimmed, so they read cleanly in tooltips and screen readers.
+func WithCollapseWhitespaceInAttributes(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.CollapseWhitespaceInAttributes = enable
+	}
+}
+
+// WithMaxImages caps the number of images kept in Content, for text-focused
+// consumers of gallery-heavy pages. The first maxImages images in document order
+// are kept and the rest are removed, along with a <picture> or <figure> left
+// empty; the lead image (the one matching the page's og:image, or else the first
+// one not known to be smaller than 100 pixels, like an icon or tracking pixel)
+// is always among those kept. Article.ImageCount records how many images the
+// article had. 0 means no limit.
+func WithMaxImages(maxImages int) Option {
+	return func(o *ExtractionOptions) {
+		o.MaxImages = maxImages
+	}
+}
+
+// WithImageDimensionInference enables or disables inference of image sizes from
+// URLs. Many CDNs encode the size of an image in its URL, as in
+// "photo-1200x800.jpg", "/w_1200,h_800/" or "?w=1200". When enabled, images
+// without width and height attributes get the size their URL gives, so that
+// icons and tracking pixels are passed over when choosing the lead image.
+func WithImageDimensionInference(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ImageDimensionInference = enable
+	}
+}
+
+// WithProtectLeadParagraphs keeps the first n substantial paragraphs of the
+// content region through conditional cleaning, so that an article's opening
+// isn't dropped when it sits in a differently styled container, such as a
+// .lede or .standfirst block with a prominent link. Paragraphs shorter than 25
+// characters don't count. 0 protects none.
+func WithProtectLeadParagraphs(n int) Option {
+	return func(o *ExtractionOptions) {
+		o.ProtectLeadParagraphs = n
+	}
+}
+
+// WithContentMaxLength caps the length of the extracted content, for previews.
+// Once the text of Content reaches maxLength characters, the remaining blocks are
+// dropped; a paragraph, heading or list item is never cut in the middle, and the
+// HTML stays well-formed. PlainContent and PlainText follow Content, and
+// Article.IsTruncated is set when anything was dropped. 0 means no limit.
+func WithContentMaxLength(maxLength int) Option {
+	return func(o *ExtractionOptions) {
+		o.ContentMaxLength = maxLength
+	}
+}
+
+// WithPreserveMath enables or disables preservation of mathematical notation.
+// When enabled, MathML equations keep their attributes in Content, the LaTeX
+// source that MathJax keeps in <script type="math/tex"> is kept as \(...\) or
+// \[...\] text, and PlainText renders MathML as LaTeX when the equation carries
+// a TeX annotation or alttext. Inline LaTeX such as $...$ is always kept as text.
+func WithPreserveMath(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.PreserveMath = enable
+	}
+}
+
+// WithRenderMathAsText enables or disables rendering of equations as text.
+// Without WithPreserveMath, MathML equations are dropped from the output. When
+// enabled, each is replaced with a plain text rendering such as "(a + b)/2",
+// "x^2" or "√x", or its alttext when its markup gives none, and the LaTeX
+// source that MathJax keeps in scripts is kept as \(...\) text. WithPreserveMath
+// takes precedence for MathML.
+func WithRenderMathAsText(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.RenderMathAsText = enable
+	}
+}
+
+// WithPreserveKbdAndSamp enables or disables preservation of <kbd> (keyboard
+// input) and <samp> (sample output) elements as bare tags. When enabled, they
+// are kept in PlainContent with their attributes stripped, so rendered output
+// can style technical text like "press Ctrl+C" consistently. By default they
+// are left as they are.
+func WithPreserveKbdAndSamp(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.PreserveKbdAndSamp = enable
+	}
+}
+
+// WithParallelScoring enables or disables parallel content scoring.
+// Scoring the paragraphs and other content elements of very large pages takes
+// most of the extraction time. When enabled, the elements are scored on one
+// goroutine per CPU and their scores then added up in document order, so the
+// result is the same as with serial scoring. Small pages gain little from it.
+func WithParallelScoring(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ParallelScoring = enable
+	}
+}
+
+// WithReorderColumns enables or disables reordering of multi-column layouts.
+// Some pages lay out columns in rows, so the DOM alternates between them and
+// the text reads across the columns. When enabled, layouts whose columns are
+// marked by a class such as "column-2" or "col-right", a data-column attribute
+// or the CSS order and grid-column properties are put in reading order, left
+// to right and top to bottom.
+func WithReorderColumns(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ReorderColumns = enable
+	}
+}
+
+// WithPreserveSemanticStyles enables or disables conversion of meaningful
+// inline styles. Style attributes are always stripped, which loses emphasis and
+// edit marks that some pages only express with CSS. When enabled, bold text
+// (font-weight bold or at least 600) becomes <strong>, italic text <em>,
+// line-through text <del> and underlined text <ins> before styles are stripped.
+func WithPreserveSemanticStyles(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.PreserveSemanticStyles = enable
+	}
+}
+
+// WithSentenceSegmentation enables or disables sentence segmentation of PlainText.
+// When enabled, each block is split into one block per sentence, which suits
+// sentence-level embedding and indexing. Sentences keep the type and level of
+// their block, and Block.ParentIndex is the index the block would have had
+// without segmentation. Abbreviations such as "Dr." and "e.g." and decimals do
+// not end a sentence.
+func WithSentenceSegmentation(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.SentenceSegmentation = enable
+	}
+}
+
+// WithTextOnly enables or disables text-only extraction, a faster mode for
+// callers that only need the text, such as search indexers. The article is
+// extracted as usual, but PlainText is built by a single walk of its text
+// instead of running the simplifier, PlainContent holds the text of the blocks
+// separated by blank lines, and Content is left empty. Options that act on the
+// simplified HTML, such as content digests, node indexes, preserved links and
+// excluded selectors, have no effect in this mode, and neither do the options
+// that format Content: WithCollapseWhitespaceInAttributes, WithMinifyOutput
+// and WithContentBoundaryMarkers.
+func WithTextOnly(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.TextOnly = enable
+	}
+}
+
+// WithExpandDetails enables or disables expansion of collapsible content.
+// When enabled, each <details> element becomes a visible section: its <summary>
+// is turned into a heading and the hidden content follows it. This keeps the
+// questions and answers of FAQ pages and similar collapsible content.
+func WithExpandDetails(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.ExpandDetails = enable
+	}
+}
+
+// WithDehyphenate enables or disables joining of words broken across lines.
+// Text from OCR or PDF-to-HTML conversion often breaks words at line ends with a
+// hyphen, which shows up as "exam- ple" in the extracted text. When enabled,
+// "exam-\nple" becomes "example". The hyphen is kept for likely compounds, such
+// as "well-\nknown" becoming "well-known", and for words hyphenated elsewhere
+// in the article. Preformatted text is left as is.
+func WithDehyphenate(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.Dehyphenate = enable
+	}
+}
+
+// WithNormalizeListMarkers enables or disables conversion of pasted list markers.
+// Content copied from documents often writes lists as paragraphs that start with
+// "• ", "- " or "1. ". When enabled, runs of such sibling paragraphs become
+// <ul> and <ol> lists. A run needs two items, or three for "-" and "*" since
+// dialogue is also written with dashes, and numbers must count up from 1.
+func WithNormalizeListMarkers(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.NormalizeListMarkers = enable
+	}
+}
+
+// WithMergeImageAltIntoCaption enables or disables captions made from alt text.
+// Renderers show no caption for a <figure> without a <figcaption>, even when its
+// image has a descriptive alt text. When enabled, such figures get a
+// <figcaption> holding the alt text, as long as the figure has a single image
+// and the alt text has at least two words and doesn't look like a file name.
+func WithMergeImageAltIntoCaption(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.MergeImageAltIntoCaption = enable
+	}
+}
+
+// WithPreserveGalleries enables or disables the keeping of image galleries.
+// Conditional cleaning removes containers with many images and little text as
+// cruft, which loses the photos of a photo essay. When enabled, a container of
+// at least four images, most of them captioned, with few links and less prose
+// than captions is kept as content.
+func WithPreserveGalleries(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.PreserveGalleries = enable
+	}
+}
+
+// WithMergeListsAcrossParagraphs enables or disables rejoining of split lists.
+// Removing an element from the middle of a list, such as an inline advertisement,
+// can leave two adjacent lists where the page had one, which restarts numbering.
+// Adjacent lists of the same type and class are merged by default.
+func WithMergeListsAcrossParagraphs(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.MergeListsAcrossParagraphs = enable
+	}
+}
+
+// WithCMSContentClasses sets the content container classes of CMS themes that
+// get a scoring bonus, replacing the default list of DefaultCMSContentClasses.
+// The bonus helps the article container win over similarly sized sidebars on
+// WordPress, Drupal and Ghost sites. Calling it without classes disables the
+// bonus.
+func WithCMSContentClasses(classes ...string) Option {
+	return func(o *ExtractionOptions) {
+		o.CMSContentClasses = classes
+	}
+}
+
+// DefaultCMSContentClasses returns the content container classes of common CMS
+// themes, such as WordPress's "entry-content" and Ghost's "gh-content", that get
+// a scoring bonus by default.
+func DefaultCMSContentClasses() []string {
+	return append([]string(nil), readability.CMSContentClasses...)
+}
+
+// WithUnlikelyRoles adds ARIA roles to those of DefaultUnlikelyRoles, whose
+// elements are dropped before scoring, for sites that put boilerplate in
+// elements with roles such as "search" or "form". Roles that aren't WAI-ARIA
+// roles are reported in Article.Warnings.
+func WithUnlikelyRoles(roles ...string) Option {
+	return func(o *ExtractionOptions) {
+		o.UnlikelyRoles = append(o.UnlikelyRoles, roles...)
+	}
+}
+
+// WithAllowedRoles exempts ARIA roles from role-based removal, for sites that
+// misuse a role such as "complementary" on their main content. Roles that
+// aren't WAI-ARIA roles are reported in Article.Warnings.
+func WithAllowedRoles(roles ...string) Option {
+	return func(o *ExtractionOptions) {
+		o.AllowedRoles = append(o.AllowedRoles, roles...)
+	}
+}
+
+// DefaultUnlikelyRoles returns the ARIA roles, such as "navigation" and
+// "complementary", whose elements are dropped before scoring by default.
+func DefaultUnlikelyRoles() []string {
+	return append([]string(nil), readability.UnlikelyRoles...)
+}
+
+// WithAllowedSchemes sets the URL schemes of the links and images kept in the
+// content, replacing the default list of DefaultAllowedSchemes. Links with any
+// other scheme are unwrapped into plain text and such images are removed;
+// relative URLs are always kept. javascript: URLs are always removed, and data:
+// URLs are only kept on images. Calling it without schemes allows every scheme.
+func WithAllowedSchemes(schemes ...string) Option {
+	return func(o *ExtractionOptions) {
+		o.AllowedSchemes = schemes
+	}
+}
+
+// DefaultAllowedSchemes returns the URL schemes kept by default: http, https,
+// mailto, and data for inline images.
+func DefaultAllowedSchemes() []string {
+	return append([]string(nil), readability.AllowedSchemes...)
+}
+
+// WithContentTypeRule adds a rule that applies the given content type, and its
+// cleanup, to pages on which selector matches, regardless of the content type
+// they would otherwise get. Rules are evaluated in the order they were added and
+// the first one whose selector matches wins. For example, matching a site's
+// paywall container with ContentTypePaywall keeps its hidden article text.
+func WithContentTypeRule(selector string, contentType ContentType) Option {
+	return func(o *ExtractionOptions) {
+		o.ContentTypeRules = append(o.ContentTypeRules, ContentTypeRule{Selector: selector, ContentType: contentType})
+	}
+}
+
+// WithRetryStrategy sets the heuristics that extraction relaxes, in order, when
+// the article it finds is shorter than CharThreshold. Each step extracts the page
+// again with the steps so far relaxed, stopping once the article is long enough.
+// The default is DefaultRetryStrategy, which is also used when RetryStrategy is
+// nil; with no steps the first attempt is kept unless it is still too short, in
+// which case the whole body is used as always.
+func WithRetryStrategy(steps ...RetryStep) Option {
+	return func(o *ExtractionOptions) {
+		o.RetryStrategy = append([]RetryStep{}, steps...)
+	}
+}
+
+// DefaultRetryStrategy returns the retry steps used by default: keeping unlikely
+// candidates, then ignoring class weights, then skipping conditional cleaning.
+func DefaultRetryStrategy() []RetryStep {
+	return []RetryStep{RetryStripUnlikelys, RetryWeightClasses, RetryCleanConditionally}
+}
+
+// WithScoringProfile applies a preset of scoring settings tuned for a class of
+// content, instead of tuning CharThreshold, LinkDensityModifier, KeepStructure
+// and PreserveImportantLinks individually. A profile only sets the settings it
+// tunes, listed below, and leaves the others as they are; options given after
+// the profile override its settings.
+//
+//   - ProfileDefault: 500 character threshold, standard link density limits.
+//   - ProfileNews: slightly stricter link density limits to drop related-story boxes.
+//   - ProfileWiki: tolerates link-heavy text, keeps lists and "See also" style
+//     sections, and preserves "more information" links.
+//   - ProfileBlog: 300 character threshold for shorter posts, more tolerant of links.
+//   - ProfileForum: 140 character threshold for short posts, more tolerant of links.
+func WithScoringProfile(profile ScoringProfile) Option {
+	return func(o *ExtractionOptions) {
+		switch profile {
+		case ProfileNews:
+			o.CharThreshold = 500
+			o.LinkDensityModifier = -0.05
+		case ProfileWiki:
+			o.CharThreshold = 500
+			o.LinkDensityModifier = 0.25
+			o.KeepStructure = true
+			o.PreserveImportantLinks = true
+		case ProfileBlog:
+			o.CharThreshold = 300
+			o.LinkDensityModifier = 0.1
+		case ProfileForum:
+			o.CharThreshold = 140
+			o.LinkDensityModifier = 0.1
+		default:
+			o.CharThreshold = 500
+			o.LinkDensityModifier = 0
+		}
+	}
+}
+
+// WithForcedEncoding makes ExtractFromReader decode its input from the named
+// encoding (such as "utf-8" or "windows-1251") instead of detecting it from a
+// byte order mark, a <meta> charset declaration or the content. This helps
+// diagnose mojibake together with Article.DetectedCharset. An empty name
+// restores detection; an unknown name makes extraction fail.
+func WithForcedEncoding(name string) Option {
+	return func(o *ExtractionOptions) {
+		o.ForcedEncoding = name
+	}
+}
+
+// WithByteOrderMarkHandling enables or disables byte order mark handling.
+// When enabled, as it is by default and for options built as a literal, a
+// leading byte order mark is stripped from the input before parsing, so no
+// invisible character ends up at the start of the title or content, and UTF-16
+// input with a byte order mark is transcoded to UTF-8. Disabling it sets
+// ExtractionOptions.KeepByteOrderMark.
+func WithByteOrderMarkHandling(enable bool) Option {
+	return func(o *ExtractionOptions) {
+		o.KeepByteOrderMark = !enable
+	}
+}
+
 // WithMaxBufferSize sets the maximum buffer size for content processing.
 // This limits the amount of memory used during extraction for very large documents.
 func WithMaxBufferSize(size int) Option {
@@ -98,6 +1015,79 @@ func WithMaxBufferSize(size int) Option {
 	}
 }
 
+// WithReferenceTime sets the time recorded as Article.ExtractedAt instead of the
+// current time. This keeps results reproducible, for example in tests.
+func WithReferenceTime(t time.Time) Option {
+	return func(o *ExtractionOptions) {
+		o.ReferenceTime = t
+	}
+}
+
+// WithAssumeTimezone sets the location of publication dates that carry no
+// timezone information, such as "January 2, 2006" or "2006-01-02T15:04:05".
+// By default only RFC 3339 dates are read, since a date without an offset
+// can't be placed in time; with a location, dates in other formats are parsed
+// too and interpreted in it, and pages whose metadata has no date take the
+// one shown on the page, preferring a date next to the byline over others
+// such as a footer date. Dates with an explicit offset are not affected.
+func WithAssumeTimezone(loc *time.Location) Option {
+	return func(o *ExtractionOptions) {
+		o.AssumeTimezone = loc
+	}
+}
+
+// WithMetadataExtractor registers custom metadata extractors. They run after the
+// built-in OpenGraph, microdata and JSON-LD extractors, in registration order,
+// and their fields are merged into Article.Metadata. When several extractors
+// return the same key, the one that runs last wins.
+func WithMetadataExtractor(extractors ...MetadataExtractor) Option {
+	return func(o *ExtractionOptions) {
+		o.MetadataExtractors = append(o.MetadataExtractors, extractors...)
+	}
+}
+
+// WithPostExtractHook registers a function that is called on the article node
+// once it has been grabbed, prepared and had its URLs made absolute and its
+// classes cleaned, before the final cleanup and before PlainContent and
+// PlainText are derived from it. The hook operates on the live selection, so
+// its mutations affect the final output; it can, for example, rewrite a site's
+// custom embed markup. Classes it adds are seen by the cleanup that follows and
+// are kept in Content. ExtractWithVariants may call the hook more than once.
+func WithPostExtractHook(hook func(*goquery.Selection)) Option {
+	return func(o *ExtractionOptions) {
+		o.PostExtractHook = hook
+	}
+}
+
+// WithLogger sets a logger for extraction debug events, such as the template
+// content promoted, the hidden elements and consent banners removed, and the
+// elements removed during cleanup. Events are logged at slog.LevelDebug with
+// the attributes "phase", "action" and, when the event concerns an element,
+// "node" (e.g. "div#main.content"). Nothing is written to stdout.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *ExtractionOptions) {
+		o.Logger = logger
+	}
+}
+
+// OpenGraphExtractor returns the built-in extractor for OpenGraph meta tags.
+// It sets title, excerpt, siteName, image, url, type, date and byline.
+func OpenGraphExtractor() MetadataExtractor {
+	return readability.OpenGraphExtractor{}
+}
+
+// JSONLDExtractor returns the built-in extractor for JSON-LD article metadata.
+// It sets title, byline, excerpt, date, siteName and authorImage.
+func JSONLDExtractor() MetadataExtractor {
+	return readability.JSONLDExtractor{}
+}
+
+// MicrodataExtractor returns the built-in extractor for schema.org microdata.
+// It sets the same keys as JSONLDExtractor.
+func MicrodataExtractor() MetadataExtractor {
+	return readability.MicrodataExtractor{}
+}
+
 // WithTimeout sets the timeout duration for extraction.
 // This prevents extraction from hanging indefinitely on problematic documents.
 func WithTimeout(timeout time.Duration) Option {
@@ -106,6 +1096,37 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithPhaseTimeouts sets time budgets for phases of the extraction, so that a
+// slow phase can't use up the whole timeout set with WithTimeout. A parse or
+// extract phase that runs over its budget fails the extraction with a
+// PhaseTimeoutError. A text phase that runs over falls back to the single walk
+// of WithTextOnly for PlainContent and PlainText, keeping Content, and adds a
+// warning to Article.Warnings. Phases without a budget are only bound by the
+// overall timeout.
+func WithPhaseTimeouts(budgets map[Phase]time.Duration) Option {
+	return func(o *ExtractionOptions) {
+		o.PhaseTimeouts = budgets
+	}
+}
+
+// WithBatchJobs sets the number of documents ExtractBatch extracts
+// concurrently. Values below 1 extract one document at a time.
+func WithBatchJobs(jobs int) Option {
+	return func(o *ExtractionOptions) {
+		o.BatchJobs = jobs
+	}
+}
+
+// WithOutputCallback sets a function that ExtractBatch calls with each result
+// as soon as it completes, so that outputs can be written while the rest of the
+// batch is still being extracted. Calls are never concurrent, and with more
+// than one job they follow completion order rather than input order.
+func WithOutputCallback(callback func(BatchResult)) Option {
+	return func(o *ExtractionOptions) {
+		o.OutputCallback = callback
+	}
+}
+
 // articleExtractor is the concrete implementation of the Extractor interface.
 // It handles the pure Go extraction method.
 type articleExtractor struct {
@@ -119,6 +1140,9 @@ func (e *articleExtractor) ExtractFromHTML(html string, options *ExtractionOptio
 	if options == nil {
 		options = &e.options
 	}
+	if !options.KeepByteOrderMark {
+		html = simplifiers.StripByteOrderMark(html)
+	}
 
 	// Create a channel for the result
 	resultCh := make(chan struct {
@@ -133,7 +1157,7 @@ func (e *articleExtractor) ExtractFromHTML(html string, options *ExtractionOptio
 
 		
 		// Use pure Go implementation
-		article, err = e.extractUsingPureGo(html, options)
+		article, err = e.extractUsingPureGo(html, options, false)
 
 		// Send the result to the channel
 		resultCh <- struct {
@@ -152,33 +1176,320 @@ func (e *articleExtractor) ExtractFromHTML(html string, options *ExtractionOptio
 }
 
 // ExtractFromReader extracts article content from an io.Reader.
-// It reads the entire content from the reader and passes it to ExtractFromHTML.
+// It reads the entire content from the reader, decodes it to UTF-8 from the
+// detected or forced encoding, and passes it to ExtractFromHTML. If reading
+// fails after part of the document was received, the article is extracted from
+// that part, flagged with Article.IsPartial, and the read error is recorded in
+// Article.Warnings.
 func (e *articleExtractor) ExtractFromReader(r io.Reader, options *ExtractionOptions) (*Article, error) {
 	if options == nil {
 		options = &e.options
 	}
 
-	// Read the entire content from the reader
-	html, err := io.ReadAll(r)
+	// Read the entire content from the reader, keeping what was received if
+	// reading fails part way through
+	html, readErr := io.ReadAll(r)
+	if readErr != nil && len(html) == 0 {
+		return nil, readErr
+	}
+
+	document, detected, err := simplifiers.DecodeHTML(html, options.ForcedEncoding)
 	if err != nil {
 		return nil, err
 	}
 
-	return e.ExtractFromHTML(string(html), options)
+	article, err := e.ExtractFromHTML(document, options)
+	if err != nil {
+		return nil, err
+	}
+	article.DetectedCharset = detected
+	if readErr != nil {
+		article.IsPartial = true
+		article.Warnings = append(article.Warnings, fmt.Sprintf("reading input failed after %d bytes: %v", len(html), readErr))
+	}
+	return article, nil
+}
+
+// ExtractWithVariants extracts article content from an HTML string twice: once
+// keeping the strict first attempt, and once with the usual fallback to relaxed
+// heuristics for short content. Callers can choose between the variants with
+// their own quality checks. An error is returned only if the lenient pass fails.
+func (e *articleExtractor) ExtractWithVariants(html string, options *ExtractionOptions) (*ArticleVariants, error) {
+	if options == nil {
+		options = &e.options
+	}
+	if !options.KeepByteOrderMark {
+		html = simplifiers.StripByteOrderMark(html)
+	}
+
+	// Create a channel for the result
+	resultCh := make(chan struct {
+		variants *ArticleVariants
+		err      error
+	}, 1)
+
+	// Start the extraction in a goroutine
+	go func() {
+		variants := &ArticleVariants{}
+		var err error
+
+		variants.Lenient, err = e.extractUsingPureGo(html, options, false)
+		if err == nil {
+			// A page without content under the strict heuristics has no strict variant
+			if strict, strictErr := e.extractUsingPureGo(html, options, true); strictErr == nil {
+				variants.Strict = strict
+			}
+		}
+
+		// Send the result to the channel
+		resultCh <- struct {
+			variants *ArticleVariants
+			err      error
+		}{variants, err}
+	}()
+
+	// Wait for the result or timeout
+	select {
+	case result := <-resultCh:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return result.variants, nil
+	case <-time.After(options.Timeout):
+		return nil, fmt.Errorf("extraction timed out after %v", options.Timeout)
+	}
+}
+
+// ExtractAll extracts every distinct article-like region of a page, such as the
+// posts of a forum thread or the entries of a category page, as a separate
+// Article, in document order. Regions are the innermost elements whose content
+// scores at least a quarter of the best region's score; they never overlap.
+// Each Article is titled by its region's first heading, or by the page title.
+// Pages without any article-like region yield an empty slice.
+func (e *articleExtractor) ExtractAll(html string, options *ExtractionOptions) ([]*Article, error) {
+	if options == nil {
+		options = &e.options
+	}
+	if !options.KeepByteOrderMark {
+		html = simplifiers.StripByteOrderMark(html)
+	}
+
+	// Create a channel for the result
+	resultCh := make(chan struct {
+		articles []*Article
+		err      error
+	}, 1)
+
+	// Start the extraction in a goroutine
+	go func() {
+		articles, err := e.extractAllUsingPureGo(html, options)
+
+		// Send the result to the channel
+		resultCh <- struct {
+			articles []*Article
+			err      error
+		}{articles, err}
+	}()
+
+	// Wait for the result or timeout
+	select {
+	case result := <-resultCh:
+		return result.articles, result.err
+	case <-time.After(options.Timeout):
+		return nil, fmt.Errorf("extraction timed out after %v", options.Timeout)
+	}
+}
+
+// extractAllUsingPureGo finds the article regions of a page and extracts each one
+func (e *articleExtractor) extractAllUsingPureGo(html string, options *ExtractionOptions) ([]*Article, error) {
+	regions, err := readability.FindArticleRegions(html)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]*Article, 0, len(regions))
+	for _, region := range regions {
+		article, err := e.extractUsingPureGo(region, options, false)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+// ExtractBatch extracts each input with ExtractFromReader, running up to
+// BatchJobs extractions at once. It returns a result for every input, in input
+// order; a document that cannot be opened or extracted has its error recorded
+// in its result without stopping the rest of the batch. The timeout applies to
+// each document separately. If OutputCallback is set, it is also called with
+// each result as it completes.
+func (e *articleExtractor) ExtractBatch(inputs []BatchInput, options *ExtractionOptions) []BatchResult {
+	if options == nil {
+		options = &e.options
+	}
+	jobs := options.BatchJobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	if jobs > len(inputs) {
+		jobs = len(inputs)
+	}
+
+	// Workers extract inputs from a shared queue
+	indexes := make(chan int)
+	completed := make(chan BatchResult)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				completed <- e.extractBatchInput(index, inputs[index], options)
+			}
+		}()
+	}
+	go func() {
+		for index := range inputs {
+			indexes <- index
+		}
+		close(indexes)
+		wg.Wait()
+		close(completed)
+	}()
+
+	// Collect the results on this goroutine so that callbacks never overlap
+	results := make([]BatchResult, len(inputs))
+	for result := range completed {
+		results[result.Index] = result
+		if options.OutputCallback != nil {
+			options.OutputCallback(result)
+		}
+	}
+	return results
+}
+
+// extractBatchInput opens and extracts a single input of a batch
+func (e *articleExtractor) extractBatchInput(index int, input BatchInput, options *ExtractionOptions) BatchResult {
+	result := BatchResult{Index: index, Name: input.Name}
+	r, err := input.Open()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer r.Close()
+
+	result.Article, result.Err = e.ExtractFromReader(r, options)
+	return result
+}
+
+// ExtractStreaming extracts text blocks from an io.Reader without building a
+// document tree, calling onBlock for each block as soon as it is identified.
+// Memory use stays constant regardless of document size, which suits batch
+// ingestion of very large documents.
+//
+// This trades accuracy for memory. Blocks are judged one at a time by their
+// tags, attributes and link density instead of by the full scoring algorithm,
+// so some boilerplate may be included and some short content may be missed.
+// No metadata is extracted, blocks carry only their text, and the extraction
+// options, including the timeout, do not apply.
+func (e *articleExtractor) ExtractStreaming(r io.Reader, onBlock func(Block)) error {
+	return readability.ExtractStreaming(r, func(block readability.Block) {
+		onBlock(Block{Text: block.Text})
+	})
 }
 
 // extractUsingPureGo implements the pure Go extraction logic.
 // This is used when Readability.js is not available or when explicitly requested.
-func (e *articleExtractor) extractUsingPureGo(html string, options *ExtractionOptions) (*Article, error) {
+// When strict is set, the first extraction attempt is kept even if it is short.
+func (e *articleExtractor) extractUsingPureGo(html string, options *ExtractionOptions, strict bool) (*Article, error) {
 	// Convert our options to internal options
 	internalOptions := &readability.ExtractionOptions{
 		ContentDigests:        options.ContentDigests,
 		NodeIndexes:           options.NodeIndexes,
 		MaxBufferSize:         options.MaxBufferSize,
 		Timeout:               int(options.Timeout.Seconds()),
+		PhaseTimeouts:         options.PhaseTimeouts,
 		PreserveImportantLinks: options.PreserveImportantLinks,
-		DetectContentType:     options.DetectContentType,
 		ContentType:           readability.ContentType(options.ContentType),
+		ContentTypeRules:      contentTypeRules(options.ContentTypeRules),
+		ExtractTemplates:      options.ExtractTemplates,
+		UseNoscriptFallback:   options.UseNoscriptFallback,
+		QuoteStyle:            simplifiers.QuoteStyle(options.QuoteStyle),
+		ComputeReadingLevel:   options.ComputeReadingLevel,
+		PreserveIDs:           options.PreserveIDs,
+		StripAutogeneratedIDs: options.StripAutogeneratedIDs,
+		AutogeneratedIDPatterns: options.AutogeneratedIDPatterns,
+		ExcludePlainTextSelectors: options.ExcludePlainTextSelectors,
+		NormalizePunctuation:  options.NormalizePunctuation,
+		DashReplacement:       options.DashReplacement,
+		ContentLanguage:       options.ContentLanguage,
+		ExtractAuthorImage:    options.ExtractAuthorImage,
+		ExtractThemeColor:     options.ExtractThemeColor,
+		ExtractAlternates:     options.ExtractAlternates,
+		CollapseBreaks:        options.CollapseBreaks,
+		ParagraphBreakThreshold: options.ParagraphBreakThreshold,
+		MaxLineBreaks:         options.MaxLineBreaks,
+		TablesVerbatim:        options.TablesVerbatim,
+		ExtractVideos:         options.ExtractVideos,
+		ExtractCodeBlocks:     options.ExtractCodeBlocks,
+		ExtractTables:         options.ExtractTables,
+		ExtractQuotes:         options.ExtractQuotes,
+		DetectPrimaryDocument: options.DetectPrimaryDocument,
+		TrackRemovedLinks:     options.TrackRemovedLinks,
+		DiscussionMode:        options.DiscussionMode,
+		LiveBlogMode:          options.LiveBlogMode,
+		ExtractStructuredContent: options.ExtractStructuredContent,
+		ExtractContactInfo:    options.ExtractContactInfo,
+		ExtractKeywords:       options.ExtractKeywords,
+		ContentBoundaryMarkers: options.ContentBoundaryMarkers,
+		StrictTitle:           options.StrictTitle,
+		MinContentScore:       options.MinContentScore,
+		StripHeaderAnchors:    options.StripHeaderAnchors,
+		TrimBoilerplateHeadings: options.TrimBoilerplateHeadings,
+		BoilerplateLabels:     options.BoilerplateLabels,
+		NormalizeHeadingWhitespace: options.NormalizeHeadingWhitespace,
+		HeadingBlocks:         options.HeadingBlocks,
+		StripEmptyAnchors:     options.StripEmptyAnchors,
+		StripHiddenText:       options.StripHiddenText,
+		StripConsentBanners:   options.StripConsentBanners,
+		StripBylineFromContent: options.StripBylineFromContent,
+		PreserveLinks:         options.PreserveLinks,
+		MinifyOutput:          options.MinifyOutput,
+		CollapseWhitespaceInAttributes: options.CollapseWhitespaceInAttributes,
+		ContentMaxLength:      options.ContentMaxLength,
+		MaxImages:             options.MaxImages,
+		ImageDimensionInference: options.ImageDimensionInference,
+		ProtectLeadParagraphs: options.ProtectLeadParagraphs,
+		PreserveMath:          options.PreserveMath,
+		RenderMathAsText:      options.RenderMathAsText,
+		PreserveKbdAndSamp:    options.PreserveKbdAndSamp,
+		ReorderColumns:        options.ReorderColumns,
+		StripLeadingSymbols:   options.StripLeadingSymbols,
+		ParallelScoring:       options.ParallelScoring,
+		PullQuoteMode:         readability.PullQuoteMode(options.PullQuoteMode),
+		PreserveSemanticStyles: options.PreserveSemanticStyles,
+		SentenceSegmentation:  options.SentenceSegmentation,
+		TextOnly:              options.TextOnly,
+		CharThreshold:         options.CharThreshold,
+		LinkDensityModifier:   options.LinkDensityModifier,
+		CMSContentClasses:     options.CMSContentClasses,
+		UnlikelyRoles:         options.UnlikelyRoles,
+		AllowedRoles:          options.AllowedRoles,
+		AllowedSchemes:        options.AllowedSchemes,
+		KeepStructure:         options.KeepStructure,
+		ExpandDetails:         options.ExpandDetails,
+		Dehyphenate:           options.Dehyphenate,
+		NormalizeListMarkers:  options.NormalizeListMarkers,
+		MergeImageAltIntoCaption: options.MergeImageAltIntoCaption,
+		PreserveGalleries:     options.PreserveGalleries,
+		MergeListsAcrossParagraphs: options.MergeListsAcrossParagraphs,
+		AssumeTimezone:        options.AssumeTimezone,
+		MetadataExtractors:    metadataExtractors(options.MetadataExtractors),
+		PostExtractHook:       options.PostExtractHook,
+		Logger:                options.Logger,
+		DisableFallback:       strict,
+		RetryFlags:            retryFlags(options.RetryStrategy),
 	}
 
 	// Use our pure Go Readability implementation
@@ -189,20 +1500,163 @@ func (e *articleExtractor) extractUsingPureGo(html string, options *ExtractionOp
 
 	// Convert internal article to our public type
 	article := &Article{
+		SchemaVersion: SchemaVersion,
 		Title:        internalArticle.Title,
 		Byline:       internalArticle.Byline,
 		Content:      internalArticle.Content,
 		PlainContent: internalArticle.PlainContent,
 		ContentType:  ContentType(internalArticle.ContentType),
+		ReadabilityScore: internalArticle.ReadabilityScore,
+		AuthorImageURL:   internalArticle.AuthorImageURL,
+		ThemeColor:       internalArticle.ThemeColor,
+		Metadata:         internalArticle.Metadata,
+		ImageCount:       internalArticle.ImageCount,
+		IsTruncated:      internalArticle.IsTruncated,
+		FullContentURL:   internalArticle.FullContentURL,
+		IsDocumentLanding:  internalArticle.IsDocumentLanding,
+		PrimaryDocumentURL: internalArticle.PrimaryDocumentURL,
+		Confidence:         internalArticle.Confidence,
+		Warnings:           internalArticle.Warnings,
+		ExtractedAt:      options.ReferenceTime,
+	}
+
+	// Flag input that was cut off before the end of the document
+	article.IsPartial = simplifiers.IsTruncatedHTML(html)
+
+	// Record the extraction time unless a reference time was given
+	if article.ExtractedAt.IsZero() {
+		article.ExtractedAt = time.Now()
 	}
 
+	// Convert internal alternate links to our alternate links
+	for _, link := range internalArticle.AlternateLinks {
+		article.AlternateLinks = append(article.AlternateLinks, AlternateLink{
+			Type:     link.Type,
+			Href:     link.Href,
+			Title:    link.Title,
+			Hreflang: link.Hreflang,
+		})
+	}
+
+	// Convert internal video embeds to our video embeds
+	for _, video := range internalArticle.Videos {
+		article.Videos = append(article.Videos, VideoEmbed{
+			URL:      video.URL,
+			Provider: video.Provider,
+			Title:    video.Title,
+			Poster:   video.Poster,
+		})
+	}
+
+	// Convert internal code blocks to our code blocks
+	for _, block := range internalArticle.CodeBlocks {
+		article.CodeBlocks = append(article.CodeBlocks, CodeBlock{
+			Language: block.Language,
+			Caption:  block.Caption,
+			Code:     block.Code,
+		})
+	}
+
+	// Convert internal tables to our tables
+	for _, table := range internalArticle.Tables {
+		article.Tables = append(article.Tables, Table{
+			Caption: table.Caption,
+			Summary: table.Summary,
+			Headers: table.Headers,
+			Rows:    table.Rows,
+		})
+	}
+
+	// Convert internal quotes to our quotes
+	for _, quote := range internalArticle.Quotes {
+		article.Quotes = append(article.Quotes, Quote{
+			Text:        quote.Text,
+			CiteURL:     quote.CiteURL,
+			Attribution: quote.Attribution,
+		})
+	}
+
+	// Convert internal removed links to our removed links
+	for _, link := range internalArticle.RemovedLinks {
+		article.RemovedLinks = append(article.RemovedLinks, RemovedLink{
+			Href:   link.Href,
+			Text:   link.Text,
+			Reason: link.Reason,
+		})
+	}
+
+	// Convert internal thread posts to our thread posts
+	for _, post := range internalArticle.Thread {
+		article.Thread = append(article.Thread, ThreadPost{
+			Author:      post.Author,
+			Timestamp:   post.Timestamp,
+			Body:        post.Body,
+			Depth:       post.Depth,
+			ParentIndex: post.ParentIndex,
+		})
+	}
+
+	// Convert internal live blog entries to our live blog entries
+	for _, update := range internalArticle.Updates {
+		article.Updates = append(article.Updates, LiveUpdate{
+			Timestamp: update.Timestamp,
+			Body:      update.Body,
+		})
+	}
+
+	// Convert the internal recipe and how-to to ours
+	if recipe := internalArticle.Recipe; recipe != nil {
+		article.Recipe = &Recipe{
+			Name:        recipe.Name,
+			Description: recipe.Description,
+			Ingredients: recipe.Ingredients,
+			Steps:       howToSteps(recipe.Steps),
+			PrepTime:    recipe.PrepTime,
+			CookTime:    recipe.CookTime,
+			TotalTime:   recipe.TotalTime,
+			Yield:       recipe.Yield,
+		}
+	}
+	if howTo := internalArticle.HowTo; howTo != nil {
+		article.HowTo = &HowTo{
+			Name:        howTo.Name,
+			Description: howTo.Description,
+			Supplies:    howTo.Supplies,
+			Tools:       howTo.Tools,
+			Steps:       howToSteps(howTo.Steps),
+			TotalTime:   howTo.TotalTime,
+			Yield:       howTo.Yield,
+		}
+	}
+
+	// Convert the internal contact details to ours
+	if info := internalArticle.ContactInfo; info != nil {
+		article.ContactInfo = &ContactInfo{Emails: info.Emails, Phones: info.Phones}
+	}
+	article.PullQuotes = internalArticle.PullQuotes
+	article.Keywords = internalArticle.Keywords
+	article.ContentHash = internalArticle.ContentHash
+
 	// Convert internal blocks to our blocks
 	article.PlainText = make([]Block, len(internalArticle.PlainText))
 	for i, block := range internalArticle.PlainText {
 		article.PlainText[i] = Block{
 			Text:      block.Text,
+			Type:      BlockType(block.Type),
+			Level:     block.Level,
 			NodeIndex: block.NodeIndex,
 		}
+		if options.SentenceSegmentation {
+			parentIndex := block.ParentIndex
+			article.PlainText[i].ParentIndex = &parentIndex
+		}
+		for _, link := range block.Links {
+			article.PlainText[i].Links = append(article.PlainText[i].Links, Link{
+				Href: link.Href,
+				Text: link.Text,
+				Rel:  link.Rel,
+			})
+		}
 	}
 
 	// Set date if available
@@ -213,6 +1667,69 @@ func (e *articleExtractor) extractUsingPureGo(html string, options *ExtractionOp
 	return article, nil
 }
 
+// metadataExtractors converts custom metadata extractors to the internal interface
+func metadataExtractors(extractors []MetadataExtractor) []readability.MetadataExtractor {
+	if len(extractors) == 0 {
+		return nil
+	}
+	converted := make([]readability.MetadataExtractor, len(extractors))
+	for i, extractor := range extractors {
+		converted[i] = extractor
+	}
+	return converted
+}
+
+// retryFlags converts retry steps to the flags the internal implementation
+// clears, skipping unknown steps. Nil steps stay nil, for the default retries.
+func retryFlags(steps []RetryStep) []int {
+	if steps == nil {
+		return nil
+	}
+	flags := make([]int, 0, len(steps))
+	for _, step := range steps {
+		switch step {
+		case RetryStripUnlikelys:
+			flags = append(flags, readability.FlagStripUnlikelys)
+		case RetryWeightClasses:
+			flags = append(flags, readability.FlagWeightClasses)
+		case RetryCleanConditionally:
+			flags = append(flags, readability.FlagCleanConditionally)
+		}
+	}
+	return flags
+}
+
+// contentTypeRules converts content type rules to the internal type
+func contentTypeRules(rules []ContentTypeRule) []readability.ContentTypeRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	converted := make([]readability.ContentTypeRule, len(rules))
+	for i, rule := range rules {
+		converted[i] = readability.ContentTypeRule{
+			Selector:    rule.Selector,
+			ContentType: readability.ContentType(rule.ContentType),
+		}
+	}
+	return converted
+}
+
+// howToSteps converts the steps of a recipe or how-to to our type
+func howToSteps(steps []readability.HowToStep) []HowToStep {
+	if len(steps) == 0 {
+		return nil
+	}
+	converted := make([]HowToStep, len(steps))
+	for i, step := range steps {
+		converted[i] = HowToStep{
+			Section: step.Section,
+			Name:    step.Name,
+			Text:    step.Text,
+		}
+	}
+	return converted
+}
+
 // New creates a new Extractor instance with the provided options.
 // It returns an implementation of the Extractor interface that can be used
 // to extract article content from HTML.
@@ -232,4 +1749,4 @@ func New(opts ...Option) Extractor {
 	return &articleExtractor{
 		options: options,
 	}
-}
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package readabiligo_test
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -63,4 +64,150 @@ func TestOptions(t *testing.T) {
 	if !strings.Contains(article.PlainContent, "data-node-index") {
 		t.Error("Expected node indexes to be added")
 	}
-}
\ No newline at end of file
+}
+func TestSchemaVersion(t *testing.T) {
+	ext := readabiligo.New()
+
+	html := `<html><head><title>Schema Test</title></head><body><article><h1>Schema Test</h1><p>This is a test paragraph with enough text to be considered relevant content by the Readability algorithm. We need to ensure that this paragraph has sufficient length to be scored highly by the content extraction algorithm.</p></article></body></html>`
+
+	article, err := ext.ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+
+	if article.SchemaVersion != readabiligo.SchemaVersion {
+		t.Errorf("Expected schema version %q, got %q", readabiligo.SchemaVersion, article.SchemaVersion)
+	}
+
+	data, err := json.Marshal(article)
+	if err != nil {
+		t.Fatalf("Failed to marshal article: %v", err)
+	}
+	if !strings.Contains(string(data), `"schema_version":"`+readabiligo.SchemaVersion+`"`) {
+		t.Errorf("Expected schema_version in JSON output, got %s", data)
+	}
+}
+
+func TestArticleJSONDate(t *testing.T) {
+	// A zero date is omitted rather than encoded as Go's zero time
+	data, err := json.Marshal(readabiligo.Article{Title: "No Date"})
+	if err != nil {
+		t.Fatalf("Failed to marshal article: %v", err)
+	}
+	if strings.Contains(string(data), `"date"`) {
+		t.Errorf("Expected zero date to be omitted, got %s", data)
+	}
+
+	// A known date is encoded in RFC 3339 format
+	date := time.Date(2024, 3, 1, 10, 30, 0, 123, time.FixedZone("CET", 3600))
+	data, err = json.Marshal(&readabiligo.Article{Title: "Dated", Date: date})
+	if err != nil {
+		t.Fatalf("Failed to marshal article: %v", err)
+	}
+	if !strings.Contains(string(data), `"date":"2024-03-01T10:30:00+01:00"`) {
+		t.Errorf("Expected RFC 3339 date, got %s", data)
+	}
+
+	// Indented output matches json.MarshalIndent
+	article := readabiligo.Article{Title: "Indented", Date: date}
+	indented, err := article.MarshalJSONIndent("", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal article: %v", err)
+	}
+	want, _ := json.MarshalIndent(article, "", "  ")
+	if string(indented) != string(want) || !strings.Contains(string(indented), "\n  \"title\": \"Indented\"") {
+		t.Errorf("Unexpected indented output: %s", indented)
+	}
+}
+
+func TestExtractedAt(t *testing.T) {
+	html := `<html><head><title>Timestamp Test</title></head><body><article><h1>Timestamp Test</h1><p>This is a test paragraph with enough text to be considered relevant content by the Readability algorithm.</p></article></body></html>`
+
+	// The reference time is recorded when provided
+	reference := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	article, err := readabiligo.New(readabiligo.WithReferenceTime(reference)).ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if !article.ExtractedAt.Equal(reference) {
+		t.Errorf("Expected ExtractedAt %v, got %v", reference, article.ExtractedAt)
+	}
+
+	data, err := json.Marshal(article)
+	if err != nil {
+		t.Fatalf("Failed to marshal article: %v", err)
+	}
+	if !strings.Contains(string(data), `"extracted_at":"2024-05-06T07:08:09Z"`) {
+		t.Errorf("Expected extracted_at in JSON output, got %s", data)
+	}
+
+	// Otherwise the current time is recorded
+	before := time.Now()
+	article, err = readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.ExtractedAt.Before(before) || article.ExtractedAt.After(time.Now()) {
+		t.Errorf("Expected ExtractedAt to be the extraction time, got %v", article.ExtractedAt)
+	}
+}
+
+func TestStructuredText(t *testing.T) {
+	html := `<html><head><title>Gardening Guide</title></head><body><article>
+		<h2><span>Getting started</span></h2>
+		<p><span>Growing vegetables at home is easier than most people think, and it only takes a little planning to get a good harvest.</span></p>
+		<ul>
+			<li><span>Pick a sunny spot</span></li>
+			<li><span>Improve the soil with compost</span></li>
+		</ul>
+		<blockquote><p><span>The best fertilizer is the gardener's shadow.</span></p></blockquote>
+	</article></body></html>`
+
+	article, err := readabiligo.New(readabiligo.WithHeadingBlocks(true)).ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+
+	want := "## Getting started\n\n" +
+		"Growing vegetables at home is easier than most people think, and it only takes a little planning to get a good harvest.\n\n" +
+		"- Pick a sunny spot\n" +
+		"- Improve the soil with compost\n\n" +
+		"> The best fertilizer is the gardener's shadow.\n"
+	if got := readabiligo.StructuredText(article.PlainText); got != want {
+		t.Errorf("Unexpected structured text:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Headings are only included in PlainText when requested
+	plain, err := readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	for _, block := range plain.PlainText {
+		if block.Type == readabiligo.BlockHeading {
+			t.Errorf("Expected no heading blocks by default, got %q", block.Text)
+		}
+	}
+
+	// Nested list items are indented and nested quotes repeat the marker
+	blocks := []readabiligo.Block{
+		{Text: "Outer", Type: readabiligo.BlockListItem, Level: 1},
+		{Text: "Inner", Type: readabiligo.BlockListItem, Level: 2},
+		{Text: "Quoted reply", Type: readabiligo.BlockQuote, Level: 2},
+	}
+	if got := readabiligo.StructuredText(blocks); got != "- Outer\n  - Inner\n\n> > Quoted reply\n" {
+		t.Errorf("Unexpected structured text for nested blocks: %q", got)
+	}
+
+	// Block types are encoded by name
+	data, err := json.Marshal(article.PlainText[0])
+	if err != nil {
+		t.Fatalf("Failed to marshal block: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"heading","level":2`) {
+		t.Errorf("Expected block type and level in JSON output, got %s", data)
+	}
+	var block readabiligo.Block
+	if err := json.Unmarshal(data, &block); err != nil || block.Type != readabiligo.BlockHeading {
+		t.Errorf("Expected block type to round-trip, got %v (%v)", block.Type, err)
+	}
+}
@@ -216,6 +216,94 @@ func BenchmarkFeatureOverhead(b *testing.B) {
 	}
 }
 
+// BenchmarkTextOnlyExtraction compares full extraction with the text-only mode,
+// which skips the simplifier and leaves Content empty, on the large fixture
+func BenchmarkTextOnlyExtraction(b *testing.B) {
+	testFile := filepath.Join("data", "benchmarkinghuge.html")
+
+	// Check if file exists
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		b.Skipf("Test file %s does not exist, skipping", testFile)
+		return
+	}
+
+	// Read the file content once
+	htmlBytes, err := os.ReadFile(testFile)
+	if err != nil {
+		b.Fatalf("Failed to read test file: %v", err)
+	}
+	htmlContent := string(htmlBytes)
+
+	modes := []struct {
+		name     string
+		textOnly bool
+	}{
+		{"Full", false},
+		{"TextOnly", true},
+	}
+
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			ext := readabiligo.New(readabiligo.WithTextOnly(mode.textOnly))
+
+			// Reset the timer before the loop
+			b.ResetTimer()
+
+			// Run the benchmark
+			for i := 0; i < b.N; i++ {
+				_, err := ext.ExtractFromHTML(htmlContent, nil)
+				if err != nil {
+					b.Fatalf("Failed to extract article: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkParallelScoring compares serial and parallel content scoring on the
+// large fixture; the gain grows with the number of CPUs
+func BenchmarkParallelScoring(b *testing.B) {
+	testFile := filepath.Join("data", "benchmarkinghuge.html")
+
+	// Check if file exists
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		b.Skipf("Test file %s does not exist, skipping", testFile)
+		return
+	}
+
+	// Read the file content once
+	htmlBytes, err := os.ReadFile(testFile)
+	if err != nil {
+		b.Fatalf("Failed to read test file: %v", err)
+	}
+	htmlContent := string(htmlBytes)
+
+	modes := []struct {
+		name     string
+		parallel bool
+	}{
+		{"Serial", false},
+		{"Parallel", true},
+	}
+
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			ext := readabiligo.New(readabiligo.WithParallelScoring(mode.parallel))
+
+			// Reset the timer before the loop
+			b.ResetTimer()
+
+			// Run the benchmark
+			for i := 0; i < b.N; i++ {
+				_, err := ext.ExtractFromHTML(htmlContent, nil)
+				if err != nil {
+					b.Fatalf("Failed to extract article: %v", err)
+				}
+			}
+		})
+	}
+}
+
 // BenchmarkDOMOperations focuses on specific DOM operations that are performance-critical
 func BenchmarkDOMOperations(b *testing.B) {
 	// Define test cases for different HTML complexities
@@ -268,9 +268,9 @@ func TestPaywallContentExtraction(t *testing.T) {
 			extractor: readabiligo.New(
 				readabiligo.WithDetectContentType(true),
 			),
-			expectedContentType: "Paywall",
+			expectedContentType: "Article", // Content types are never detected, so this matches the default
 			expectedElements: map[string]int{
-				".premium-content": 12, // With paywall detection, we now preserve more structure
+				".premium-content": 0,
 				".paywall": 0,
 				"h2": 2,
 				"blockquote": 1,
@@ -379,4 +379,65 @@ func countTextInElements(doc *goquery.Document, selector, text string) int {
 		}
 	})
 	return count
-}
\ No newline at end of file
+}
+
+// TestContentTypeOptions checks that Article.ContentType reports what the
+// options promise: content types are never detected, WithContentType assigns a
+// content type to every page, and a matching WithContentTypeRule wins.
+func TestContentTypeOptions(t *testing.T) {
+	html := `<html><head><title>Subscriber exclusive: the water report</title></head><body><article>
+		<h1>The water report</h1>
+		<p><span>` + strings.Repeat("The report found that the new filters reduce water usage across the region. ", 6) + `</span></p>
+		<div class="paywall"><p><span>Subscribe to continue reading this article.</span></p></div>
+	</article></body></html>`
+
+	tests := []struct {
+		name    string
+		options []readabiligo.Option
+		want    readabiligo.ContentType
+	}{
+		{"default", nil, readabiligo.ContentTypeArticle},
+		{"detection has no effect", []readabiligo.Option{readabiligo.WithDetectContentType(true)}, readabiligo.ContentTypeArticle},
+		{"assigned to every page", []readabiligo.Option{readabiligo.WithContentType(readabiligo.ContentTypePaywall)}, readabiligo.ContentTypePaywall},
+		{"matching rule", []readabiligo.Option{readabiligo.WithContentTypeRule(".paywall", readabiligo.ContentTypePaywall)}, readabiligo.ContentTypePaywall},
+		{"rule wins", []readabiligo.Option{
+			readabiligo.WithContentType(readabiligo.ContentTypeTechnical),
+			readabiligo.WithContentTypeRule(".paywall", readabiligo.ContentTypePaywall),
+		}, readabiligo.ContentTypePaywall},
+		{"rule not matching", []readabiligo.Option{
+			readabiligo.WithContentType(readabiligo.ContentTypeTechnical),
+			readabiligo.WithContentTypeRule(".metered", readabiligo.ContentTypePaywall),
+		}, readabiligo.ContentTypeTechnical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article, err := readabiligo.New(tt.options...).ExtractFromHTML(html, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, article.ContentType)
+		})
+	}
+}
+
+// TestMinimalPageFormConsistency checks that the login form of a page assigned
+// the Minimal content type is kept in both Content and PlainContent
+func TestMinimalPageFormConsistency(t *testing.T) {
+	html := `<html><head><title>Sign in to your account</title></head><body>
+		<div class="login-container"><h1>Sign in to your account</h1>
+		<p><span>Enter your email address and password to continue to your dashboard.</span></p>
+		<form action="/login" method="post">
+			<label for="email">Email</label><input id="email" name="email" type="email">
+			<label for="password">Password</label><input id="password" name="password" type="password">
+			<button type="submit">Sign in</button>
+		</form></div></body></html>`
+
+	ex := readabiligo.New(readabiligo.WithContentTypeRule(`form input[type="password"]`, readabiligo.ContentTypeMinimal))
+	article, err := ex.ExtractFromHTML(html, nil)
+	require.NoError(t, err)
+	assert.Equal(t, readabiligo.ContentTypeMinimal, article.ContentType)
+	for name, output := range map[string]string{"Content": article.Content, "PlainContent": article.PlainContent} {
+		assert.Contains(t, output, `<form action="/login"`, name)
+		assert.Contains(t, output, `type="password"`, name)
+		assert.Contains(t, output, `<button type="submit">Sign in</button>`, name)
+	}
+}
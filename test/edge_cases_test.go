@@ -324,8 +324,8 @@ func testPaywallContent(t *testing.T, htmlContent string) {
 	t.Logf("Standard extraction content type: %s", standardArticle.ContentType.String())
 	t.Logf("Content-aware extraction content type: %s", contentAwareArticle.ContentType.String())
 	
-	// Content-aware extraction should detect paywall content type
-	assert.Equal(t, "Paywall", contentAwareArticle.ContentType.String(), "Content-aware extraction should detect paywall content type")
+	// Content types are never detected, so the deprecated option leaves the default
+	assert.Equal(t, "Article", contentAwareArticle.ContentType.String(), "WithDetectContentType should have no effect")
 	
 	// Parse the articles with goquery for testing
 	standardDoc, err := goquery.NewDocumentFromReader(strings.NewReader(standardArticle.Content))
@@ -0,0 +1,2637 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mrjoshuak/readabiligo"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExtractTemplates tests that article content rendered into a <template>
+// is only promoted when template extraction is enabled
+func TestExtractTemplates(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+	<title>Component Rendered Article</title>
+</head>
+<body>
+	<div id="app"><p>Loading…</p></div>
+	<template id="article-body">
+		<article>
+			<h1>Component Rendered Article</h1>
+			<p>Some frameworks server-render the article body into a template element, relying on client-side code to stamp it into the page once the application boots.</p>
+			<p>Without that code running, the visible document is little more than a loading placeholder, even though the full text of the article has been delivered with the response.</p>
+			<p>Promoting the template content lets the extractor score the real article instead of falling back to the empty shell that browsers would otherwise display.</p>
+		</article>
+	</template>
+</body>
+</html>`
+
+	t.Run("Disabled", func(t *testing.T) {
+		ex := readabiligo.New()
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		// The article is left as inert template content
+		assert.Contains(t, article.Content, "<template")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithExtractTemplates(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, "server-render the article body")
+		assert.Contains(t, article.Content, "Promoting the template content")
+		assert.NotContains(t, article.Content, "<template")
+		assert.NotContains(t, article.Content, "Loading")
+	})
+}
+
+// TestUseNoscriptFallback tests that an article kept in a <noscript> element
+// is only promoted when the noscript fallback is enabled
+func TestUseNoscriptFallback(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+	<title>Script Rendered Article</title>
+</head>
+<body>
+	<div id="root"></div>
+	<noscript>
+		<article>
+			<h1>Script Rendered Article</h1>
+			<p>Some sites render the whole article with client-side code and leave the server response with nothing but an empty root element for the application to fill.</p>
+			<p>For clients that do not run scripts, a static copy of the article is placed inside a noscript element, which readers never see when scripting is enabled.</p>
+			<p>Search engines and archives have long relied on that copy, since it carries the same headline, paragraphs and links as the rendered page.</p>
+			<p>Promoting the noscript content lets the extractor recover the article from the static copy instead of returning an empty page.</p>
+		</article>
+	</noscript>
+</body>
+</html>`
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, article.PlainContent, "static copy of the article")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithUseNoscriptFallback(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.PlainContent, "static copy of the article")
+		assert.Contains(t, article.PlainContent, "Promoting the noscript content")
+		assert.NotContains(t, article.Content, "<noscript")
+		assert.NotContains(t, article.Content, "&lt;p&gt;")
+	})
+}
+
+// TestComputeReadingLevel tests that reading level scoring distinguishes
+// simple text from complex text and is skipped for non-English documents
+func TestComputeReadingLevel(t *testing.T) {
+	page := func(lang, body string) string {
+		return `<!DOCTYPE html>
+<html lang="` + lang + `">
+<head><title>Reading Level Sample Article</title></head>
+<body><article><h1>Reading Level Sample Article</h1>` + body + `</article></body>
+</html>`
+	}
+
+	simple := page("en", strings.Repeat(`<p>The cat sat on the mat. The dog ran to the park. We had fun in the sun. It was a good day. The kids ate cake and drank milk. Then they went home to bed.</p>`, 4))
+	complex := page("en", strings.Repeat(`<p>Notwithstanding considerable institutional resistance, interdisciplinary collaboration among computational linguists, cognitive psychologists, and educational administrators has substantially transformed contemporary understanding of comprehension difficulty, particularly regarding morphologically sophisticated vocabulary and syntactically elaborate constructions.</p>`, 4))
+
+	ex := readabiligo.New(readabiligo.WithComputeReadingLevel(true))
+
+	simpleArticle, err := ex.ExtractFromHTML(simple, nil)
+	assert.NoError(t, err)
+	complexArticle, err := ex.ExtractFromHTML(complex, nil)
+	assert.NoError(t, err)
+
+	assert.NotZero(t, complexArticle.ReadabilityScore)
+	assert.Greater(t, complexArticle.ReadabilityScore, simpleArticle.ReadabilityScore+5)
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(complex, nil)
+		assert.NoError(t, err)
+		assert.Zero(t, article.ReadabilityScore)
+	})
+
+	t.Run("NonEnglish", func(t *testing.T) {
+		article, err := ex.ExtractFromHTML(strings.Replace(complex, `lang="en"`, `lang="de"`, 1), nil)
+		assert.NoError(t, err)
+		assert.Zero(t, article.ReadabilityScore)
+	})
+}
+
+// TestPreserveIDs tests that heading ids survive extraction, duplicates are
+// made unique and in-page anchors stay relative when ids are preserved
+func TestPreserveIDs(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+	<title>Deep Linking Guide</title>
+	<base href="https://example.com/docs/">
+</head>
+<body>
+	<article>
+		<h1>Deep Linking Guide</h1>
+		<p>This guide explains how to link directly to a section, for example <a href="#usage">the usage section</a>, without leaving the page you are reading right now.</p>
+		<h2 id="usage"><em>Usage</em></h2>
+		<p>Fragment identifiers point at element ids, so extracted content needs to keep those ids intact for links, bookmarks, and tables of contents to keep working.</p>
+		<h2 id="usage"><em>Usage again</em></h2>
+		<p>Templates frequently repeat ids by accident, which makes the document invalid and leaves browsers to guess which of the duplicate targets a fragment refers to.</p>
+	</article>
+</body>
+</html>`
+
+	ex := readabiligo.New(readabiligo.WithPreserveIDs(true))
+	article, err := ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+
+	assert.Contains(t, article.Content, `id="usage"`)
+	assert.Contains(t, article.Content, `id="usage-2"`)
+	assert.Equal(t, 1, strings.Count(article.Content, `id="usage"`))
+	assert.Contains(t, article.Content, `href="#usage"`)
+	assert.Contains(t, article.PlainContent, `id="usage-2"`)
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, article.Content, `id="usage-2"`)
+		assert.Contains(t, article.Content, `href="https://example.com/docs/#usage"`)
+	})
+}
+
+// TestStripAutogeneratedIDs tests that generated ids are removed while
+// authored ids survive, and that the patterns can be replaced
+func TestStripAutogeneratedIDs(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Getting started with sourdough</title></head>
+<body>
+	<div id="gatsby-focus-wrapper">
+		<article id="a1b2c3d4e5">
+			<h2 id="section-intro"><em>Introduction</em></h2>
+			<p id=":r3:">A sourdough starter is a mix of flour and water in which wild yeast and bacteria live, and it takes about a week of daily feeding before it can raise a loaf.</p>
+			<h2 id="feeding"><em>Feeding the starter</em></h2>
+			<p>Discard half of the starter every day and feed it with equal weights of flour and water, keeping it somewhere warm so that it doubles within a few hours.</p>
+		</article>
+	</div>
+</body>
+</html>`
+
+	ex := readabiligo.New(readabiligo.WithPreserveIDs(true), readabiligo.WithStripAutogeneratedIDs(true))
+	article, err := ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, `id="section-intro"`)
+	assert.Contains(t, article.Content, `id="feeding"`)
+	assert.NotContains(t, article.Content, `id="a1b2c3d4e5"`)
+	assert.NotContains(t, article.Content, `id=":r3:"`)
+	assert.NotContains(t, article.Content, `gatsby-focus-wrapper`)
+
+	t.Run("CustomPatterns", func(t *testing.T) {
+		ex := readabiligo.New(
+			readabiligo.WithStripAutogeneratedIDs(true),
+			readabiligo.WithAutogeneratedIDPatterns(regexp.MustCompile(`^feed`)),
+		)
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, `id="a1b2c3d4e5"`)
+		assert.NotContains(t, article.Content, `id="feeding"`)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New(readabiligo.WithPreserveIDs(true)).ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, `id="a1b2c3d4e5"`)
+	})
+}
+
+// TestExcludePlainText tests that text inside excluded elements is left out
+// of the PlainText blocks while remaining in Content
+func TestExcludePlainText(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Configuring the Query Planner</title></head>
+<body>
+	<article>
+		<h1>Configuring the Query Planner</h1>
+		<p>The planner chooses a strategy for every statement, and <em>tuning its cost settings</em> can make a large difference for workloads that join many tables or scan wide indexes.</p>
+		<pre><ul><li><code>SET planner_cost = 4;</code></li></ul></pre>
+		<p>Each setting applies to the current session only, so <strong>persist the values</strong> in the server configuration once you are happy with the results of your experiments.</p>
+		<table>
+			<tr><th>Setting</th><th>Default</th></tr>
+			<tr><td><p><code>planner_cost</code> is the relative cost of a sequential page fetch</p></td><td><p><code>4.0</code></p></td></tr>
+		</table>
+	</article>
+</body>
+</html>`
+
+	hasText := func(blocks []readabiligo.Block, text string) bool {
+		for _, block := range blocks {
+			if strings.Contains(block.Text, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.True(t, hasText(article.PlainText, "SET planner_cost"))
+		assert.True(t, hasText(article.PlainText, "planner_cost"))
+	})
+
+	t.Run("Excluded", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithExcludePlainText("pre", "table"))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.True(t, hasText(article.PlainText, "tuning its cost settings"))
+		assert.True(t, hasText(article.PlainText, "persist the values"))
+		assert.False(t, hasText(article.PlainText, "SET planner_cost"))
+		assert.False(t, hasText(article.PlainText, "4.0"))
+		assert.Contains(t, article.Content, "SET planner_cost")
+		assert.Contains(t, article.Content, "<table")
+	})
+}
+
+// TestNormalizePunctuation tests that typographic punctuation is canonicalized
+// in PlainText only when punctuation normalization is enabled
+func TestNormalizePunctuation(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Typography Notes</title></head>
+<body>
+	<article>
+		<h1>Typography Notes</h1>
+		<p>Publishing systems love typographic punctuation, which is great for readers but awkward for exact matching: <em>“smart” quotes — long dashes, 9–5 ranges and 100&nbsp;km</em> all look familiar yet compare differently.</p>
+		<p>Search indexes usually want a canonical form, so that a query typed on an ordinary keyboard still finds text that was written with fancy characters in a content management system.</p>
+	</article>
+</body>
+</html>`
+
+	hasText := func(blocks []readabiligo.Block, text string) bool {
+		for _, block := range blocks {
+			if strings.Contains(block.Text, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		// Standard text normalization folds quotes and spells em dashes as "--"
+		assert.True(t, hasText(article.PlainText, `"smart" quotes -- long dashes, 9-5 ranges`))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithNormalizePunctuation(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.True(t, hasText(article.PlainText, `"smart" quotes - long dashes, 9-5 ranges and 100 km`))
+		assert.Contains(t, article.Content, "“smart”")
+	})
+
+	t.Run("DashReplacement", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithNormalizePunctuation(true), readabiligo.WithDashReplacement("--"))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.True(t, hasText(article.PlainText, `"smart" quotes -- long dashes, 9--5 ranges`))
+	})
+}
+
+// TestContentLanguage tests that elements declaring a different language
+// are dropped before scoring when a content language is set
+func TestContentLanguage(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html lang="en">
+<head><title>Visiting the Coast in Autumn</title></head>
+<body>
+	<div class="page">
+		<div class="story">
+			<h1>Visiting the Coast in Autumn</h1>
+			<p>Autumn is the best season to visit the coast, because the summer crowds have gone home, the water is still warm enough for a swim, and the light is soft all day long.</p>
+			<p>Most of the small harbour towns keep their restaurants open until November, so you can still enjoy fresh fish, long walks along the cliffs, and quiet evenings by the sea.</p>
+			<p lang="en-GB">Pack a jumper, though: the evening wind off the water can be surprisingly chilly, even after a sunny afternoon on the beach.</p>
+			<p>End the day at a harbour café with a plate of mussels and wish the table next to you <i lang="fr">bon appétit</i> before the sun sets.</p>
+		</div>
+		<div lang="fr">
+			<p>Découvrez aussi nos autres guides de voyage, nos conseils pour voyager en famille, et notre sélection des plus beaux villages de la côte, mis à jour chaque saison par notre équipe.</p>
+		</div>
+	</div>
+</body>
+</html>`
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, "Découvrez aussi")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithContentLanguage("en"))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, "Autumn is the best season")
+		assert.Contains(t, article.Content, "Pack a jumper")
+		assert.Contains(t, article.Content, "bon appétit")
+		assert.NotContains(t, article.Content, "Découvrez aussi")
+	})
+}
+
+// TestExtractFragment tests that HTML fragments without <html> or <body>
+// are extracted as body content instead of failing
+func TestExtractFragment(t *testing.T) {
+	fragment := `<div class="field-body">
+	<p>Content management systems often store article bodies as bare HTML fragments, without any surrounding document, so the extractor has to cope with input that starts straight at a div.</p>
+	<p>Those fragments should be treated exactly like the body of a full page, keeping every paragraph in order and producing the same shape of output as a complete document would.</p>
+</div>`
+
+	article, err := readabiligo.New().ExtractFromHTML(fragment, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "bare HTML fragments")
+	assert.Contains(t, article.Content, "every paragraph in order")
+
+	t.Run("Frameset", func(t *testing.T) {
+		_, err := readabiligo.New().ExtractFromHTML(`<html><frameset><frame src="nav.html"></frameset></html>`, nil)
+		assert.NoError(t, err)
+	})
+}
+
+// TestExtractAuthorImage tests that the author's profile image is taken from
+// JSON-LD or the byline, resolved against the document base URL
+func TestExtractAuthorImage(t *testing.T) {
+	page := func(head, byline string) string {
+		return `<!DOCTYPE html>
+<html>
+<head>
+	<title>Growing Tomatoes on a Balcony</title>
+	<base href="https://example.com/garden/">
+	` + head + `
+</head>
+<body>
+	<article>
+		<h1>Growing Tomatoes on a Balcony</h1>
+		` + byline + `
+		<p>Tomatoes are surprisingly happy in containers, as long as they get at least six hours of direct sun, a deep pot, and a steady supply of water during the hottest weeks of summer.</p>
+		<p>Choose a compact variety, add a sturdy stake early on, and feed the plants every two weeks once the first flowers appear to keep the fruit coming until autumn.</p>
+	</article>
+</body>
+</html>`
+	}
+
+	jsonLD := `<script type="application/ld+json">{"@context": "https://schema.org", "@type": "BlogPosting", "headline": "Growing Tomatoes on a Balcony", "author": {"@type": "Person", "name": "Jane Doe", "image": {"@type": "ImageObject", "url": "/avatars/jane.jpg"}}}</script>`
+
+	ex := readabiligo.New(readabiligo.WithExtractAuthorImage(true))
+
+	t.Run("JSONLD", func(t *testing.T) {
+		article, err := ex.ExtractFromHTML(page(jsonLD, ""), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/avatars/jane.jpg", article.AuthorImageURL)
+	})
+
+	t.Run("Byline", func(t *testing.T) {
+		article, err := ex.ExtractFromHTML(page("", `<div class="author"><img src="img/jane.png" alt=""> Jane Doe</div>`), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/garden/img/jane.png", article.AuthorImageURL)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(page(jsonLD, ""), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, article.AuthorImageURL)
+	})
+}
+
+// TestExtractThemeColor tests that the theme color is taken from the
+// theme-color meta tag and normalized to lowercase hex
+func TestExtractThemeColor(t *testing.T) {
+	page := func(head string) string {
+		return `<!DOCTYPE html>
+<html>
+<head>
+	<title>Growing Tomatoes on a Balcony</title>
+	` + head + `
+</head>
+<body>
+	<article>
+		<p>Tomatoes are surprisingly happy in containers, as long as they get at least six hours of direct sun, a deep pot, and a steady supply of water during the hottest weeks of summer.</p>
+		<p>Choose a compact variety, add a sturdy stake early on, and feed the plants every two weeks once the first flowers appear to keep the fruit coming until autumn.</p>
+	</article>
+</body>
+</html>`
+	}
+
+	ex := readabiligo.New(readabiligo.WithExtractThemeColor(true))
+
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"Hex", `<meta name="theme-color" content="#FF5500">`, "#ff5500"},
+		{"ShortHex", `<meta name="theme-color" content="#F50">`, "#ff5500"},
+		{"RGB", `<meta name="theme-color" content="rgb(255, 85, 0)">`, "#ff5500"},
+		{"Named", `<meta name="theme-color" content="Tomato">`, "tomato"},
+		{"PreferNoMedia", `<meta name="theme-color" media="(prefers-color-scheme: dark)" content="#000000"><meta name="theme-color" content="#FF5500">`, "#ff5500"},
+		{"Missing", ``, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article, err := ex.ExtractFromHTML(page(tt.head), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, article.ThemeColor)
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(page(`<meta name="theme-color" content="#FF5500">`), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, article.ThemeColor)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		article, err := ex.ExtractFromHTML(page(`<meta name="theme-color" content="#FF5500">`), nil)
+		assert.NoError(t, err)
+		data, err := json.Marshal(article)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"theme_color":"#ff5500"`)
+	})
+}
+
+// TestCollapseConsecutiveBreaks tests that only long runs of <br> become
+// paragraph breaks when consecutive breaks are collapsed
+func TestCollapseConsecutiveBreaks(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Notes From the Team Offsite</title></head>
+<body>
+	<article>
+		<span>Thanks to everyone who joined the offsite last week, it was great to see so many of you in person, and the discussions were even better than we had hoped.</span><br><br>
+		<span>Slides from every session are in the shared drive, and the recordings will follow once they have been edited, probably by the end of next week.</span><br><br><br><br><br>
+		<span>Next year we plan to run the offsite in spring instead of autumn, so please keep an eye out for the survey about dates, locations, and topics you would like to cover.</span>
+	</article>
+</body>
+</html>`
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		// Every run of two or more breaks is treated as a paragraph break
+		assert.Equal(t, 0, strings.Count(article.Content, "<br"))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithCollapseConsecutiveBreaks(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		// The pair of breaks is kept as a single line break, the run of five is
+		// the only paragraph break
+		assert.Equal(t, 1, strings.Count(article.Content, "<br"))
+		assert.Contains(t, article.Content, "hoped.</span><br/>")
+		assert.Contains(t, article.Content, "Next year we plan")
+	})
+}
+
+// TestTablesVerbatim tests that data tables are copied into PlainContent
+// without their cell structure being normalized
+func TestTablesVerbatim(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Quarterly Sales by Region</title></head>
+<body>
+	<article>
+		<h1>Quarterly Sales by Region</h1>
+		<p>Sales grew in every region this quarter, with the northern stores benefiting most from the <em>early start to the holiday season</em> and the new online ordering service.</p>
+		<table class="sales" style="width: 100%">
+			<caption>Sales (thousands)</caption>
+			<tr><th>Region</th><th>Store</th><th>Sales</th></tr>
+			<tr><td rowspan="2" class="region">North</td><td>Harbour  Street</td><td>120</td></tr>
+			<tr><td>Market Square</td><td>95</td></tr>
+		</table>
+		<p>The southern region held steady, and we expect it to pick up once the <em>refurbished flagship store</em> reopens at the beginning of the next quarter.</p>
+	</article>
+</body>
+</html>`
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithTablesVerbatim(true), readabiligo.WithNodeIndexes(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.PlainContent, `<td rowspan="2">North</td>`)
+		assert.Contains(t, article.PlainContent, `<td>Harbour  Street</td>`)
+		assert.Contains(t, article.PlainContent, `<caption>Sales (thousands)</caption>`)
+		assert.NotContains(t, article.PlainContent, `class="sales"`)
+		assert.NotContains(t, article.PlainContent, `style=`)
+		assert.NotContains(t, article.PlainContent, `data-verbatim-table`)
+		// The table keeps its own node index
+		assert.Regexp(t, `<table data-node-index="[0-9.]+">`, article.PlainContent)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, article.PlainContent, `<td>Harbour  Street</td>`)
+	})
+}
+
+// TestExtractVideos tests that allowed video embeds kept in the content are
+// indexed into Article.Videos
+func TestExtractVideos(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>How to Sharpen a Kitchen Knife</title></head>
+<body>
+	<article>
+		<h1>How to Sharpen a Kitchen Knife</h1>
+		<p>A sharp knife is safer than a dull one, because it goes where you point it instead of slipping off the skin of a tomato and into your finger, so it is worth learning to <em>sharpen your own knives</em>.</p>
+		<iframe width="560" height="315" src="//www.youtube.com/embed/abc123" title="Whetstone basics" data-poster="/img/whetstone.jpg"></iframe>
+		<p>Hold the blade at a consistent angle of about fifteen degrees, use light pressure, and count your strokes so that both sides of the edge receive the same amount of work from the stone.</p>
+	</article>
+</body>
+</html>`
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithExtractVideos(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, "<iframe")
+		if assert.Len(t, article.Videos, 1) {
+			video := article.Videos[0]
+			assert.Equal(t, "https://www.youtube.com/embed/abc123", video.URL)
+			assert.Equal(t, "youtube", video.Provider)
+			assert.Equal(t, "Whetstone basics", video.Title)
+			assert.Equal(t, "/img/whetstone.jpg", video.Poster)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, article.Videos)
+	})
+}
+
+// TestExtractCodeBlocks tests that code blocks are indexed with their language
+// and caption, and that the code and its language stay in Content
+func TestExtractCodeBlocks(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Reading files in Go</title></head>
+<body>
+	<article>
+		<h1>Reading files in Go</h1>
+		<p>The os package has a helper that reads a whole file into memory at once, which is the simplest way to load small configuration files, templates and fixtures in <em>your programs</em>.</p>
+		<figure>
+			<figcaption>main.go</figcaption>
+			<pre><code class="language-go">data, err := os.ReadFile("config.json")
+if err != nil {
+	log.Fatal(err)
+}</code></pre>
+		</figure>
+		<p>For large files, open the file and read it in chunks with a scanner instead, so that memory use stays flat no matter how big <em>the input grows</em>.</p>
+		<pre data-lang="Shell">go run main.go</pre>
+	</article>
+</body>
+</html>`
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithExtractCodeBlocks(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, `data-lang="go"`)
+		assert.Contains(t, article.Content, "main.go</figcaption>")
+		if assert.Len(t, article.CodeBlocks, 2) {
+			assert.Equal(t, readabiligo.CodeBlock{
+				Language: "go",
+				Caption:  "main.go",
+				Code:     "data, err := os.ReadFile(\"config.json\")\nif err != nil {\n\tlog.Fatal(err)\n}",
+			}, article.CodeBlocks[0])
+			assert.Equal(t, readabiligo.CodeBlock{Language: "shell", Code: "go run main.go"}, article.CodeBlocks[1])
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, article.CodeBlocks)
+		assert.Contains(t, article.Content, "os.ReadFile")
+	})
+}
+
+// TestDetectPrimaryDocument tests that a landing page whose main content is a
+// PDF download link is reported as such, while a full article is not
+func TestDetectPrimaryDocument(t *testing.T) {
+	landing := `<!DOCTYPE html>
+<html>
+<head>
+	<title>Annual Water Quality Report 2024</title>
+	<base href="https://water.example.org/reports/">
+</head>
+<body>
+	<article>
+		<h1>Annual Water Quality Report 2024</h1>
+		<p>Our yearly report on the quality of the drinking water we supply.</p>
+		<p><a href="files/water-quality-2024.pdf?download=1">Download the report (PDF)</a></p>
+	</article>
+</body>
+</html>`
+
+	ex := readabiligo.New(readabiligo.WithDetectPrimaryDocument(true))
+
+	t.Run("LandingPage", func(t *testing.T) {
+		article, err := ex.ExtractFromHTML(landing, nil)
+		assert.NoError(t, err)
+		assert.True(t, article.IsDocumentLanding)
+		assert.Equal(t, "https://water.example.org/reports/files/water-quality-2024.pdf?download=1", article.PrimaryDocumentURL)
+	})
+
+	t.Run("Article", func(t *testing.T) {
+		article, err := ex.ExtractFromHTML(`<!DOCTYPE html>
+<html>
+<head><title>What is in your tap water</title></head>
+<body>
+	<article>
+		<h1>What is in your tap water</h1>
+		<p><span>Tap water is tested hundreds of times a year for bacteria, metals and chemicals, and the results are published every spring in a report that few people ever read, even though it answers most questions about taste and safety.</span></p>
+		<p><span>The hardness of the water, which leaves limescale in kettles, comes from calcium and magnesium picked up from rock, and is harmless to drink; softeners remove it but add sodium, which is why many homes keep one unsoftened tap in the kitchen.</span></p>
+		<p><span>Chlorine is added to keep the water safe on its way through the pipes, and its smell fades if a jug is left in the fridge for an hour. The full figures are in <a href="/reports/water-quality-2024.pdf">this year's report</a>.</span></p>
+	</article>
+</body>
+</html>`, nil)
+		assert.NoError(t, err)
+		assert.False(t, article.IsDocumentLanding)
+		assert.Empty(t, article.PrimaryDocumentURL)
+	})
+
+	t.Run("DocumentType", func(t *testing.T) {
+		html := strings.Replace(landing, `<title>`, `<meta property="og:type" content="document"><title>`, 1)
+		html = strings.Replace(html, `<p>Our yearly`, strings.Repeat(`<p><span>Our yearly report covers every supply zone, with the results of each test against the legal limits. </span></p>`, 8)+`<p>Our yearly`, 1)
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.True(t, article.IsDocumentLanding)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(landing, nil)
+		assert.NoError(t, err)
+		assert.False(t, article.IsDocumentLanding)
+		assert.Empty(t, article.PrimaryDocumentURL)
+	})
+}
+
+// TestStripHeaderAnchors tests that permalink anchors are removed from
+// headings by default and kept when stripping is disabled
+func TestStripHeaderAnchors(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Getting Started</title></head>
+<body>
+	<div class="document">
+		<h1>Getting Started</h1>
+		<p>This guide walks through installing the command line tool, configuring your first project, and running the build, so that you have a working setup <em>in about ten minutes</em>.</p>
+		<h2 id="install"><em>Installation</em><a class="headerlink" href="#install" title="Permalink to this heading">¶</a></h2>
+		<p>Download the latest release for your platform, unpack the archive somewhere on your path, and check that everything works by asking the tool to print <em>its version number</em>.</p>
+	</div>
+</body>
+</html>`
+
+	t.Run("Default", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, "<em>Installation</em></h2>")
+		assert.NotContains(t, article.Content, "¶")
+		assert.NotContains(t, article.Content, "headerlink")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithStripHeaderAnchors(false))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, "¶")
+	})
+}
+
+// TestStripEmptyAnchors tests removal of links left without text or media
+func TestStripEmptyAnchors(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Trail Guide</title></head>
+<body>
+	<div class="story">
+		<h1>Trail Guide</h1>
+		<p>The northern loop climbs steadily through pine forest before opening onto a ridge with views of the whole valley, <em>so plan for a long morning</em>.<a href="https://example.com/empty"></a></p>
+		<p><a href="https://example.com/map"><img src="https://example.com/map.png" alt="Trail map"></a></p>
+		<p>Carry plenty of water, since the only spring along the route dries up by midsummer, and start early to avoid <em>the afternoon storms</em>.</p>
+	</div>
+</body>
+</html>`
+
+	t.Run("Default", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, article.Content, "https://example.com/empty")
+		assert.Contains(t, article.Content, `<a href="https://example.com/map"><img`)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithStripEmptyAnchors(false))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, article.Content, `<a href="https://example.com/empty"></a>`)
+	})
+}
+
+// TestPreserveLinks tests that plain text blocks list the links they contain
+func TestPreserveLinks(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Release Notes</title></head>
+<body>
+	<div class="story">
+		<h1>Release Notes</h1>
+		<p>This release improves startup time and fixes several crashes reported by users on older hardware, <em>with more fixes planned for the next update</em>.</p>
+		<p><em>Read the <a href="https://example.com/upgrade">upgrade guide</a> before installing, and see the <a href="https://example.com/changelog" rel="nofollow  Sponsored">full changelog</a> for every change in this release.</em></p>
+	</div>
+</body>
+</html>`
+
+	findBlock := func(blocks []readabiligo.Block, text string) *readabiligo.Block {
+		for i := range blocks {
+			if strings.Contains(blocks[i].Text, text) {
+				return &blocks[i]
+			}
+		}
+		return nil
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		ex := readabiligo.New(readabiligo.WithPreserveLinks(true))
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+
+		block := findBlock(article.PlainText, "upgrade guide")
+		if assert.NotNil(t, block) {
+			assert.Equal(t, []readabiligo.Link{
+				{Href: "https://example.com/upgrade", Text: "upgrade guide"},
+				{Href: "https://example.com/changelog", Text: "full changelog", Rel: "nofollow sponsored"},
+			}, block.Links)
+		}
+
+		block = findBlock(article.PlainText, "more fixes planned")
+		if assert.NotNil(t, block) {
+			assert.Empty(t, block.Links)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+
+		block := findBlock(article.PlainText, "upgrade guide")
+		if assert.NotNil(t, block) {
+			assert.Empty(t, block.Links)
+		}
+	})
+}
+
+// TestMinifyOutput tests that minification removes inter-element whitespace
+// without changing the text
+func TestMinifyOutput(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Build Notes</title></head>
+<body>
+	<div class="story">
+		<h1>Build Notes</h1>
+		<p>The build now caches compiled dependencies between runs, which cuts the time for an incremental build <em>roughly in half</em> on most projects.</p>
+		<pre>
+  make deps
+  make build
+</pre>
+		<p>Clear the cache with the clean target whenever the toolchain is upgraded, since stale objects <em>can fail to link</em>.</p>
+	</div>
+</body>
+</html>`
+
+	regular, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	minified, err := readabiligo.New(readabiligo.WithMinifyOutput(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+
+	assert.Less(t, len(minified.Content), len(regular.Content))
+	assert.NotContains(t, minified.Content, ">\n")
+	assert.Contains(t, minified.Content, "  make deps\n  make build\n</pre>")
+
+	// Only whitespace-only text between elements is removed; all other text
+	// nodes are byte-identical
+	textOf := func(content string) []string {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+		assert.NoError(t, err)
+		var texts []string
+		doc.Find("*").Contents().Each(func(i int, s *goquery.Selection) {
+			if goquery.NodeName(s) == "#text" && strings.TrimSpace(s.Text()) != "" {
+				texts = append(texts, s.Text())
+			}
+		})
+		return texts
+	}
+	assert.Equal(t, textOf(regular.Content), textOf(minified.Content))
+	assert.Equal(t, textOf(regular.PlainContent), textOf(minified.PlainContent))
+}
+
+// TestScoringProfile tests that the wiki profile keeps more of a wiki page's
+// structure than the default settings
+func TestScoringProfile(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Lighthouse</title></head>
+<body>
+	<div class="mw-body">
+		<h1>Lighthouse</h1>
+		<p>A lighthouse is a tower, building, or other type of physical structure designed to emit light from a system of lamps and lenses and to serve as a beacon for navigational aid, for maritime pilots at sea or on inland waterways.</p>
+		<p>Lighthouses mark dangerous coastlines, hazardous shoals, reefs, rocks, and safe entries to harbors; they also assist in aerial navigation. Once widely used, the number of operational lighthouses has declined due to the expense of maintenance and the advent of much cheaper, more sophisticated, and more effective electronic navigational systems.</p>
+		<div class="see-also">
+			<h2><span>See also</span></h2>
+			<ul>
+				<li><a href="/wiki/Lightvessel"><span>Lightvessel</span></a></li>
+				<li><a href="/wiki/Fresnel_lens"><span>Fresnel lens</span></a></li>
+			</ul>
+		</div>
+	</div>
+</body>
+</html>`
+
+	defaultArticle, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	wikiArticle, err := readabiligo.New(readabiligo.WithScoringProfile(readabiligo.ProfileWiki)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+
+	assert.NotContains(t, defaultArticle.Content, "Fresnel lens")
+	assert.Contains(t, wikiArticle.Content, "See also")
+	assert.Contains(t, wikiArticle.Content, "Fresnel lens")
+
+	// The profile only sets the underlying options
+	options := readabiligo.DefaultOptions()
+	readabiligo.WithScoringProfile(readabiligo.ProfileForum)(&options)
+	assert.Equal(t, 140, options.CharThreshold)
+	assert.Equal(t, "Forum", readabiligo.ProfileForum.String())
+
+	// Settings a profile doesn't tune survive it
+	options = readabiligo.DefaultOptions()
+	readabiligo.WithPreserveImportantLinks(true)(&options)
+	options.KeepStructure = true
+	readabiligo.WithScoringProfile(readabiligo.ProfileNews)(&options)
+	assert.True(t, options.PreserveImportantLinks)
+	assert.True(t, options.KeepStructure)
+	assert.Equal(t, -0.05, options.LinkDensityModifier)
+	readabiligo.WithScoringProfile(readabiligo.ProfileBlog)(&options)
+	assert.True(t, options.PreserveImportantLinks)
+	assert.True(t, options.KeepStructure)
+	assert.Equal(t, 300, options.CharThreshold)
+}
+
+// TestCMSContentClasses tests that the CMS content container classes can be
+// replaced or disabled
+func TestCMSContentClasses(t *testing.T) {
+	options := readabiligo.DefaultOptions()
+	assert.Contains(t, options.CMSContentClasses, "entry-content")
+	assert.Contains(t, options.CMSContentClasses, "td-post-content")
+
+	readabiligo.WithCMSContentClasses("story-text")(&options)
+	assert.Equal(t, []string{"story-text"}, options.CMSContentClasses)
+
+	readabiligo.WithCMSContentClasses()(&options)
+	assert.Empty(t, options.CMSContentClasses)
+
+	// The default list is a copy that callers can extend safely
+	classes := readabiligo.DefaultCMSContentClasses()
+	classes[0] = "changed"
+	assert.NotContains(t, readabiligo.DefaultCMSContentClasses(), "changed")
+}
+
+// TestExpandDetails tests that collapsible <details> content is turned into a
+// visible section headed by its summary
+func TestExpandDetails(t *testing.T) {
+	intro := strings.Repeat(`<p><span>Our product helps teams ship faster, with fewer bugs, clearer reviews, and better documentation for everyone involved.</span></p>`, 4)
+	html := `<!DOCTYPE html>
+<html>
+<head><title>FAQ</title></head>
+<body>
+	<article>
+		<h1><span>Frequently asked questions</span></h1>
+		` + intro + `
+		<details>
+			<summary><span>How do I reset my password?</span></summary>
+			<p><span>Open the account settings page, choose Security, and follow the reset link sent to your email address.</span></p>
+		</details>
+	</article>
+</body>
+</html>`
+
+	collapsed, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, collapsed.Content, "<details")
+
+	expanded, err := readabiligo.New(readabiligo.WithExpandDetails(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, expanded.Content, "<details")
+	assert.NotContains(t, expanded.Content, "<summary")
+	assert.Contains(t, expanded.Content, "<h3><span>How do I reset my password?</span></h3>")
+	assert.Contains(t, expanded.PlainContent, "How do I reset my password?")
+	assert.Contains(t, expanded.PlainContent, "follow the reset link sent to your email address")
+
+	// The question precedes its answer
+	question := strings.Index(expanded.Content, "How do I reset my password?")
+	answer := strings.Index(expanded.Content, "follow the reset link")
+	assert.Less(t, question, answer)
+}
+
+// TestMergeListsAcrossParagraphs tests that a list split in two by a removed
+// advertisement is merged back into a single list
+func TestMergeListsAcrossParagraphs(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Sourdough</title></head>
+<body>
+	<article>
+		<h1><span>How to bake sourdough bread</span></h1>
+		<p><span>Baking sourdough takes patience, but the steps are simple once you know them, and the result is far better than anything from a supermarket shelf.</span></p>
+		<p><span>Start the evening before, so the dough has plenty of time to rise, and keep your kitchen reasonably warm while it does.</span></p>
+		<ol>
+			<li><span>Feed the starter and wait until it doubles in size.</span></li>
+			<li><span>Mix flour, water and starter, then rest the dough for an hour.</span></li>
+		</ol>
+		<div class="sponsor-box"><span>Sponsored: Buy our premium flour</span></div>
+		<ol>
+			<li><span>Stretch and fold the dough every half hour for two hours.</span></li>
+			<li><span>Shape the loaf and bake it in a hot Dutch oven.</span></li>
+		</ol>
+		<ol class="tips">
+			<li><span>Use a kitchen scale rather than measuring cups.</span></li>
+		</ol>
+	</article>
+</body>
+</html>`
+
+	merged, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, merged.Content, "Sponsored")
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(merged.Content))
+	assert.NoError(t, err)
+	lists := doc.Find("ol")
+	assert.Equal(t, 2, lists.Length(), "the split list should be merged, the tips list kept apart")
+	assert.Equal(t, 4, lists.First().Find("li").Length())
+	assert.Contains(t, lists.First().Find("li").Last().Text(), "Shape the loaf")
+
+	split, err := readabiligo.New(readabiligo.WithMergeListsAcrossParagraphs(false)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	doc, err = goquery.NewDocumentFromReader(strings.NewReader(split.Content))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, doc.Find("ol").Length())
+}
+
+// TestAssumeTimezone tests that publication dates without timezone information
+// are interpreted in the configured location
+func TestAssumeTimezone(t *testing.T) {
+	page := func(date string) string {
+		return `<!DOCTYPE html>
+<html>
+<head>
+	<title>Council approves new library</title>
+	<script type="application/ld+json">{"@context": "https://schema.org", "@type": "NewsArticle", "headline": "Council approves new library", "datePublished": "` + date + `"}</script>
+</head>
+<body>
+	<article>
+		<p>The city council voted on Tuesday to approve funding for a new public library in the town centre, ending years of debate about the site.</p>
+		<p>Construction is expected to begin next spring, and the library should open its doors to readers within two years of the first spade in the ground.</p>
+	</article>
+</body>
+</html>`
+	}
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	// Without a location, only RFC 3339 dates are read
+	article, err := readabiligo.New().ExtractFromHTML(page("January 2, 2006"), nil)
+	assert.NoError(t, err)
+	assert.True(t, article.Date.IsZero(), "got %v", article.Date)
+	article, err = readabiligo.New().ExtractFromHTML(page("2006-01-02T10:00:00+02:00"), nil)
+	assert.NoError(t, err)
+	assert.True(t, article.Date.Equal(time.Date(2006, 1, 2, 8, 0, 0, 0, time.UTC)), "got %v", article.Date)
+
+	// With a location, naive dates are interpreted in it
+	ext := readabiligo.New(readabiligo.WithAssumeTimezone(tokyo))
+	article, err = ext.ExtractFromHTML(page("January 2, 2006"), nil)
+	assert.NoError(t, err)
+	assert.True(t, article.Date.Equal(time.Date(2006, 1, 2, 0, 0, 0, 0, tokyo)), "got %v", article.Date)
+
+	// An explicit offset wins
+	article, err = ext.ExtractFromHTML(page("2006-01-02T10:00:00+02:00"), nil)
+	assert.NoError(t, err)
+	assert.True(t, article.Date.Equal(time.Date(2006, 1, 2, 8, 0, 0, 0, time.UTC)), "got %v", article.Date)
+
+	// Without a date in the metadata, the one next to the byline is taken
+	// rather than the footer date
+	noMetadata := `<!DOCTYPE html>
+<html>
+<head><title>Council approves new library</title></head>
+<body>
+	<article>
+		<div class="byline">By <a rel="author" href="/authors/ann">Ann Lee</a> <time>March 27, 2023</time></div>
+		<p>The city council voted on Tuesday to approve funding for a new public library in the town centre, ending years of debate about the site.</p>
+		<p>Construction is expected to begin next spring, and the library should open its doors to readers within two years of the first spade in the ground.</p>
+	</article>
+	<footer><span class="date">January 5, 2020</span></footer>
+</body>
+</html>`
+	article, err = readabiligo.New().ExtractFromHTML(noMetadata, nil)
+	assert.NoError(t, err)
+	assert.True(t, article.Date.IsZero(), "got %v", article.Date)
+	article, err = ext.ExtractFromHTML(noMetadata, nil)
+	assert.NoError(t, err)
+	assert.True(t, article.Date.Equal(time.Date(2023, 3, 27, 0, 0, 0, 0, tokyo)), "got %v", article.Date)
+}
+
+// TestStripHiddenText tests that text hidden from readers is left out of the
+// output unless hidden text stripping is disabled
+func TestStripHiddenText(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Packing light for a week away</title></head>
+<body>
+	<article>
+		<p>Packing for a week with only a carry-on bag is easier than it sounds, as long as you choose clothes that mix and match and leave the just-in-case items at home.</p>
+		<p style="display: none">cheap flights cheap hotels best travel deals cheap flights</p>
+		<p>Roll your clothes instead of folding them, wear your bulkiest shoes on the plane, and buy toiletries at your destination instead of carrying them.<span style="visibility:hidden"> discount luggage coupon</span></p>
+		<div hidden><p>Copyright notice inserted when this text is copied to the clipboard.</p></div>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.PlainContent, "Roll your clothes")
+	for _, hidden := range []string{"cheap flights", "discount luggage", "Copyright notice"} {
+		assert.NotContains(t, article.Content, hidden)
+		assert.NotContains(t, article.PlainContent, hidden)
+	}
+
+	kept, err := readabiligo.New(readabiligo.WithStripHiddenText(false)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, kept.PlainContent, "cheap flights")
+	assert.Contains(t, kept.PlainContent, "discount luggage")
+
+	// Paywall content is not stripped, since its hidden text is the article
+	paywall, err := readabiligo.New(readabiligo.WithContentType(readabiligo.ContentTypePaywall)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, paywall.PlainContent, "discount luggage")
+}
+
+// TestContentTypeRule tests that a matching selector rule overrides the content
+// type, and that rules are evaluated in order
+func TestContentTypeRule(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Why rents keep rising</title></head>
+<body>
+	<article class="paywall-v2">
+		<p><span>Rents in most large cities rose faster than wages again this year, and economists say the shortage of new housing is the main reason behind it.</span></p>
+		<div style="display: none"><p><span>Subscribers can read how zoning rules limit the number of homes that can be built near city centers.</span></p></div>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, readabiligo.ContentTypeArticle, article.ContentType)
+	assert.NotContains(t, article.PlainContent, "zoning rules")
+
+	paywall, err := readabiligo.New(
+		readabiligo.WithContentTypeRule(".paywall-v2", readabiligo.ContentTypePaywall),
+	).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, readabiligo.ContentTypePaywall, paywall.ContentType)
+	assert.Contains(t, paywall.PlainContent, "zoning rules")
+
+	// The first matching rule wins; rules that do not match are skipped
+	ordered, err := readabiligo.New(
+		readabiligo.WithContentTypeRule(".no-such-class", readabiligo.ContentTypeError),
+		readabiligo.WithContentTypeRule("article", readabiligo.ContentTypeTechnical),
+		readabiligo.WithContentTypeRule(".paywall-v2", readabiligo.ContentTypePaywall),
+	).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, readabiligo.ContentTypeTechnical, ordered.ContentType)
+}
+
+// TestStripConsentBanners tests that cookie consent banners are removed while
+// the article, including its own mentions of cookies, is kept
+func TestStripConsentBanners(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Baking soft oatmeal cookies</title></head>
+<body>
+	<div id="onetrust-consent-sdk">
+		<div id="onetrust-banner-sdk" role="dialog">
+			<p>We use cookies to improve your experience and to show you personalised ads.</p>
+			<button id="onetrust-accept-btn-handler">Accept All Cookies</button>
+		</div>
+	</div>
+	<div class="privacy-overlay" style="position: fixed; bottom: 0">
+		<p>This website uses cookies. <a href="/privacy">Manage cookies</a></p>
+	</div>
+	<article>
+		<p>Soft oatmeal cookies rely on brown sugar and a short baking time: take them out of the oven while the centres still look slightly underdone.</p>
+		<p>Let the cookies rest on the tray for five minutes so they set, then move them to a rack and store them in a tin with a slice of bread to keep them soft.</p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.PlainContent, "Soft oatmeal cookies")
+	assert.Contains(t, article.PlainContent, "Let the cookies rest")
+	for _, banner := range []string{"We use cookies", "Accept All", "This website uses cookies"} {
+		assert.NotContains(t, article.Content, banner)
+		assert.NotContains(t, article.PlainContent, banner)
+	}
+
+	kept, err := readabiligo.New(readabiligo.WithStripConsentBanners(false)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, kept.Content, "This website uses cookies")
+}
+
+// TestStripBylineFromContent tests that the in-content byline is removed once
+// it has been captured into Byline
+func TestStripBylineFromContent(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+	<title>Why sourdough needs time</title>
+	<meta name="author" content="Maria Lopez">
+</head>
+<body>
+	<article>
+		<p><span>By <a rel="author" href="/authors/maria-lopez">Maria Lopez</a></span></p>
+		<p><span>A sourdough starter is a living culture of wild yeast and bacteria, and it needs hours rather than minutes to raise a loaf, which is exactly where its flavour comes from.</span></p>
+		<p><span>Give the dough a long, cool rise overnight in the fridge, and bake it straight from cold the next morning in a very hot oven with plenty of steam.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Maria Lopez", article.Byline)
+	assert.Contains(t, article.Content, ">Maria Lopez</a>")
+
+	ext := readabiligo.New(readabiligo.WithStripBylineFromContent(true))
+	article, err = ext.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Maria Lopez", article.Byline)
+	assert.NotContains(t, article.Content, "Maria Lopez")
+	assert.NotContains(t, article.PlainContent, "By ")
+	assert.Contains(t, article.Content, "A sourdough starter")
+}
+
+// TestPostExtractHook tests that the post-extraction hook can transform the
+// article and that its changes are seen by the processing that follows
+func TestPostExtractHook(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Restoring an old bicycle</title></head>
+<body>
+	<article>
+		<p><span>Start by stripping the bicycle down to the frame, keeping the small parts in labelled bags so that putting it back together is a matter of following the labels.</span></p>
+		<p><span>Rust on chrome comes off with aluminium foil dipped in water, while painted tubes are best sanded back to bare metal before priming and spraying.</span></p>
+		<p><span>Prices quoted in this article were correct at the time of writing, but may have changed since then.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "Prices quoted")
+
+	called := 0
+	ext := readabiligo.New(readabiligo.WithPostExtractHook(func(s *goquery.Selection) {
+		called++
+		// Classes added by the hook are seen by the footer cleanup that follows
+		s.Find("p:contains('Prices quoted')").AddClass("footer")
+	}))
+	article, err = ext.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, called)
+	assert.Contains(t, article.Content, "Rust on chrome")
+	assert.NotContains(t, article.Content, "Prices quoted")
+	assert.NotContains(t, article.PlainContent, "Prices quoted")
+}
+
+// metadataFunc adapts a function to the MetadataExtractor interface
+type metadataFunc func(doc *goquery.Document) map[string]string
+
+func (f metadataFunc) Extract(doc *goquery.Document) map[string]string {
+	return f(doc)
+}
+
+// TestMetadataExtractor tests that the built-in metadata extractors fill
+// Article.Metadata and that custom extractors add and override fields
+func TestMetadataExtractor(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+	<title>Growing tomatoes on a balcony</title>
+	<meta property="og:title" content="Balcony tomatoes">
+	<meta property="og:site_name" content="Garden Weekly">
+	<meta property="og:image" content="https://example.com/tomatoes.jpg">
+	<meta name="paywall" content="metered">
+	<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Article", "headline": "Growing tomatoes on a balcony", "author": {"name": "Sam Reed"}}</script>
+</head>
+<body>
+	<article>
+		<p>Tomatoes grow well in containers as long as they get six hours of sun a day, so a south-facing balcony is all you need for a summer harvest.</p>
+		<p>Pick a compact variety, use a pot of at least twenty litres, and water deeply every morning once the fruit starts to set.</p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Garden Weekly", article.Metadata["siteName"])
+	assert.Equal(t, "https://example.com/tomatoes.jpg", article.Metadata["image"])
+	assert.Equal(t, "Sam Reed", article.Metadata["byline"])
+	// JSON-LD runs after OpenGraph, so its headline wins
+	assert.Equal(t, "Growing tomatoes on a balcony", article.Metadata["title"])
+
+	paywall := metadataFunc(func(doc *goquery.Document) map[string]string {
+		return map[string]string{"paywall": doc.Find(`meta[name="paywall"]`).AttrOr("content", "")}
+	})
+	override := metadataFunc(func(doc *goquery.Document) map[string]string {
+		return map[string]string{"siteName": "GW"}
+	})
+	ext := readabiligo.New(readabiligo.WithMetadataExtractor(paywall, override))
+	article, err = ext.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "metered", article.Metadata["paywall"])
+	assert.Equal(t, "GW", article.Metadata["siteName"])
+
+	data, err := json.Marshal(article)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"paywall":"metered"`)
+}
+
+// TestCollapseWhitespaceInAttributes tests that whitespace from the source
+// markup is collapsed in alt and title attributes
+func TestCollapseWhitespaceInAttributes(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Herons of the estuary</title></head>
+<body>
+	<article>
+		<p><span>Grey herons stand motionless in the shallows of the estuary for minutes at a time, waiting for a fish or a frog to come within reach of their long, dagger-like bills.</span></p>
+		<p><img src="https://example.com/heron.jpg" alt="A grey heron
+			standing in   the reeds" title="  Photo:
+			the estuary  "></p>
+		<p><span>They nest in colonies at the tops of tall trees, and the same heronries are used by generation after generation, some of them for more than a hundred years.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "standing in   the reeds")
+
+	ext := readabiligo.New(readabiligo.WithCollapseWhitespaceInAttributes(true))
+	article, err = ext.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, `alt="A grey heron standing in the reeds"`)
+	assert.Contains(t, article.Content, `title="Photo: the estuary"`)
+}
+
+// TestContentMaxLength tests that content is cut at a paragraph boundary when
+// it is longer than the configured maximum, and that the HTML stays well-formed
+func TestContentMaxLength(t *testing.T) {
+	paragraphs := []string{
+		"The first rule of bread baking is patience: a slow rise in a cool kitchen develops far more flavour than a quick one in a warm oven.",
+		"Weigh your flour instead of measuring it by the cup, because a cup of flour can vary by twenty percent depending on how it was scooped.",
+		"Finally, let the loaf cool completely on a rack before slicing it, or the crumb will be gummy and the steam will escape too soon.",
+	}
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Better bread at home</title></head>
+<body>
+	<article>
+		<p>` + strings.Join(paragraphs, "</p>\n\t\t<p>") + `</p>
+	</article>
+</body>
+</html>`
+
+	full, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.False(t, full.IsTruncated)
+	assert.Contains(t, full.PlainContent, "Finally, let the loaf cool")
+
+	ext := readabiligo.New(readabiligo.WithContentMaxLength(len(paragraphs[0]) + len(paragraphs[1]) + 10))
+	article, err := ext.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.True(t, article.IsTruncated)
+	assert.Contains(t, article.Content, paragraphs[1]+"</p>")
+	assert.NotContains(t, article.Content, "Finally")
+	assert.NotContains(t, article.PlainContent, "Finally")
+	for _, block := range article.PlainText {
+		assert.NotContains(t, block.Text, "Finally")
+	}
+
+	// Every element opened in the truncated content is closed again
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(article.Content))
+	assert.NoError(t, err)
+	rendered, err := doc.Find("body").Html()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(article.Content), rendered)
+}
+
+// TestSentenceSegmentation tests that PlainText blocks are split into sentences
+func TestSentenceSegmentation(t *testing.T) {
+	// The paragraph text is wrapped in spans because PlainText currently
+	// only picks up text inside child elements
+	html := `<!DOCTYPE html>
+<html>
+<head><title>An evening at the clinic</title></head>
+<body>
+	<article>
+		<p><span>Dr. Smith went home. He slept.</span></p>
+		<p><span>The clinic stayed open until 9.30 in the evening, e.g. for emergencies. Nobody came in.</span></p>
+		<p><span>The night shift at the clinic is usually quiet, with a nurse, a receptionist and one doctor on call for the whole of the valley, and most evenings pass without a single patient walking through the door.</span></p>
+		<p><span>When somebody does come in, it is rarely serious: a sprained ankle from the football pitch, a child with a fever that will not come down, or a farmer who cut his hand on a fence and waited too long to have it seen to.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Len(t, article.PlainText, 4)
+	assert.Nil(t, article.PlainText[0].ParentIndex)
+
+	ext := readabiligo.New(readabiligo.WithSentenceSegmentation(true))
+	article, err = ext.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, article.PlainText, 6) {
+		assert.Equal(t, "Dr. Smith went home.", article.PlainText[0].Text)
+		assert.Equal(t, "He slept.", article.PlainText[1].Text)
+		assert.Equal(t, "The clinic stayed open until 9.30 in the evening, e.g. for emergencies.", article.PlainText[2].Text)
+		assert.Equal(t, "Nobody came in.", article.PlainText[3].Text)
+		for i, parent := range []int{0, 0, 1, 1} {
+			if assert.NotNil(t, article.PlainText[i].ParentIndex) {
+				assert.Equal(t, parent, *article.PlainText[i].ParentIndex)
+			}
+			assert.Equal(t, readabiligo.BlockParagraph, article.PlainText[i].Type)
+		}
+		encoded, err := json.Marshal(article.PlainText[0])
+		assert.NoError(t, err)
+		assert.Contains(t, string(encoded), `"parent_index":0`)
+	}
+}
+
+// TestTextOnly tests that text-only extraction produces typed PlainText blocks
+// and a plain text PlainContent, and leaves Content empty
+func TestTextOnly(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Planting garlic in autumn</title></head>
+<body>
+	<article>
+		<h2><span>When to plant</span></h2>
+		<p><span>Plant garlic a few weeks before the ground freezes, so that the cloves can grow roots but not leaves before winter arrives in the garden.</span></p>
+		<ul>
+			<li><span>Choose large cloves</span>
+				<ul><li><span>Keep the papery skin on</span></li></ul>
+			</li>
+		</ul>
+		<blockquote><p><span>Garlic planted in spring rarely forms large bulbs.</span></p></blockquote>
+	</article>
+</body>
+</html>`
+
+	full, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+
+	article, err := readabiligo.New(readabiligo.WithTextOnly(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.Content)
+	assert.Equal(t, full.Title, article.Title)
+
+	expected := []readabiligo.Block{
+		{Text: "When to plant", Type: readabiligo.BlockHeading, Level: 2},
+		{Text: "Plant garlic a few weeks before the ground freezes, so that the cloves can grow roots but not leaves before winter arrives in the garden.", Type: readabiligo.BlockParagraph},
+		{Text: "Choose large cloves", Type: readabiligo.BlockListItem, Level: 1},
+		{Text: "Keep the papery skin on", Type: readabiligo.BlockListItem, Level: 2},
+		{Text: "Garlic planted in spring rarely forms large bulbs.", Type: readabiligo.BlockQuote, Level: 1},
+	}
+	assert.Equal(t, expected, article.PlainText)
+	assert.Equal(t, strings.Join([]string{
+		expected[0].Text, expected[1].Text, expected[2].Text, expected[3].Text, expected[4].Text,
+	}, "\n\n"), article.PlainContent)
+
+	// Options that format Content have nothing to act on
+	formatted, err := readabiligo.New(
+		readabiligo.WithTextOnly(true),
+		readabiligo.WithMinifyOutput(true),
+		readabiligo.WithContentBoundaryMarkers(true),
+		readabiligo.WithCollapseWhitespaceInAttributes(true),
+	).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, formatted.Content)
+	assert.Equal(t, article.PlainText, formatted.PlainText)
+	assert.Equal(t, article.PlainContent, formatted.PlainContent)
+}
+
+// TestPreserveSemanticStyles tests that meaningful inline styles become
+// elements only when semantic styles are preserved
+func TestPreserveSemanticStyles(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Library to close one day a week</title></head>
+<body>
+	<article>
+		<p><span>The council voted to close the library on <span style="text-decoration: line-through">Mondays</span> <span style="font-weight: 700">Tuesdays</span> from next month, according to minutes published on Friday.</span></p>
+		<p style="font-style: italic"><span>This article was corrected to fix the day of the week the library will close.</span></p>
+		<p><span>Nobody from the library was available to comment on the <b style="font-weight: bold">decision</b>, which the council said was needed to balance its budget.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New(readabiligo.WithPreserveSemanticStyles(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Regexp(t, `<del[^>]*>Mondays</del>`, article.Content)
+	assert.Regexp(t, `<strong[^>]*>Tuesdays</strong>`, article.Content)
+	assert.Regexp(t, `<p[^>]*><em><span>This article was corrected`, article.Content)
+	assert.Contains(t, article.Content, "decision</b>")
+	assert.NotContains(t, article.Content, "<strong>decision")
+	assert.Contains(t, article.PlainContent, "Mondays</del>")
+
+	article, err = readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "<del")
+	assert.NotContains(t, article.Content, "<strong")
+	assert.NotContains(t, article.Content, "<em>")
+}
+
+// TestPreserveMath tests that MathML equations and MathJax LaTeX source
+// survive extraction when math is preserved
+func TestPreserveMath(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Solving quadratic equations</title></head>
+<body>
+	<article>
+		<p>The roots of a quadratic equation are given by a formula that every student learns at school, and it can be derived by completing the square.</p>
+		<p><math display="block" xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow><mi>x</mi><mo>=</mo><mfrac><mrow><mo>-</mo><mi>b</mi><mo>±</mo><msqrt><mrow><msup><mi>b</mi><mn>2</mn></msup><mo>-</mo><mn>4</mn><mi>a</mi><mi>c</mi></mrow></msqrt></mrow><mrow><mn>2</mn><mi>a</mi></mrow></mfrac></mrow><annotation encoding="application/x-tex">x = \frac{-b \pm \sqrt{b^2-4ac}}{2a}</annotation></semantics></math></p>
+		<p>The expression under the root, <span class="MathJax_Preview">b2-4ac</span><script type="math/tex">b^2-4ac</script>, is called the discriminant, and its sign tells you how many real roots there are.</p>
+	</article>
+</body>
+</html>`
+
+	ext := readabiligo.New(readabiligo.WithPreserveMath(true))
+	article, err := ext.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, `<math display="block"`)
+	assert.Contains(t, article.Content, "<mfrac>")
+	assert.Contains(t, article.Content, `\(b^2-4ac\)`)
+	assert.NotContains(t, article.Content, "b2-4ac")
+
+	var plainText []string
+	for _, block := range article.PlainText {
+		plainText = append(plainText, block.Text)
+	}
+	assert.Contains(t, strings.Join(plainText, "\n"), `\[x = \frac{-b \pm \sqrt{b^2-4ac}}{2a}\]`)
+
+	// Without the option the MathJax source is removed with the other scripts
+	article, err = readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, `b^2-4ac\)`)
+}
+
+// TestRenderMathAsText tests that MathML equations are rendered as plain text
+// instead of disappearing, falling back to their alttext
+func TestRenderMathAsText(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Dividing fractions</title></head>
+<body>
+	<article>
+		<p><span>To divide one quantity by another, write the first over the second: </span><math><mfrac><mi>a</mi><mi>b</mi></mfrac></math><span>, which is only defined when the second is not zero.</span></p>
+		<p><span>The mean of two numbers is </span><math><mfrac><mrow><mi>a</mi><mo>+</mo><mi>b</mi></mrow><mn>2</mn></mfrac></math><span>, and the area of a square of side x is </span><math><msup><mi>x</mi><mn>2</mn></msup></math><span>, as every student learns.</span></p>
+		<p><span>The golden ratio is </span><math alttext="phi = (1 + sqrt 5)/2"><mglyph src="phi.png"></mglyph></math><span>, which appears in the proportions of many buildings and paintings.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.PlainTextString(), "a/b")
+
+	article, err = readabiligo.New(readabiligo.WithRenderMathAsText(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	text := article.PlainTextString()
+	assert.Contains(t, text, "a/b, which")
+	assert.Contains(t, text, "(a + b)/2")
+	assert.Contains(t, text, "x^2")
+	assert.Contains(t, text, "phi = (1 + sqrt 5)/2")
+	assert.NotContains(t, article.Content, "<math")
+}
+
+// TestReorderColumns tests that a two-column layout split into rows, whose DOM
+// alternates between the columns, is read column by column when enabled
+func TestReorderColumns(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Two views on remote work</title></head>
+<body>
+	<article>
+		<div class="row">
+			<div class="column-left"><p><span>Alpha one: working from home saves the daily commute, which for many people adds up to more than an hour of their day.</span></p></div>
+			<div class="column-right"><p><span>Beta one: offices make it easier to learn from colleagues, since questions can be answered the moment they come up.</span></p></div>
+		</div>
+		<div class="row">
+			<div class="column-left"><p><span>Alpha two: a quiet room at home can be better for focused work than an open plan office full of conversations.</span></p></div>
+			<div class="column-right"><p><span>Beta two: the boundary between work and home blurs when both happen at the same kitchen table every day.</span></p></div>
+		</div>
+		<div class="row">
+			<div class="column-left"><p><span>Alpha three: hiring without regard to location lets a team find people it could never have reached before.</span></p></div>
+			<div class="column-right"><p><span>Beta three: time zones spread across a team make it harder to find an hour when everyone can meet.</span></p></div>
+		</div>
+	</article>
+</body>
+</html>`
+
+	order := func(text string) []int {
+		var positions []int
+		for _, marker := range []string{"Alpha one", "Alpha two", "Alpha three", "Beta one", "Beta two", "Beta three"} {
+			positions = append(positions, strings.Index(text, marker))
+		}
+		return positions
+	}
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	positions := order(article.PlainTextString())
+	assert.Less(t, positions[3], positions[1], "DOM order should be kept by default")
+
+	article, err = readabiligo.New(readabiligo.WithReorderColumns(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	positions = order(article.PlainTextString())
+	for i := range positions {
+		assert.GreaterOrEqual(t, positions[i], 0)
+		if i > 0 {
+			assert.Less(t, positions[i-1], positions[i], "blocks should be read column by column")
+		}
+	}
+
+	// Columns placed with the CSS order property are read in that order
+	html = strings.ReplaceAll(html, `class="column-left"`, `style="order: 2"`)
+	html = strings.ReplaceAll(html, `class="column-right"`, `style="order: 1"`)
+	article, err = readabiligo.New(readabiligo.WithReorderColumns(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	positions = order(article.PlainTextString())
+	assert.Less(t, positions[5], positions[0], "the column placed first should be read first")
+}
+
+// TestStripLeadingSymbols tests that decorative emoji are removed from the start
+// of the title when enabled, while symbols that are part of it are kept
+func TestStripLeadingSymbols(t *testing.T) {
+	page := func(title string) string {
+		return `<html><head><title>` + title + `</title></head><body><article><p><span>Every opinion column needs a strong position, stated early and defended with evidence rather than volume.</span></p></article></body></html>`
+	}
+
+	article, err := readabiligo.New().ExtractFromHTML(page("🔥 Hot Take"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "🔥 Hot Take", article.Title)
+
+	ex := readabiligo.New(readabiligo.WithStripLeadingSymbols(true))
+	for title, want := range map[string]string{
+		"🔥 Hot Take":       "Hot Take",
+		"👍🏽 ➡️ Next steps": "Next steps",
+		"C++ Tips":         "C++ Tips",
+		"#1 Hit Song":      "#1 Hit Song",
+		"$5 Lunch Ideas":   "$5 Lunch Ideas",
+	} {
+		article, err := ex.ExtractFromHTML(page(title), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, want, article.Title, title)
+	}
+}
+
+// TestPullQuoteMode tests that a pull-quote repeating a sentence of the body is
+// kept by default, removed with PullQuoteRemove and listed with PullQuoteExtract
+func TestPullQuoteMode(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>The last lighthouse keeper</title></head>
+<body>
+	<article>
+		<p><span>For thirty years Margaret climbed the hundred and twelve steps of the tower every evening to light the lamp before dusk.</span></p>
+		<p><span>She kept a log of every ship that passed. The sea never sleeps, so neither can the keeper, she wrote on her first night.</span></p>
+		<aside class="pullquote"><p><span>“The sea never sleeps, so neither can the keeper”</span></p></aside>
+		<p><span>When the light was automated in 1998 she stayed on in the cottage, walking the cliff path each morning to check the lens.</span></p>
+		<blockquote><p><span>She kept a log of every ship that passed.</span></p></blockquote>
+		<p><span>Her logbooks, forty volumes in all, are now kept by the county archive and open to anyone who asks to read them.</span></p>
+	</article>
+</body>
+</html>`
+
+	count := func(article *readabiligo.Article, sentence string) int {
+		return strings.Count(article.PlainTextString(), sentence)
+	}
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count(article, "The sea never sleeps, so neither can the keeper"))
+	assert.Empty(t, article.PullQuotes)
+
+	article, err = readabiligo.New(readabiligo.WithPullQuoteMode(readabiligo.PullQuoteRemove)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count(article, "The sea never sleeps, so neither can the keeper"))
+	assert.Equal(t, 1, count(article, "She kept a log of every ship that passed."))
+	assert.Empty(t, article.PullQuotes)
+
+	article, err = readabiligo.New(readabiligo.WithPullQuoteMode(readabiligo.PullQuoteExtract)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count(article, "The sea never sleeps, so neither can the keeper"))
+	assert.Equal(t, []string{
+		"The sea never sleeps, so neither can the keeper",
+		"She kept a log of every ship that passed.",
+	}, article.PullQuotes)
+	assert.Contains(t, article.PlainTextString(), "Her logbooks, forty volumes in all")
+}
+
+// TestPhaseTimeouts tests that a slow extract phase is cut off at its own budget
+// rather than at the overall timeout, and that phases within budget succeed
+func TestPhaseTimeouts(t *testing.T) {
+	html := `<html><head><title>Slow page</title></head><body><article><p><span>This article is extracted by a hook that takes far longer than the budget of its phase allows.</span></p></article></body></html>`
+	// The hook blocks until the test is done, far beyond the phase budget
+	release := make(chan struct{})
+	defer close(release)
+	slowHook := readabiligo.WithPostExtractHook(func(s *goquery.Selection) {
+		<-release
+	})
+
+	ex := readabiligo.New(
+		slowHook,
+		readabiligo.WithTimeout(10*time.Second),
+		readabiligo.WithPhaseTimeouts(map[readabiligo.Phase]time.Duration{readabiligo.PhaseExtract: 50 * time.Millisecond}),
+	)
+	start := time.Now()
+	_, err := ex.ExtractFromHTML(html, nil)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, errors.Is(err, readabiligo.ErrTimeout))
+	var phaseErr *readabiligo.PhaseTimeoutError
+	if assert.True(t, errors.As(err, &phaseErr)) {
+		assert.Equal(t, readabiligo.PhaseExtract, phaseErr.Phase)
+		assert.Equal(t, "extract", phaseErr.Phase.String())
+	}
+
+	// Phases that finish within their budgets are unaffected
+	ex = readabiligo.New(readabiligo.WithPhaseTimeouts(map[readabiligo.Phase]time.Duration{
+		readabiligo.PhaseParse: time.Second,
+		readabiligo.PhaseText:  time.Second,
+	}))
+	article, err := ex.ExtractFromHTML(html, nil)
+	if assert.NoError(t, err) {
+		assert.Contains(t, article.PlainTextString(), "far longer than the budget")
+	}
+}
+
+// TestExtractKeywords tests that the most frequent words of a topical article
+// are listed as keywords, without stopwords, and only when enabled
+func TestExtractKeywords(t *testing.T) {
+	html := `<html lang="en"><head><title>Keeping bees in the city</title></head><body><article>
+		<p><span>Urban beekeeping has grown quickly. A single hive on a rooftop can hold fifty thousand bees, and the honey they make tastes of the flowers in the parks and gardens around it.</span></p>
+		<p><span>Before buying a hive, check the rules of your city. Some cities ask beekeepers to register each hive, and neighbours are more relaxed about bees when the hive sits high above the street.</span></p>
+		<p><span>Bees need water as well as flowers. A shallow dish with pebbles near the hive keeps them from visiting swimming pools, and a beekeeper who plants flowers helps the honey harvest too.</span></p>
+		<p><span>Harvest honey in late summer, leaving enough in the hive for the bees to survive the winter.</span></p>
+	</article></body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.Keywords)
+
+	article, err = readabiligo.New(readabiligo.WithExtractKeywords(4)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hive", "bees", "honey", "flowers"}, article.Keywords)
+	for _, stopword := range []string{"the", "and", "when", "their", "some"} {
+		assert.NotContains(t, article.Keywords, stopword)
+	}
+}
+
+// TestPreserveKbdAndSamp tests that keyboard input and sample output elements
+// are kept as bare tags when enabled, and left as they are by default
+func TestPreserveKbdAndSamp(t *testing.T) {
+	html := `<html><head><title>Stopping the development server</title></head><body>
+		<article>
+			<p><span>To stop the running server, press <kbd id="ctrl">Ctrl</kbd>+<kbd>C</kbd> in the terminal and wait for <samp id="status">Server stopped</samp> to appear before closing the window.</span></p>
+		</article>
+	</body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.PlainContent, `<kbd id="ctrl">Ctrl</kbd>`)
+	assert.Contains(t, article.PlainContent, `<samp id="status">Server stopped</samp>`)
+
+	article, err = readabiligo.New(readabiligo.WithPreserveKbdAndSamp(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.PlainContent, `<kbd>Ctrl</kbd>+<kbd>C</kbd>`)
+	assert.Contains(t, article.PlainContent, `<samp>Server stopped</samp>`)
+}
+
+// TestRetryStrategy tests that extraction relaxes the configured heuristics in
+// order when the article is too short, and the default ones when none are set
+func TestRetryStrategy(t *testing.T) {
+	html := `<html><head><title>Short note</title></head><body>
+		<article><p><span>The bakery is closed on Monday for a family event and will open again on Tuesday morning.</span></p></article>
+	</body></html>`
+
+	// retries returns the heuristics relaxed while extracting the page with the
+	// given logger, in order
+	retries := func(extract func(logger *slog.Logger) error) []string {
+		var logs bytes.Buffer
+		assert.NoError(t, extract(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+		var flags []string
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var event map[string]any
+			if err := json.Unmarshal([]byte(line), &event); err != nil {
+				t.Fatalf("Failed to decode log event %q: %v", line, err)
+			}
+			if event["action"] == "retry with relaxed heuristic" {
+				flags = append(flags, event["flag"].(string))
+			}
+		}
+		return flags
+	}
+	withOptions := func(opts ...readabiligo.Option) func(logger *slog.Logger) error {
+		return func(logger *slog.Logger) error {
+			_, err := readabiligo.New(append(opts, readabiligo.WithLogger(logger))...).ExtractFromHTML(html, nil)
+			return err
+		}
+	}
+
+	assert.Equal(t, []string{"StripUnlikelys", "WeightClasses", "CleanConditionally"}, retries(withOptions()))
+	assert.Equal(t, []string{"CleanConditionally"}, retries(withOptions(readabiligo.WithRetryStrategy(readabiligo.RetryCleanConditionally))))
+	assert.Equal(t, []string{"WeightClasses", "StripUnlikelys"}, retries(withOptions(readabiligo.WithRetryStrategy(readabiligo.RetryWeightClasses, readabiligo.RetryStripUnlikelys))))
+	assert.Empty(t, retries(withOptions(readabiligo.WithRetryStrategy())))
+
+	// Options built as a literal, without a strategy, keep the default retries
+	literal := retries(func(logger *slog.Logger) error {
+		_, err := readabiligo.New().ExtractFromHTML(html, &readabiligo.ExtractionOptions{Timeout: time.Second, Logger: logger})
+		return err
+	})
+	assert.Equal(t, []string{"StripUnlikelys", "WeightClasses", "CleanConditionally"}, literal)
+}
+
+// TestExtractBatch tests that a batch is extracted concurrently with every
+// result returned in input order, and that a failing input does not stop the
+// rest of the batch
+func TestExtractBatch(t *testing.T) {
+	var inputs []readabiligo.BatchInput
+	for i := 0; i < 10; i++ {
+		html := fmt.Sprintf(`<html><head><title>Post %d</title></head><body><article><p><span>Post number %d explains how to keep a sourdough starter alive, feeding it equal weights of flour and water every day.</span></p></article></body></html>`, i, i)
+		inputs = append(inputs, readabiligo.BatchInput{
+			Name: fmt.Sprintf("post-%d.html", i),
+			Open: func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(html)), nil },
+		})
+	}
+	inputs = append(inputs, readabiligo.BatchInput{
+		Name: "missing.html",
+		Open: func() (io.ReadCloser, error) { return nil, errors.New("file not found") },
+	})
+
+	var completed []string
+	ex := readabiligo.New(
+		readabiligo.WithBatchJobs(4),
+		readabiligo.WithOutputCallback(func(result readabiligo.BatchResult) {
+			completed = append(completed, result.Name)
+		}),
+	).(readabiligo.ExtendedExtractor)
+	results := ex.ExtractBatch(inputs, nil)
+	assert.Len(t, results, len(inputs))
+	assert.Len(t, completed, len(inputs))
+
+	for i, result := range results[:10] {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, fmt.Sprintf("post-%d.html", i), result.Name)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, fmt.Sprintf("Post %d", i), result.Article.Title)
+		}
+	}
+	assert.Error(t, results[10].Err)
+	assert.Nil(t, results[10].Article)
+}
+
+// TestTrackRemovedLinks tests that links removed with the footer are listed in
+// RemovedLinks only when tracking is enabled
+func TestTrackRemovedLinks(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Feeding a sourdough starter</title></head>
+<body>
+	<article>
+		<h1>Feeding a sourdough starter</h1>
+		<p><span>A sourdough starter is a mix of flour and water in which wild yeast and bacteria live, and it takes about a week of daily feeding before it can raise a loaf. See the <a href="/guides/flour">flour guide</a> for advice on choosing flour.</span></p>
+		<p><span>Discard half of the starter every day and feed it with equal weights of flour and water, keeping it somewhere warm so that it doubles within a few hours.</span></p>
+		<footer>
+			<a href="/about">About us</a>
+			<a href="/privacy">Privacy policy</a>
+		</footer>
+	</article>
+</body>
+</html>`
+
+	ex := readabiligo.New(readabiligo.WithTrackRemovedLinks(true))
+	article, err := ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "Privacy policy")
+	assert.Contains(t, article.RemovedLinks, readabiligo.RemovedLink{Href: "/about", Text: "About us", Reason: "footer"})
+	assert.Contains(t, article.RemovedLinks, readabiligo.RemovedLink{Href: "/privacy", Text: "Privacy policy", Reason: "footer"})
+	for _, link := range article.RemovedLinks {
+		assert.NotEqual(t, "/guides/flour", link.Href, "Kept link listed as removed")
+	}
+
+	ex = readabiligo.New()
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.RemovedLinks)
+}
+
+// TestNormalizeHeadingWhitespace tests that a multi-line heading becomes a
+// single-line title and heading block unless normalization is disabled
+func TestNormalizeHeadingWhitespace(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Home</title></head>
+<body>
+	<article>
+		<h1>Feeding a sourdough
+			starter<br>A beginner's   guide</h1>
+		<h2><span>Daily<br/>feeding</span></h2>
+		<p><span>A sourdough starter is a mix of flour and water in which wild yeast and bacteria live, and it takes about a week of daily feeding before it can raise a loaf.</span></p>
+	</article>
+</body>
+</html>`
+
+	ex := readabiligo.New(readabiligo.WithHeadingBlocks(true))
+	article, err := ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Feeding a sourdough starter A beginner's guide", article.Title)
+	if assert.NotEmpty(t, article.PlainText) {
+		assert.Equal(t, readabiligo.BlockHeading, article.PlainText[0].Type)
+		assert.Equal(t, "Daily feeding", article.PlainText[0].Text)
+	}
+
+	ex = readabiligo.New(readabiligo.WithNormalizeHeadingWhitespace(false))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Title, "starterA beginner's")
+}
+
+// TestDiscussionMode tests that nested comments are walked into thread posts
+// that keep the reply hierarchy
+func TestDiscussionMode(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Feeding a sourdough starter</title></head>
+<body>
+	<article>
+		<h1>Feeding a sourdough starter</h1>
+		<p><span>A sourdough starter is a mix of flour and water in which wild yeast and bacteria live, and it takes about a week of daily feeding before it can raise a loaf.</span></p>
+	</article>
+	<section id="comments">
+		<ol class="comment-list">
+			<li class="comment">
+				<div class="comment-meta"><span class="comment-author">Ann</span> <time datetime="2024-05-01T09:30:00Z">May 1</time></div>
+				<div class="comment-content"><p>Does rye flour work?</p><p>I only have rye at home.</p></div>
+				<ol class="children">
+					<li class="comment">
+						<div class="comment-meta"><span class="comment-author">Ben</span> <time datetime="2024-05-01T10:00:00Z">May 1</time></div>
+						<div class="comment-content"><p>Yes, rye ferments even faster.</p></div>
+						<ol class="children">
+							<li class="comment">
+								<div class="comment-meta"><span class="comment-author">Ann</span></div>
+								<div class="comment-content"><p>Thanks, trying it tonight.</p></div>
+							</li>
+						</ol>
+					</li>
+					<li class="comment">
+						<div class="comment-meta"><span class="comment-author">Cai</span></div>
+						<div class="comment-content"><p>Mix it half and half with wheat.</p></div>
+					</li>
+				</ol>
+			</li>
+			<li class="comment">
+				<div class="comment-meta"><span class="comment-author">Dee</span></div>
+				<div class="comment-content"><p>Great guide!</p></div>
+			</li>
+		</ol>
+	</section>
+</body>
+</html>`
+
+	ex := readabiligo.New(readabiligo.WithDiscussionMode(true))
+	article, err := ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []readabiligo.ThreadPost{
+		{Author: "Ann", Timestamp: "2024-05-01T09:30:00Z", Body: "Does rye flour work?\n\nI only have rye at home.", Depth: 0, ParentIndex: -1},
+		{Author: "Ben", Timestamp: "2024-05-01T10:00:00Z", Body: "Yes, rye ferments even faster.", Depth: 1, ParentIndex: 0},
+		{Author: "Ann", Body: "Thanks, trying it tonight.", Depth: 2, ParentIndex: 1},
+		{Author: "Cai", Body: "Mix it half and half with wheat.", Depth: 1, ParentIndex: 0},
+		{Author: "Dee", Body: "Great guide!", Depth: 0, ParentIndex: -1},
+	}, article.Thread)
+
+	t.Run("FlatLayout", func(t *testing.T) {
+		html := `<html><head><title>Thread</title></head><body>
+			<shreddit-comment depth="0"><a class="author">ann</a><div slot="comment"><p>Is this safe to eat?</p></div></shreddit-comment>
+			<shreddit-comment depth="1"><a class="author">ben</a><div slot="comment"><p>Yes, once baked.</p></div></shreddit-comment>
+			<shreddit-comment depth="0"><a class="author">cai</a><div slot="comment"><p>Nice loaf.</p></div></shreddit-comment>
+		</body></html>`
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, article.Thread, 3) {
+			assert.Equal(t, "ben", article.Thread[1].Author)
+			assert.Equal(t, 1, article.Thread[1].Depth)
+			assert.Equal(t, 0, article.Thread[1].ParentIndex)
+			assert.Equal(t, -1, article.Thread[2].ParentIndex)
+		}
+	})
+
+	article, err = readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.Thread)
+}
+
+func TestAllowedSchemes(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Booking a table</title></head>
+<body>
+	<article>
+		<h1>Booking a table</h1>
+		<p><span>Tables for groups of up to six can be booked online at <a href="https://example.com/book">our booking page</a>, and larger groups should call us on <a href="tel:+15550100">555 0100</a> so that we can prepare the private room.</span></p>
+		<p><span>Questions about allergies can be sent to <a href="mailto:kitchen@example.com">the kitchen</a> ahead of the visit, and the menu is <a href="javascript:openMenu()">shown here</a> each week.</span></p>
+		<p><img src="https://example.com/room.jpg" alt="Private room"><img src="ftp://example.com/map.png" alt="Map"><img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt="Dot"></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "tel:")
+	assert.Contains(t, article.Content, "555 0100")
+	assert.Contains(t, article.Content, `href="https://example.com/book"`)
+	assert.Contains(t, article.Content, `href="mailto:kitchen@example.com"`)
+	assert.NotContains(t, article.Content, "javascript:")
+	assert.Contains(t, article.Content, "shown here")
+	assert.Contains(t, article.Content, `src="https://example.com/room.jpg"`)
+	assert.NotContains(t, article.Content, "ftp:")
+	assert.Contains(t, article.Content, `src="data:image/gif`)
+
+	ex := readabiligo.New(readabiligo.WithAllowedSchemes(append(readabiligo.DefaultAllowedSchemes(), "tel")...))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, `href="tel:+15550100"`)
+	assert.NotContains(t, article.Content, "javascript:")
+}
+
+func TestExtractTables(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Rainfall in the valley</title></head>
+<body>
+	<article>
+		<h1>Rainfall in the valley</h1>
+		<p><span>Rainfall in the valley varies a great deal over the year, with most of it falling during the late autumn storms that roll in from the coast.</span></p>
+		<table summary="Monthly totals measured at the valley station">
+			<caption>Rainfall by month</caption>
+			<thead><tr><th>Month</th><th>Rainfall (mm)</th></tr></thead>
+			<tbody>
+				<tr><td>January</td><td>82</td></tr>
+				<tr><td>February</td><td>64</td></tr>
+			</tbody>
+		</table>
+		<p><span>The driest months are in midsummer, when the station often records no rain at all for several weeks in a row.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.Tables)
+
+	ex := readabiligo.New(readabiligo.WithExtractTables(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []readabiligo.Table{{
+		Caption: "Rainfall by month",
+		Summary: "Monthly totals measured at the valley station",
+		Headers: []string{"Month", "Rainfall (mm)"},
+		Rows:    [][]string{{"January", "82"}, {"February", "64"}},
+	}}, article.Tables)
+	assert.Contains(t, article.Content, "<caption>Rainfall by month</caption>")
+}
+
+func TestTrimBoilerplateHeadings(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Repairing a bicycle chain</title></head>
+<body>
+	<article>
+		<h1>Repairing a bicycle chain</h1>
+		<p><span>A broken chain can be repaired at the roadside with a chain tool and a spare quick link, which takes only a few minutes once you know how.</span></p>
+		<h4>ADVERTISEMENT</h4>
+		<div class="ad-label"><p>Sponsored Content:</p></div>
+		<p><span>Push out the damaged link with the tool, then join the two ends with the quick link and pedal hard once to snap it shut.</span></p>
+		<p><span>Bike shops rely on advertisement to reach riders, but a chain tool costs less than a single repair at most of them.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "ADVERTISEMENT")
+	assert.NotContains(t, article.Content, "Sponsored Content")
+	assert.Contains(t, article.Content, "Bike shops rely on advertisement")
+
+	ex := readabiligo.New(readabiligo.WithTrimBoilerplateHeadings(false))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "ADVERTISEMENT")
+
+	ex = readabiligo.New(readabiligo.WithBoilerplateLabels("Sponsored Content"))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "ADVERTISEMENT")
+	assert.NotContains(t, article.Content, "Sponsored Content")
+}
+
+func TestLogger(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Planting garlic</title></head>
+<body>
+	<div id="cookie-consent" class="cookie-banner"><p>We use cookies to improve your experience. <button>Accept</button></p></div>
+	<article>
+		<h1>Planting garlic</h1>
+		<p><span>Garlic is planted in autumn, a few weeks before the first frost, so that the cloves can grow roots before the ground freezes over winter.</span></p>
+		<p style="display:none">Hidden promotional text</p>
+		<p><span>Set each clove pointed end up, two inches deep and six inches apart, and cover the bed with straw once the ground turns cold.</span></p>
+	</article>
+</body>
+</html>`
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// Capture stdout to check that nothing is printed
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	os.Stdout = w
+	article, err := readabiligo.New(readabiligo.WithLogger(logger)).ExtractFromHTML(html, nil)
+	os.Stdout = stdout
+	assert.NoError(t, w.Close())
+	printed, _ := io.ReadAll(r)
+
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "Garlic is planted in autumn")
+	assert.Empty(t, string(printed))
+
+	hasEvent := func(phase, action, node string) bool {
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var event map[string]any
+			if err := json.Unmarshal([]byte(line), &event); err != nil {
+				t.Fatalf("Failed to decode log event %q: %v", line, err)
+			}
+			if event["level"] == "DEBUG" && event["phase"] == phase && event["action"] == action && event["node"] == node {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, hasEvent("prepare", "remove consent banner", "div#cookie-consent.cookie-banner"), logs.String())
+	assert.True(t, hasEvent("prepare", "remove hidden content", "p"), logs.String())
+}
+
+func TestExtractAlternates(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+	<title>Autumn bird migration</title>
+	<base href="https://birds.example.com/news/">
+	<link rel="alternate" type="application/rss+xml" title="Bird news feed" href="/feed.xml">
+	<link rel="amphtml" href="autumn-migration.amp.html">
+	<link rel="alternate stylesheet" href="/dark.css" title="Dark">
+</head>
+<body>
+	<article>
+		<h1>Autumn bird migration</h1>
+		<p><span>Every autumn millions of birds leave their breeding grounds in the north and head south along the coast, resting in the marshes on the way.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.AlternateLinks)
+
+	ex := readabiligo.New(readabiligo.WithExtractAlternates(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []readabiligo.AlternateLink{
+		{Type: "application/rss+xml", Href: "https://birds.example.com/feed.xml", Title: "Bird news feed"},
+		{Type: "amp", Href: "https://birds.example.com/news/autumn-migration.amp.html"},
+	}, article.AlternateLinks)
+}
+
+func TestDehyphenate(t *testing.T) {
+	html := "<!DOCTYPE html>\n<html>\n<head><title>Scanned report</title></head>\n<body>\n\t<article>\n" +
+		"\t\t<p><span>This scanned report is a well-\nknown exam-\nple of text converted from a printed page, where words are broken across lines at the margin.</span></p>\n" +
+		"\t\t<pre>total-\nlength</pre>\n" +
+		"\t</article>\n</body>\n</html>"
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "exam-\nple")
+
+	ex := readabiligo.New(readabiligo.WithDehyphenate(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "a well-known example of text")
+	assert.Contains(t, article.Content, "total-\nlength")
+	assert.Contains(t, article.PlainContent, "a well-known example of text")
+}
+
+func TestMaxImages(t *testing.T) {
+	var gallery strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&gallery, `<figure><img src="https://example.com/photos/%d.jpg" alt="Photo %d"><figcaption>Photo %d</figcaption></figure>`, i, i, i)
+		gallery.WriteString("\n")
+	}
+	html := `<!DOCTYPE html>
+<html>
+<head>
+	<title>A week on the islands</title>
+	<meta property="og:image" content="https://example.com/photos/12.jpg">
+</head>
+<body>
+	<article>
+		<h1>A week on the islands</h1>
+		<p><span>We spent a week sailing between the islands, anchoring in a different bay each night and going ashore to walk the cliff paths in the mornings.</span></p>
+		` + gallery.String() + `
+		<p><span>The weather held for all but the last day, when a storm kept us in the harbour and we spent the afternoon in the pub by the quay.</span></p>
+	</article>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, article.ImageCount)
+	assert.Equal(t, 20, strings.Count(article.Content, "<img"))
+
+	ex := readabiligo.New(readabiligo.WithMaxImages(5))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, article.ImageCount)
+	assert.Equal(t, 5, strings.Count(article.Content, "<img"))
+	for _, kept := range []int{1, 2, 3, 4, 12} {
+		assert.Contains(t, article.Content, fmt.Sprintf(`src="https://example.com/photos/%d.jpg"`, kept))
+	}
+	assert.NotContains(t, article.Content, "photos/5.jpg")
+	assert.NotContains(t, article.Content, "Photo 20")
+	assert.Contains(t, article.Content, "the pub by the quay")
+}
+
+func TestLiveBlogMode(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head><title>Marathon day: live</title></head>
+<body>
+	<main>
+		<h1>Marathon day: live</h1>
+		<div class="feed">
+			<div class="entry">
+				<time datetime="2024-04-21T12:40:00Z">12:40</time>
+				<h3>The winner crosses the line</h3>
+				<p>The leader finishes in just over two hours and eight minutes.</p>
+			</div>
+			<div class="entry">
+				<time datetime="2024-04-21T11:15:00Z">11:15</time>
+				<p>The lead group has broken away at the halfway mark.</p>
+			</div>
+			<div class="entry">
+				<time datetime="2024-04-21T10:00:00Z">10:00</time>
+				<p>The elite runners are off, under a cloudy sky.</p>
+			</div>
+		</div>
+	</main>
+</body>
+</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.Updates)
+
+	ex := readabiligo.New(readabiligo.WithLiveBlogMode(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []readabiligo.LiveUpdate{
+		{Timestamp: "2024-04-21T10:00:00Z", Body: "The elite runners are off, under a cloudy sky."},
+		{Timestamp: "2024-04-21T11:15:00Z", Body: "The lead group has broken away at the halfway mark."},
+		{Timestamp: "2024-04-21T12:40:00Z", Body: "The winner crosses the line\n\nThe leader finishes in just over two hours and eight minutes."},
+	}, article.Updates)
+
+	t.Run("LiveBlogMarkup", func(t *testing.T) {
+		html := `<html><head><title>Election night</title></head><body>
+			<article itemscope itemtype="https://schema.org/LiveBlogPosting">
+				<div itemprop="liveBlogUpdate"><span class="timestamp">22:30</span><p>First results are in.</p></div>
+				<div itemprop="liveBlogUpdate"><span class="timestamp">22:05</span><p>Polls have closed.</p></div>
+			</article>
+		</body></html>`
+		article, err := ex.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []readabiligo.LiveUpdate{
+			{Timestamp: "22:05", Body: "Polls have closed."},
+			{Timestamp: "22:30", Body: "First results are in."},
+		}, article.Updates)
+	})
+}
+
+func TestStrictTitle(t *testing.T) {
+	body := `<body><div><p><span>` + strings.Repeat("The committee met on Tuesday to review the proposals for the new library. ", 8) + `</span></p></div></body>`
+	generic := `<html><head><title>Home</title></head>` + body + `</html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(generic, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Home", article.Title)
+
+	strict := readabiligo.New(readabiligo.WithStrictTitle(true))
+	_, err = strict.ExtractFromHTML(generic, nil)
+	assert.ErrorIs(t, err, readabiligo.ErrNoTitle)
+
+	t.Run("AgreeingSources", func(t *testing.T) {
+		html := `<html><head>
+			<title>Library plans approved by committee | Town News</title>
+			<meta property="og:title" content="Library plans approved by committee">
+		</head>` + body + `</html>`
+		article, err := strict.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Library plans approved by committee", article.Title)
+	})
+
+	t.Run("IdenticalSources", func(t *testing.T) {
+		html := `<html><head>
+			<title>Library plans approved by committee</title>
+			<meta property="og:title" content="Library plans approved by committee">
+		</head>` + body + `</html>`
+		article, err := strict.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Library plans approved by committee", article.Title)
+	})
+
+	t.Run("CopiedMetaTags", func(t *testing.T) {
+		html := `<html><head>
+			<title>Home</title>
+			<meta property="og:title" content="Library plans approved by committee">
+			<meta name="twitter:title" content="Library plans approved by committee">
+		</head>` + body + `</html>`
+		_, err := strict.ExtractFromHTML(html, nil)
+		assert.ErrorIs(t, err, readabiligo.ErrNoTitle)
+	})
+
+	t.Run("ContentHeading", func(t *testing.T) {
+		html := `<html><head><title>Town News</title></head><body>
+			<article><h1>Library plans approved by committee</h1><p><span>` +
+			strings.Repeat("The committee met on Tuesday to review the proposals for the new library. ", 8) +
+			`</span></p></article></body></html>`
+		article, err := strict.ExtractFromHTML(html, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Library plans approved by committee", article.Title)
+	})
+}
+
+func TestExtractQuotes(t *testing.T) {
+	html := `<html><head>
+		<title>Quotes on the record today</title>
+		<base href="https://example.com/news/">
+	</head><body><article>
+		<p><span>` + strings.Repeat("Words of the speech were reported widely in the press. ", 6) + `</span></p>
+		<blockquote cite="/speeches/42">
+			<p><span>We choose to go to the moon in this decade and do the other things.</span></p>
+			<footer>— <cite>John F. Kennedy</cite></footer>
+		</blockquote>
+		<p><span>A reporter later wrote that <q cite="coverage.html">the crowd fell silent</q> at that moment.</span></p>
+	</article></body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, article.Quotes)
+	assert.Contains(t, article.Content, `<blockquote cite="https://example.com/speeches/42">`)
+
+	ex := readabiligo.New(readabiligo.WithExtractQuotes(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []readabiligo.Quote{
+		{
+			Text:        "We choose to go to the moon in this decade and do the other things.",
+			CiteURL:     "https://example.com/speeches/42",
+			Attribution: "John F. Kennedy",
+		},
+		{
+			Text:    "the crowd fell silent",
+			CiteURL: "https://example.com/news/coverage.html",
+		},
+	}, article.Quotes)
+	assert.NotContains(t, article.Content, "data-readability-attribution")
+}
+
+func TestNormalizeListMarkers(t *testing.T) {
+	intro := `<p><span>` + strings.Repeat("Here is what you need to pack for the trip into the mountains. ", 5) + `</span></p>`
+	html := `<html><head><title>Packing for the mountains this summer</title></head><body><article>` + intro + `
+		<p><span>• A warm jacket</span></p>
+		<p><span>• Sturdy walking boots</span></p>
+		<p><span>• A refillable water bottle</span></p>
+		<p><span>- Just one more thing, said the guide, before we set off.</span></p>
+		<p><span>1. Check the weather</span></p>
+		<p><span>2. Tell someone your route</span></p>
+	</article></body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "<ul>")
+
+	ex := readabiligo.New(readabiligo.WithNormalizeListMarkers(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	compact := regexp.MustCompile(`>\s+<`).ReplaceAllString(article.Content, "><")
+	assert.Contains(t, compact, `<ul><li><span>A warm jacket</span></li><li><span>Sturdy walking boots</span></li><li><span>A refillable water bottle</span></li></ul>`)
+	assert.Contains(t, compact, `<p><span>- Just one more thing, said the guide, before we set off.</span></p>`)
+	assert.Contains(t, compact, `<ol><li><span>Check the weather</span></li><li><span>Tell someone your route</span></li></ol>`)
+}
+
+func TestUnlikelyRoles(t *testing.T) {
+	paragraphs := strings.Repeat(`<p><span>The council voted on Tuesday night to approve the plan for the new library, after months of debate over its cost and location in the town centre.</span></p>`, 5)
+	html := `<html><head><title>Library plans approved by the committee</title></head><body>` +
+		`<div role="search"><p><span>Search the archive of local news stories by keyword</span></p></div>` +
+		`<div role="complementary"><p><span>Most read stories this week across the region</span></p></div>` +
+		`<div class="article-body">` + paragraphs + `</div>` +
+		`</body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "Search the archive")
+	assert.NotContains(t, article.Content, "Most read stories")
+	assert.Empty(t, article.Warnings)
+
+	article, err = readabiligo.New(readabiligo.WithUnlikelyRoles("search")).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "Search the archive")
+
+	article, err = readabiligo.New(readabiligo.WithAllowedRoles("complementary")).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "Most read stories")
+
+	article, err = readabiligo.New(readabiligo.WithUnlikelyRoles("sidebar")).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Warnings, `unlikely roles: "sidebar" is not a known ARIA role`)
+
+	article, err = readabiligo.New(readabiligo.WithUnlikelyRoles("Search")).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "Search the archive")
+	assert.Empty(t, article.Warnings)
+
+	assert.Contains(t, readabiligo.DefaultUnlikelyRoles(), "complementary")
+}
+
+func TestMergeImageAltIntoCaption(t *testing.T) {
+	paragraphs := strings.Repeat(`<p><span>The harbour was rebuilt over the winter, with new moorings for the fishing fleet and a wider promenade for visitors.</span></p>`, 4)
+	html := `<html><head><title>The harbour reopens after the winter works</title></head><body><article>` + paragraphs +
+		`<figure><img src="/harbour.jpg" alt="Fishing boats moored along the rebuilt harbour wall"></figure>` +
+		`<figure><img src="/crane.jpg" alt="IMG_2041.jpg"></figure>` +
+		`<figure><img src="/promenade.jpg" alt="The new promenade"><figcaption>Visitors on the promenade</figcaption></figure>` +
+		`</article></body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, ">Fishing boats")
+
+	ex := readabiligo.New(readabiligo.WithMergeImageAltIntoCaption(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, ">Fishing boats moored along the rebuilt harbour wall</figcaption>")
+	assert.NotContains(t, article.Content, ">IMG_2041.jpg</figcaption>")
+	assert.NotContains(t, article.Content, ">The new promenade</figcaption>")
+	assert.Equal(t, 2, strings.Count(article.Content, "<figcaption"), "only the uncaptioned figure with a descriptive alt gains a caption")
+}
+
+func TestExtractStructuredContent(t *testing.T) {
+	paragraphs := strings.Repeat(`<p><span>This is the loaf my grandmother baked every Sunday, and it has never once failed me in thirty years of baking.</span></p>`, 4)
+	html := `<html><head><title>Sunday banana bread</title>
+		<script type="application/ld+json">{
+			"@context": "https://schema.org",
+			"@graph": [
+				{"@type": "WebPage", "name": "Sunday banana bread"},
+				{
+					"@type": "Recipe",
+					"name": "Sunday banana bread",
+					"description": "A moist loaf that uses up <b>overripe</b> bananas.",
+					"recipeIngredient": ["3 ripe bananas", "250 g flour", "2 eggs"],
+					"recipeInstructions": [
+						{"@type": "HowToSection", "name": "Batter", "itemListElement": [
+							{"@type": "HowToStep", "text": "Mash the bananas."},
+							{"@type": "HowToStep", "name": "Combine", "text": "Beat in the eggs, then fold in the flour."}
+						]},
+						{"@type": "HowToStep", "text": "Bake for 60 minutes at 180&deg;C."}
+					],
+					"prepTime": "PT15M",
+					"cookTime": "PT1H",
+					"totalTime": "PT1H15M",
+					"recipeYield": ["8", "1 loaf"]
+				}
+			]
+		}</script></head><body><article>` + paragraphs + `</article></body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, article.Recipe)
+
+	article, err = readabiligo.New(readabiligo.WithExtractStructuredContent(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, article.HowTo)
+	if assert.NotNil(t, article.Recipe) {
+		assert.Equal(t, "Sunday banana bread", article.Recipe.Name)
+		assert.Equal(t, "A moist loaf that uses up overripe bananas.", article.Recipe.Description)
+		assert.Equal(t, []string{"3 ripe bananas", "250 g flour", "2 eggs"}, article.Recipe.Ingredients)
+		assert.Equal(t, []readabiligo.HowToStep{
+			{Section: "Batter", Text: "Mash the bananas."},
+			{Section: "Batter", Name: "Combine", Text: "Beat in the eggs, then fold in the flour."},
+			{Text: "Bake for 60 minutes at 180°C."},
+		}, article.Recipe.Steps)
+		assert.Equal(t, "PT15M", article.Recipe.PrepTime)
+		assert.Equal(t, "PT1H", article.Recipe.CookTime)
+		assert.Equal(t, "PT1H15M", article.Recipe.TotalTime)
+		assert.Equal(t, "1 loaf", article.Recipe.Yield)
+	}
+	assert.Contains(t, article.Content, "grandmother baked")
+}
+
+func TestExtractStructuredContentHowTo(t *testing.T) {
+	paragraphs := strings.Repeat(`<p><span>A simple shelf is a good first woodworking project, and needs only a few tools you probably already own.</span></p>`, 4)
+	html := `<html><head><title>How to build a wall shelf</title>
+		<script type="application/ld+json">[{
+			"@context": "https://schema.org",
+			"@type": "HowTo",
+			"name": "Build a wall shelf",
+			"supply": [{"@type": "HowToSupply", "name": "Pine board"}, "Wood screws"],
+			"tool": {"@type": "HowToTool", "name": "Drill"},
+			"step": ["Cut the board to length.", {"@type": "HowToStep", "name": "Mount", "text": "Screw the brackets to the wall."}],
+			"totalTime": "PT45M"
+		}]</script></head><body><article>` + paragraphs + `</article></body></html>`
+
+	article, err := readabiligo.New(readabiligo.WithExtractStructuredContent(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, article.Recipe)
+	if assert.NotNil(t, article.HowTo) {
+		assert.Equal(t, "Build a wall shelf", article.HowTo.Name)
+		assert.Equal(t, []string{"Pine board", "Wood screws"}, article.HowTo.Supplies)
+		assert.Equal(t, []string{"Drill"}, article.HowTo.Tools)
+		assert.Equal(t, []readabiligo.HowToStep{
+			{Text: "Cut the board to length."},
+			{Name: "Mount", Text: "Screw the brackets to the wall."},
+		}, article.HowTo.Steps)
+		assert.Equal(t, "PT45M", article.HowTo.TotalTime)
+	}
+}
+
+func TestContentBoundaryMarkers(t *testing.T) {
+	paragraphs := strings.Repeat(`<p><span>The museum reopened its east wing on Saturday after a two year restoration of the painted ceilings and the original oak floors.</span></p>`, 4)
+	html := `<html><head><title>The museum reopens its east wing</title></head><body>` +
+		`<div id="story" class="story-body">` + paragraphs + `</div></body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, article.Content, "readabiligo:content-start")
+
+	article, err = readabiligo.New(readabiligo.WithContentBoundaryMarkers(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	start := regexp.MustCompile(`^<!-- readabiligo:content-start selector=(\S+) score=(\S+) source=(\S+) -->`).FindStringSubmatch(article.Content)
+	if assert.NotNil(t, start, article.Content) {
+		assert.NotEmpty(t, start[1])
+		assert.Contains(t, []string{"candidate", "microdata", "body", "fallback"}, start[3])
+	}
+	assert.True(t, strings.HasSuffix(article.Content, "<!-- readabiligo:content-end -->"))
+	assert.Contains(t, article.Content, "painted ceilings")
+	assert.Equal(t, 1, strings.Count(article.Content, "readabiligo:content-start"))
+	assert.NotContains(t, article.PlainContent, "readabiligo:content")
+}
+
+func TestImageDimensionInference(t *testing.T) {
+	paragraphs := strings.Repeat(`<p><span>The harbour was rebuilt over the winter, with new moorings for the fishing fleet and a wider promenade for visitors.</span></p>`, 4)
+	html := `<html><head><title>The harbour reopens after the winter works</title></head><body><article>` +
+		`<img src="https://cdn.example.com/icons/share-32x32.png">` + paragraphs +
+		`<img src="https://cdn.example.com/photos/harbour-1200x800.jpg">` +
+		`<img src="https://cdn.example.com/photos/promenade.jpg?w=1200">` +
+		`</article></body></html>`
+
+	article, err := readabiligo.New(readabiligo.WithMaxImages(1)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, article.Content, "share-32x32.png")
+	assert.NotContains(t, article.Content, "harbour-1200x800.jpg")
+
+	ex := readabiligo.New(readabiligo.WithMaxImages(1), readabiligo.WithImageDimensionInference(true))
+	article, err = ex.ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, article.ImageCount)
+	assert.Contains(t, article.Content, "harbour-1200x800.jpg")
+	assert.NotContains(t, article.Content, "share-32x32.png")
+	assert.NotContains(t, article.Content, "promenade.jpg")
+}
+
+// TestArticleText tests joining the plain text blocks with Text and
+// PlainTextString
+func TestArticleText(t *testing.T) {
+	article := &readabiligo.Article{PlainText: []readabiligo.Block{
+		{Text: "Feeding", Type: readabiligo.BlockHeading, Level: 2},
+		{Text: "Feed the starter every day.", Type: readabiligo.BlockParagraph},
+		{Text: "Keep it warm.", Type: readabiligo.BlockParagraph},
+	}}
+
+	assert.Equal(t, "Feeding\n\nFeed the starter every day.\n\nKeep it warm.", article.PlainTextString())
+	assert.Equal(t, "Feeding\nFeed the starter every day.\nKeep it warm.", article.Text("\n"))
+	assert.Equal(t, article.PlainTextString(), article.Text(readabiligo.DefaultBlockSeparator))
+	assert.Equal(t, "", (&readabiligo.Article{}).PlainTextString())
+}
+
+// TestMinContentScore tests that WithMinContentScore rejects a page whose best
+// candidate scores below the floor, reporting the score, and keeps articles
+func TestMinContentScore(t *testing.T) {
+	weak := `<html><head><title>Shop</title></head><body>
+		<div class="menu"><a href="/">Home</a> <a href="/shop">Shop</a></div>
+		<div><p><span>Free shipping on all orders over fifty dollars.</span></p></div>
+	</body></html>`
+	strong := `<html><head><title>Pruning roses</title></head><body><article>` +
+		strings.Repeat(`<p><span>Roses are best pruned in late winter, just before the buds begin to swell, so that the plant puts its energy into new growth, and the cuts heal quickly.</span></p>`, 5) +
+		`</article></body></html>`
+
+	// Without a floor, the weak page still yields content
+	_, err := readabiligo.New().ExtractFromHTML(weak, nil)
+	assert.NoError(t, err)
+
+	extractor := readabiligo.New(readabiligo.WithMinContentScore(15))
+	_, err = extractor.ExtractFromHTML(weak, nil)
+	assert.True(t, errors.Is(err, readabiligo.ErrNoContent))
+	var scoreErr *readabiligo.ContentScoreError
+	if assert.True(t, errors.As(err, &scoreErr)) {
+		assert.Less(t, scoreErr.Score, 15.0)
+		assert.Greater(t, scoreErr.Score, 0.0)
+		assert.Equal(t, 15.0, scoreErr.MinScore)
+	}
+
+	article, err := extractor.ExtractFromHTML(strong, nil)
+	if assert.NoError(t, err) {
+		assert.Contains(t, article.PlainContent, "Roses are best pruned")
+	}
+}
+
+// TestExtractContactInfo tests that WithExtractContactInfo collects the email
+// addresses and phone numbers of an about page from its links and text,
+// without duplicates, and leaves dates alone
+func TestExtractContactInfo(t *testing.T) {
+	html := `<html><head><title>About us</title></head><body><main>
+		<h1>About the Harbour Bakery</h1>
+		<p><span>We have baked bread on the quay since 1998, and the shop was rebuilt in 2010-2011 after the flood of 2009-11-04.</span></p>
+		<p><span>For orders, write to </span><a href="mailto:Orders@HarbourBakery.example?subject=Order"><span>Orders@HarbourBakery.example</span></a><span> or call </span><a href="tel:+44-20-7946-0958"><span>our shop</span></a><span>.</span></p>
+		<p><span>The press office answers at press@harbourbakery.example and on (555) 123-4567, also listed as 555.123.4567, from Monday to Friday.</span></p>
+		<p><span>International wholesale enquiries: +1 415 555 0199.</span></p>
+	</main></body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, article.ContactInfo)
+
+	article, err = readabiligo.New(readabiligo.WithExtractContactInfo(true)).ExtractFromHTML(html, nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, article.ContactInfo) {
+		assert.Equal(t, []string{"orders@harbourbakery.example", "press@harbourbakery.example"}, article.ContactInfo.Emails)
+		assert.Equal(t, []string{"+442079460958", "5551234567", "+14155550199"}, article.ContactInfo.Phones)
+	}
+}
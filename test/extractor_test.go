@@ -2,15 +2,19 @@ package test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/mrjoshuak/readabiligo"
+	"golang.org/x/text/encoding/charmap"
 )
 
 // TestExtractFromFile tests basic article extraction from a file
@@ -565,6 +569,498 @@ func TestExtractionTimeout(t *testing.T) {
 	}
 }
 
+// TestExtractWithVariants tests that both the strict and the lenient result are returned
+func TestExtractWithVariants(t *testing.T) {
+	// A page too short for the strict attempt, so extraction falls back to relaxed flags
+	html := `<html><head><title>Borderline</title></head><body>
+		<article><p><span>A short introduction that is well below the character threshold.</span></p></article>
+		<div class="comment-thread"><p><span>A reader comment that is not part of the article.</span></p></div>
+	</body></html>`
+
+	ext := readabiligo.New().(readabiligo.ExtendedExtractor)
+	variants, err := ext.ExtractWithVariants(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract variants: %v", err)
+	}
+	if variants.Strict == nil || variants.Lenient == nil {
+		t.Fatalf("Expected both variants, got strict=%v lenient=%v", variants.Strict, variants.Lenient)
+	}
+
+	if !strings.Contains(variants.Lenient.Content, "short introduction") {
+		t.Errorf("Lenient variant should contain the introduction: %s", variants.Lenient.Content)
+	}
+	if variants.Strict.Content == variants.Lenient.Content {
+		t.Error("Strict variant should keep the first attempt instead of the fallback result")
+	}
+
+	// The lenient variant is the default extraction result
+	article, err := ext.ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.Content != variants.Lenient.Content {
+		t.Error("Lenient variant should match ExtractFromHTML")
+	}
+}
+
+// TestMicrodataExtraction tests that schema.org microdata supplies metadata and the content node
+func TestMicrodataExtraction(t *testing.T) {
+	paragraph := strings.Repeat("<span>The harbor reopened this week after months of repairs to the old sea wall. </span>", 4)
+	html := `<html><head><title>Harbor News</title></head><body>
+		<div itemscope itemtype="https://schema.org/NewsArticle">
+			<h1 itemprop="headline">Harbor Reopens</h1>
+			<div itemprop="author" itemscope itemtype="https://schema.org/Person">
+				<span itemprop="name">Maria Lopez</span>
+			</div>
+			<time itemprop="datePublished" datetime="2024-03-01T10:00:00Z">March 1</time>
+			<div itemprop="articleBody"><p>` + paragraph + `</p><p>` + paragraph + `</p></div>
+		</div>
+		<div><p><span>Subscribe to our newsletter for weekly updates from the waterfront.</span></p></div>
+	</body></html>`
+
+	ext := readabiligo.New()
+	article, err := ext.ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+
+	if article.Byline != "Maria Lopez" {
+		t.Errorf("Expected byline from microdata, got %q", article.Byline)
+	}
+	if want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC); !article.Date.Equal(want) {
+		t.Errorf("Expected date %v from microdata, got %v", want, article.Date)
+	}
+	if !strings.Contains(article.Content, "sea wall") {
+		t.Errorf("Content should contain the articleBody text: %s", article.Content)
+	}
+	if strings.Contains(article.Content, "newsletter") {
+		t.Errorf("Content should be limited to the articleBody element: %s", article.Content)
+	}
+}
+
+// TestTruncatedContentDetection tests that teaser pages report the full article URL
+func TestTruncatedContentDetection(t *testing.T) {
+	ext := readabiligo.New()
+
+	teaser := `<html><head><title>Teaser</title>
+		<link rel="canonical" href="https://example.com/teaser">
+		<meta property="og:url" content="https://example.com/teaser">
+	</head><body><article>
+		<h1>City Council Approves Budget</h1>
+		<p><span>The council voted late on Tuesday to approve next year's budget.</span></p>
+		<p><a href="/news/council-budget-full">Continue reading →</a></p>
+	</article></body></html>`
+
+	article, err := ext.ExtractFromHTML(teaser, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if !article.IsTruncated {
+		t.Error("Expected teaser page to be detected as truncated")
+	}
+	if article.FullContentURL != "https://example.com/news/council-budget-full" {
+		t.Errorf("Expected full content URL from the continue link, got %q", article.FullContentURL)
+	}
+
+	// A continue link back to the page itself does not mark the content as truncated
+	selfLink := strings.Replace(teaser, "/news/council-budget-full", "https://example.com/teaser", 1)
+	article, err = ext.ExtractFromHTML(selfLink, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.IsTruncated || article.FullContentURL != "" {
+		t.Errorf("Expected no truncation for a link to the canonical URL, got %q", article.FullContentURL)
+	}
+
+	// Ordinary links to more information do not mark the content as truncated
+	moreInfo := strings.Replace(teaser, "Continue reading →", "More info on opening hours", 1)
+	article, err = ext.ExtractFromHTML(moreInfo, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.IsTruncated || article.FullContentURL != "" {
+		t.Errorf("Expected no truncation for an ordinary link, got %q", article.FullContentURL)
+	}
+}
+
+// TestMultipleH1TitleSelection tests that the article's own h1 is chosen as the
+// title over a site-name h1 when the page title is unusable
+func TestMultipleH1TitleSelection(t *testing.T) {
+	body := `<p>The city council voted on Tuesday to approve funding for a new public library in the town centre, ending years of debate about the site.</p>
+		<p>Construction is expected to begin next spring, and the library should open its doors to readers within two years of the first spade in the ground.</p>`
+
+	tests := []struct {
+		name string
+		html string
+	}{
+		{
+			name: "h1 inside the content container",
+			html: `<html><head><title>Acme</title></head><body>
+				<header><h1>Acme Daily News Network Online</h1></header>
+				<article><h1>Council approves funding for new library</h1>` + body + `</article>
+			</body></html>`,
+		},
+		{
+			name: "h1 most similar to og:title",
+			html: `<html><head><title>Acme</title><meta property="og:title" content="Council approves funding for new library"></head><body>
+				<div class="masthead"><h1>Acme Daily News Network Online</h1></div>
+				<div class="story"><h1>Council approves funding for new library</h1>` + body + `</div>
+			</body></html>`,
+		},
+		{
+			name: "h1 the article is aria-labelledby",
+			html: `<html><head><title>Acme</title></head><body>
+				<article aria-labelledby="headline"><h1>Local news from the town centre today</h1>
+				<h1 id="headline">Council approves funding for new library</h1>` + body + `</article>
+			</body></html>`,
+		},
+	}
+
+	ext := readabiligo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article, err := ext.ExtractFromHTML(tt.html, nil)
+			if err != nil {
+				t.Fatalf("Failed to extract article: %v", err)
+			}
+			if article.Title != "Council approves funding for new library" {
+				t.Errorf("Expected the article h1 as title, got %q", article.Title)
+			}
+		})
+	}
+}
+
+// TestARIATitleAndByline tests that the accessible name and description of
+// the article element are used as title and byline when the page has no better
+// source, and count as a title source for WithStrictTitle
+func TestARIATitleAndByline(t *testing.T) {
+	body := `<p>The city council voted on Tuesday to approve funding for a new public library in the town centre, ending years of debate about the site.</p>
+		<p>Construction is expected to begin next spring, and the library should open its doors to readers within two years of the first spade in the ground.</p>`
+
+	html := `<html><head></head><body>
+		<main aria-label="Council approves funding for new library" aria-describedby="summary credit">
+		<p id="summary">The vote ends years of debate.</p>
+		<p id="credit">By Jane Doe</p>` + body + `</main>
+	</body></html>`
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.Title != "Council approves funding for new library" {
+		t.Errorf("Expected the aria-label as title, got %q", article.Title)
+	}
+	if article.Byline != "Jane Doe" {
+		t.Errorf("Expected the aria-describedby byline, got %q", article.Byline)
+	}
+
+	// A <title> agreeing with the article's aria-labelledby heading is confident
+	labelled := `<html><head><title>Council approves funding for new library - Acme</title></head><body>
+		<div><h2 id="t">Council approves funding for new library</h2></div>
+		<article aria-labelledby="t">` + body + `</article>
+	</body></html>`
+	strict := readabiligo.New(readabiligo.WithStrictTitle(true))
+	if _, err := strict.ExtractFromHTML(labelled, nil); err != nil {
+		t.Errorf("Expected a confident title from the <title> and aria-labelledby, got %v", err)
+	}
+	unlabelled := strings.Replace(labelled, ` aria-labelledby="t"`, "", 1)
+	if _, err := strict.ExtractFromHTML(unlabelled, nil); !errors.Is(err, readabiligo.ErrNoTitle) {
+		t.Errorf("Expected ErrNoTitle without the aria-labelledby, got %v", err)
+	}
+}
+
+// TestExtractStreaming tests that streaming extraction reports content blocks
+// and skips navigation, scripts and link lists
+func TestExtractStreaming(t *testing.T) {
+	html := `<html><head><title>Streaming</title><script>var tracking = "a script that should never be reported as text";</script></head><body>
+		<nav><p>Home, News, Sport, Weather, and everything else in the site menu</p></nav>
+		<div class="sidebar"><p>Popular stories from around the site this week, chosen by editors</p></div>
+		<article>
+			<p>The first paragraph of the article, which is long enough to count as content.</p>
+			<p>The second paragraph continues the story with a <a href="/more">short link</a> in its text.
+			<ul>
+				<li><a href="/a">Related story one</a></li>
+				<li><a href="/b">Related story two, with a longer headline</a></li>
+			</ul>
+		</article>
+	</body></html>`
+
+	var blocks []string
+	ext := readabiligo.New().(readabiligo.ExtendedExtractor)
+	err := ext.ExtractStreaming(strings.NewReader(html), func(block readabiligo.Block) {
+		blocks = append(blocks, block.Text)
+	})
+	if err != nil {
+		t.Fatalf("Failed to stream blocks: %v", err)
+	}
+
+	expected := []string{
+		"The first paragraph of the article, which is long enough to count as content.",
+		"The second paragraph continues the story with a short link in its text.",
+	}
+	if len(blocks) != len(expected) {
+		t.Fatalf("Expected %d blocks, got %d: %q", len(expected), len(blocks), blocks)
+	}
+	for i, text := range expected {
+		if blocks[i] != text {
+			t.Errorf("Block %d: expected %q, got %q", i, text, blocks[i])
+		}
+	}
+}
+
+// TestExtractStreamingLargeDocument tests that streaming extraction of a large
+// document runs in constant memory
+func TestExtractStreamingLargeDocument(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping large document streaming test in short mode")
+	}
+
+	ext := readabiligo.New().(readabiligo.ExtendedExtractor)
+
+	// Stream the large fixture if it has been downloaded
+	testFile := filepath.Join("data", "benchmarkinghuge.html")
+	if file, err := os.Open(testFile); err == nil {
+		defer file.Close()
+		count := 0
+		if err := ext.ExtractStreaming(file, func(block readabiligo.Block) { count++ }); err != nil {
+			t.Fatalf("Failed to stream %s: %v", testFile, err)
+		}
+		if count == 0 {
+			t.Errorf("Expected blocks from %s", testFile)
+		}
+	}
+
+	// Stream a generated document much larger than the expected heap, large
+	// enough that a quarter of it is well above the 4MB heap the runtime
+	// allows before collecting
+	const paragraphs = 400000
+	paragraph := "<p>A paragraph of generated article text, long enough to be reported as a block.</p>\n"
+	r := io.MultiReader(
+		strings.NewReader("<html><body><article>"),
+		&repeatReader{s: paragraph, n: paragraphs},
+		strings.NewReader("</article></body></html>"),
+	)
+	size := len(paragraph) * paragraphs
+
+	runtime.GC()
+	var stats runtime.MemStats
+	var peak uint64
+	count := 0
+	err := ext.ExtractStreaming(r, func(block readabiligo.Block) {
+		count++
+		if count%10000 == 0 {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapAlloc > peak {
+				peak = stats.HeapAlloc
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("Failed to stream generated document: %v", err)
+	}
+	if count != paragraphs {
+		t.Errorf("Expected %d blocks, got %d", paragraphs, count)
+	}
+	if peak > uint64(size/4) {
+		t.Errorf("Heap grew to %d bytes while streaming a %d byte document", peak, size)
+	}
+}
+
+// repeatReader is an io.Reader that yields a string n times without holding
+// the whole output in memory
+type repeatReader struct {
+	s   string
+	n   int
+	off int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, io.EOF
+	}
+	copied := copy(p, r.s[r.off:])
+	r.off += copied
+	if r.off == len(r.s) {
+		r.off = 0
+		r.n--
+	}
+	return copied, nil
+}
+
+// TestExtractAll tests that each post of a forum thread is extracted as a
+// separate article
+func TestExtractAll(t *testing.T) {
+	posts := []struct {
+		title string
+		text  string
+	}{
+		{"Sourdough starter not rising", "My starter has been sitting on the counter for a week, and it bubbles a little, but it never doubles in size no matter how often I feed it."},
+		{"Re: Sourdough starter not rising", "Try feeding it with wholemeal rye flour for a few days, and keep it somewhere warmer, because a cold kitchen slows the yeast right down."},
+		{"Re: Sourdough starter not rising", "Chlorinated tap water can also be the culprit, so leave the water out overnight, or use filtered water, before you mix it into the starter."},
+	}
+
+	page := func(count int) string {
+		var thread strings.Builder
+		for i, post := range posts[:count] {
+			thread.WriteString(fmt.Sprintf(`<div class="post" id="post-%d">
+				<div class="post-meta"><h2>%s</h2><span class="author">user%d</span></div>
+				<div class="message"><p>%s</p><p>Thanks in advance for any advice, and apologies if this has been asked before on the forum.</p></div>
+			</div>`, i+1, post.title, i+1, post.text))
+		}
+		return `<html><head><title>Baking Forum</title></head><body>
+			<nav><a href="/">Home</a> <a href="/bread">Bread</a> <a href="/cakes">Cakes</a></nav>
+			<div class="thread">` + thread.String() + `</div>
+			<footer><p>Baking Forum is run by volunteers. All posts are the opinions of their authors.</p></footer>
+		</body></html>`
+	}
+
+	articles, err := readabiligo.New().(readabiligo.ExtendedExtractor).ExtractAll(page(len(posts)), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract articles: %v", err)
+	}
+	if len(articles) != len(posts) {
+		t.Fatalf("Expected %d articles, got %d", len(posts), len(articles))
+	}
+	for i, article := range articles {
+		if article.Title != posts[i].title {
+			t.Errorf("Article %d: expected title %q, got %q", i, posts[i].title, article.Title)
+		}
+		for j, post := range posts {
+			contains := strings.Contains(article.PlainContent, post.text)
+			if contains != (i == j) {
+				t.Errorf("Article %d: contains text of post %d = %v", i, j, contains)
+			}
+		}
+		if !strings.Contains(article.Content, fmt.Sprintf(`id="post-%d"`, i+1)) {
+			t.Errorf("Article %d does not hold the whole post", i)
+		}
+		if strings.Contains(article.PlainContent, "run by volunteers") {
+			t.Errorf("Article %d includes the page footer", i)
+		}
+	}
+
+	// A page with a single article yields a single region
+	articles, err = readabiligo.New().(readabiligo.ExtendedExtractor).ExtractAll(page(1), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract articles: %v", err)
+	}
+	if len(articles) != 1 {
+		t.Errorf("Expected 1 article, got %d", len(articles))
+	}
+}
+
+// TestByteOrderMark tests that a leading byte order mark does not end up in
+// the extracted title or text
+func TestByteOrderMark(t *testing.T) {
+	html := "\ufeff" + `<html><head><title>Notes from the allotment</title></head><body>
+		<article>
+			<p>The broad beans went in early this year, and despite a cold March they have come through with hardly a gap in the rows.</p>
+			<p>Next month the squash seedlings will be hardened off on the windowsill before they are planted out beside the compost heap.</p>
+		</article>
+	</body></html>`
+
+	article, err := readabiligo.New().ExtractFromReader(strings.NewReader(html), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.Title != "Notes from the allotment" {
+		t.Errorf("Expected a clean title, got %q", article.Title)
+	}
+	if strings.ContainsRune(article.Content, '\ufeff') || strings.ContainsRune(article.PlainContent, '\ufeff') {
+		t.Errorf("Content contains a byte order mark")
+	}
+	for _, block := range article.PlainText {
+		if strings.ContainsRune(block.Text, '\ufeff') {
+			t.Errorf("Block %q contains a byte order mark", block.Text)
+		}
+	}
+
+	// Options built as a literal strip it too
+	article, err = readabiligo.New().ExtractFromHTML(html, &readabiligo.ExtractionOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.Title != "Notes from the allotment" || strings.ContainsRune(article.Content, '\ufeff') {
+		t.Errorf("Expected a clean title and content with literal options, got %q", article.Title)
+	}
+}
+
+// TestDetectedCharset tests that ExtractFromReader reports the encoding it
+// decoded the document from, and that a forced encoding bypasses detection
+func TestDetectedCharset(t *testing.T) {
+	html := `<html><head><meta charset="windows-1251"><title>Заметки с огорода</title></head><body>
+		<article>
+			<p>Бобы в этом году посадили рано, и несмотря на холодный март они взошли почти без пропусков в рядах.</p>
+		</article>
+	</body></html>`
+	encoded, err := charmap.Windows1251.NewEncoder().String(html)
+	if err != nil {
+		t.Fatalf("Failed to encode document: %v", err)
+	}
+
+	article, err := readabiligo.New().ExtractFromReader(strings.NewReader(encoded), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.DetectedCharset != "windows-1251" {
+		t.Errorf("Expected detected charset windows-1251, got %q", article.DetectedCharset)
+	}
+	if article.Title != "Заметки с огорода" {
+		t.Errorf("Expected the title to be decoded, got %q", article.Title)
+	}
+
+	// Forcing UTF-8 on the windows-1251 bytes produces mojibake
+	forced := readabiligo.New(readabiligo.WithForcedEncoding("utf-8"))
+	article, err = forced.ExtractFromReader(strings.NewReader(encoded), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.DetectedCharset != "utf-8" {
+		t.Errorf("Expected forced charset utf-8, got %q", article.DetectedCharset)
+	}
+	if strings.Contains(article.Title, "Заметки") || !strings.ContainsRune(article.Title, '\ufffd') {
+		t.Errorf("Expected a garbled title when forcing UTF-8, got %q", article.Title)
+	}
+
+	// An unknown encoding is an error
+	if _, err := readabiligo.New(readabiligo.WithForcedEncoding("no-such-encoding")).ExtractFromReader(strings.NewReader(encoded), nil); err == nil {
+		t.Errorf("Expected an error for an unknown forced encoding")
+	}
+}
+
+// TestTitleHeadingWithSubtitle tests that a heading which extends the page
+// title with a subtitle is kept in the content, while Title stays the page title
+func TestTitleHeadingWithSubtitle(t *testing.T) {
+	page := func(heading string) string {
+		return `<html><head><title>Growing tomatoes on a small city balcony | Example Gardens</title></head><body>
+		<article>
+			<h1><span>` + heading + `</span></h1>
+			<p><span>Tomatoes need at least six hours of direct sun a day, so a balcony that faces south or west is the best place for them, even in a crowded city.</span></p>
+			<p><span>Choose compact varieties that were bred for containers, and give each plant a pot of at least twenty litres so that its roots never run short of water.</span></p>
+		</article>
+	</body></html>`
+	}
+
+	article, err := readabiligo.New().ExtractFromHTML(page("Growing tomatoes on a small city balcony: year two"), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.Title != "Growing tomatoes on a small city balcony" {
+		t.Errorf("Expected the page title without the site name, got %q", article.Title)
+	}
+	if !strings.Contains(article.Content, "Growing tomatoes on a small city balcony: year two") {
+		t.Errorf("Expected the subtitled heading to be kept in the content")
+	}
+
+	// A heading that only repeats the title is still removed as a duplicate
+	article, err = readabiligo.New().ExtractFromHTML(page("Growing Tomatoes on a Small City Balcony"), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if strings.Contains(article.Content, "<h1") {
+		t.Errorf("Expected the duplicate title heading to be removed, got %s", article.Content)
+	}
+}
+
 // TestRealWorldWebsites tests extraction from real-world websites
 // This test is skipped by default because it requires internet access
 func TestRealWorldWebsites(t *testing.T) {
@@ -652,3 +1148,199 @@ func readFileToString(path string) (string, error) {
 
 	return string(bytes), nil
 }
+
+// TestKbdAndSampKeptInContent tests that keyboard input and sample output
+// elements survive extraction so that they can be styled
+func TestKbdAndSampKeptInContent(t *testing.T) {
+	html := `<html><head><title>Stopping the development server</title></head><body>
+		<article>
+			<p><span>To stop the running server, press <kbd>Ctrl</kbd>+<kbd>C</kbd> in the terminal and wait for <samp>Server stopped</samp> to appear before closing the window.</span></p>
+		</article>
+	</body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if !strings.Contains(article.Content, "<kbd>Ctrl</kbd>") {
+		t.Errorf("Expected <kbd> to be kept in the content, got %s", article.Content)
+	}
+	if !strings.Contains(article.Content, "<samp>Server stopped</samp>") {
+		t.Errorf("Expected <samp> to be kept in the content, got %s", article.Content)
+	}
+}
+
+// TestExtractionQualitySelfTest tests that content that is mostly markup and
+// holds little of the page text, like a navigation list picked instead of the
+// article, is given a low confidence with warnings
+func TestExtractionQualitySelfTest(t *testing.T) {
+	notice := strings.Repeat("This notice explains at length the terms under which readers may use the site and its archives. ", 40)
+	html := `<html><head><title>Site index</title></head><body>
+		<div id="main"><ul>
+			<li><a href="https://example.com/sections/world-news/index.html">World</a></li>
+			<li><a href="https://example.com/sections/business-news/index.html">Business</a></li>
+			<li><a href="https://example.com/sections/technology-news/index.html">Tech</a></li>
+			<li><a href="https://example.com/sections/science-news/index.html">Science</a></li>
+		</ul></div>
+		<footer><p><span>` + notice + `</span></p></footer>
+	</body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.Confidence >= 0.5 {
+		t.Errorf("Expected a low confidence for a navigation list, got %v", article.Confidence)
+	}
+	if len(article.Warnings) != 2 {
+		t.Errorf("Expected warnings for both the markup ratio and the page text coverage, got %v", article.Warnings)
+	}
+
+	html = `<html><head><title>Pruning roses</title></head><body>
+		<article>
+			<p><span>Roses are best pruned in late winter, just before the buds begin to swell, so that the plant puts its energy into new growth.</span></p>
+			<p><span>Cut each cane just above an outward facing bud at a slight angle, and remove any dead or crossing wood at the base.</span></p>
+		</article>
+	</body></html>`
+
+	article, err = readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.Confidence != 1 || len(article.Warnings) != 0 {
+		t.Errorf("Expected full confidence without warnings for an article, got %v %v", article.Confidence, article.Warnings)
+	}
+}
+
+// TestMojibakeWarning tests that text garbled by a wrong character encoding,
+// such as UTF-8 accents read as Latin-1, is reported in the warnings
+func TestMojibakeWarning(t *testing.T) {
+	html := `<html><head><title>Le cafÃ© du coin</title></head><body>
+		<article>
+			<p><span>Le cafÃ© du quartier a rouvert aprÃ¨s trois mois de travaux, avec une nouvelle terrasse et une carte Ã©toffÃ©e.</span></p>
+			<p><span>Les habituÃ©s y retrouvent le mÃªme comptoir, et le patron promet que le prix du cafÃ© ne changera pas cette annÃ©e.</span></p>
+		</article>
+	</body></html>`
+
+	article, err := readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	found := false
+	for _, warning := range article.Warnings {
+		if strings.Contains(warning, "wrong character encoding") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a warning about the character encoding, got %v", article.Warnings)
+	}
+
+	html = strings.NewReplacer("Ã©", "é", "Ã¨", "è", "Ãª", "ê").Replace(html)
+	article, err = readabiligo.New().ExtractFromHTML(html, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if len(article.Warnings) != 0 {
+		t.Errorf("Expected no warnings for correctly decoded text, got %v", article.Warnings)
+	}
+}
+
+// TestTruncatedInput tests that a document cut off mid-article still yields the
+// content received so far and is flagged as partial
+func TestTruncatedInput(t *testing.T) {
+	complete := `<html><head><title>Walking the coast path</title></head><body>
+		<article>
+			<h1>Walking the coast path</h1>
+			<p><span>The coast path follows the cliffs for eleven miles between the two harbours, with steep climbs in and out of every cove along the way.</span></p>
+			<p><span>Most walkers take a full day over it, stopping for lunch at the cafe above the lifeboat station at the halfway point.</span></p>
+		</article>
+	</body></html>`
+	truncated := complete[:strings.Index(complete, "stopping for lunch")]
+
+	article, err := readabiligo.New().ExtractFromHTML(complete, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.IsPartial {
+		t.Errorf("Expected a complete document not to be flagged as partial")
+	}
+
+	// HTML allows the closing </body> and </html> tags to be left out
+	article, err = readabiligo.New().ExtractFromHTML(strings.TrimSuffix(complete, "</body></html>"), nil)
+	if err != nil {
+		t.Fatalf("Failed to extract article: %v", err)
+	}
+	if article.IsPartial {
+		t.Errorf("Expected a document without optional closing tags not to be flagged as partial")
+	}
+
+	article, err = readabiligo.New().ExtractFromHTML(truncated, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract truncated article: %v", err)
+	}
+	if !article.IsPartial {
+		t.Errorf("Expected a truncated document to be flagged as partial")
+	}
+	if !strings.Contains(article.Content, "Most walkers take a full day over it") {
+		t.Errorf("Expected the content received so far to be extracted, got %s", article.Content)
+	}
+
+	// A connection dropped mid-download keeps what was read
+	reader := io.MultiReader(strings.NewReader(truncated), iotest.ErrReader(io.ErrUnexpectedEOF))
+	article, err = readabiligo.New().ExtractFromReader(reader, nil)
+	if err != nil {
+		t.Fatalf("Failed to extract from interrupted reader: %v", err)
+	}
+	if !article.IsPartial || !strings.Contains(article.Content, "eleven miles") {
+		t.Errorf("Expected partial content from an interrupted reader, got %v %s", article.IsPartial, article.Content)
+	}
+	if len(article.Warnings) == 0 || !strings.Contains(article.Warnings[len(article.Warnings)-1], io.ErrUnexpectedEOF.Error()) {
+		t.Errorf("Expected the read error in the warnings, got %v", article.Warnings)
+	}
+}
+
+// TestContentHash tests that the content hash ignores the ads that extraction
+// removes and changes in whitespace, but changes with the article text
+func TestContentHash(t *testing.T) {
+	page := func(middle string) string {
+		return `<html><head><title>Repairing a bicycle puncture</title></head><body>
+		<article>
+			<p><span>Take the wheel off the bike, then lever one side of the tyre off the rim with two tyre levers.</span></p>` + middle + `
+			<p><span>Pump up the tube, find the hole by listening for the hiss, and roughen the rubber around it before applying the patch.</span></p>
+		</article>
+	</body></html>`
+	}
+	hash := func(html string) string {
+		article, err := readabiligo.New().ExtractFromHTML(html, nil)
+		if err != nil {
+			t.Fatalf("Failed to extract article: %v", err)
+		}
+		return article.ContentHash
+	}
+
+	plain := hash(page(""))
+	if len(plain) != 64 {
+		t.Fatalf("Expected a hex-encoded SHA-256 hash, got %q", plain)
+	}
+	withAd := hash(page(`<div class="advertisement"><p><span>Advertisement</span></p><a href="https://ads.example.com/"><span>Buy tyres</span></a></div>`))
+	if withAd != plain {
+		t.Errorf("Expected the same hash for a page with an ad, got %q and %q", withAd, plain)
+	}
+	respaced := hash(strings.ReplaceAll(page(""), "then lever", "then\n\t\tlever"))
+	if respaced != plain {
+		t.Errorf("Expected the same hash for a page with different whitespace, got %q and %q", respaced, plain)
+	}
+	edited := hash(strings.ReplaceAll(page(""), "two tyre levers", "three tyre levers"))
+	if edited == plain {
+		t.Errorf("Expected a different hash for a page with different text")
+	}
+
+	// A page too simple for its text to reach PlainText still hashes its text
+	simple := func(text string) string {
+		return hash(`<html><head><title>Opening hours</title></head><body><p>` + text + `</p></body></html>`)
+	}
+	if simple("The pool opens at nine.") == simple("The pool opens at ten.") {
+		t.Errorf("Expected a different hash for a simple page with different body text")
+	}
+}
@@ -3,8 +3,17 @@
 package readabiligo
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
 	"runtime"
+	"strings"
 	"time"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // Version information for the ReadabiliGo library.
@@ -13,18 +22,117 @@ const (
 	Name    = "ReadabiliGo"
 )
 
+// SchemaVersion identifies the shape of the JSON encoding of Article, and is
+// emitted as the "schema_version" field so downstream tools can branch on it.
+//
+// Versioning policy: the minor number is bumped whenever a field is added to
+// Article (or a type it embeds, like Block), and the major number is bumped
+// whenever a field is removed, renamed, or changes type or meaning. Consumers
+// that only read known fields can safely accept any version with the same major.
+const SchemaVersion = "2.0"
+
+
 // Block represents a block of text with optional metadata.
 // It is used to store paragraphs of plain text extracted from an article,
 // with optional node index information for tracking the source HTML elements.
 type Block struct {
-	Text      string `json:"text"`
-	NodeIndex string `json:"node_index,omitempty"`
+	Text      string    `json:"text"`
+	Type      BlockType `json:"type"`            // Kind of element the text came from
+	Level     int       `json:"level,omitempty"` // Heading level, list nesting depth or blockquote nesting depth
+	NodeIndex string    `json:"node_index,omitempty"`
+	Links     []Link    `json:"links,omitempty"` // Links in the block, set only when WithPreserveLinks is enabled
+	ParentIndex *int    `json:"parent_index,omitempty"` // Index of the block a sentence was split from, set only when WithSentenceSegmentation is enabled
+}
+
+// BlockType identifies the kind of element a block of text came from.
+// It is encoded in JSON by name, e.g. "heading".
+type BlockType int
+
+// Block type constants
+const (
+	BlockParagraph BlockType = iota // Paragraph
+	BlockHeading                    // Heading; Level is 1-6
+	BlockListItem                   // List item; Level is the list nesting depth, starting at 1
+	BlockQuote                      // Paragraph in a blockquote; Level is the quote nesting depth
+)
+
+// String returns a string representation of the block type
+func (bt BlockType) String() string {
+	switch bt {
+	case BlockHeading:
+		return "heading"
+	case BlockListItem:
+		return "list_item"
+	case BlockQuote:
+		return "blockquote"
+	default:
+		return "paragraph"
+	}
+}
+
+// MarshalText encodes the block type by name.
+func (bt BlockType) MarshalText() ([]byte, error) {
+	return []byte(bt.String()), nil
+}
+
+// UnmarshalText decodes a block type name produced by MarshalText.
+func (bt *BlockType) UnmarshalText(text []byte) error {
+	for _, t := range []BlockType{BlockParagraph, BlockHeading, BlockListItem, BlockQuote} {
+		if t.String() == string(text) {
+			*bt = t
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown block type %q", text)
+}
+
+// StructuredText renders blocks as plain text with Markdown-style structure
+// markers: headings are prefixed with one "#" per level, list items with "- "
+// (indented two spaces per nesting level) and quoted paragraphs with "> " per
+// quote level. Unlike full Markdown, the text itself is not escaped. Blocks are
+// separated by blank lines, except for consecutive list items.
+func StructuredText(blocks []Block) string {
+	var sb strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			sb.WriteString("\n")
+			if block.Type != BlockListItem || blocks[i-1].Type != BlockListItem {
+				sb.WriteString("\n")
+			}
+		}
+
+		level := block.Level
+		if level < 1 {
+			level = 1
+		}
+		switch block.Type {
+		case BlockHeading:
+			sb.WriteString(strings.Repeat("#", level) + " ")
+		case BlockListItem:
+			sb.WriteString(strings.Repeat("  ", level-1) + "- ")
+		case BlockQuote:
+			sb.WriteString(strings.Repeat("> ", level))
+		}
+		sb.WriteString(block.Text)
+	}
+	if len(blocks) > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Link represents a hyperlink within a block of text.
+type Link struct {
+	Href string `json:"href"` // Link target
+	Text string `json:"text"` // Anchor text
+	Rel  string `json:"rel,omitempty"` // Link types from the rel attribute, e.g. "nofollow sponsored"
 }
 
 // Article represents the extracted content and metadata from a webpage.
 // It contains the article title, byline, publication date, HTML content,
 // simplified HTML content, plain text paragraphs, and detected content type.
 type Article struct {
+	SchemaVersion string     `json:"schema_version"`
 	Title        string      `json:"title"`
 	Byline       string      `json:"byline"`
 	Date         time.Time   `json:"date"`
@@ -32,17 +140,335 @@ type Article struct {
 	PlainContent string      `json:"plain_content"`
 	PlainText    []Block     `json:"plain_text"`
 	ContentType  ContentType `json:"content_type"`
+
+	// ReadabilityScore is the Flesch-Kincaid grade level of the extracted text,
+	// set only when WithComputeReadingLevel is enabled. Higher values indicate
+	// more complex text. The score is English-centric and is left at 0 for
+	// documents that declare a non-English language.
+	ReadabilityScore float64 `json:"readability_score,omitempty"`
+
+	// AuthorImageURL is the URL of the author's profile image, set only when
+	// WithExtractAuthorImage is enabled. It is resolved against the document's
+	// base URL when one is declared.
+	AuthorImageURL string `json:"author_image_url,omitempty"`
+
+	// ThemeColor is the page's theme color from <meta name="theme-color">, as
+	// lowercase hex when possible, set only when WithExtractThemeColor is enabled.
+	ThemeColor string `json:"theme_color,omitempty"`
+
+	// Metadata holds the fields found by the metadata extractors: the built-in
+	// OpenGraph, microdata and JSON-LD extractors, followed by any registered
+	// with WithMetadataExtractor. Later extractors override earlier ones.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// ImageCount is the number of images in the article, counted before any
+	// were removed to honor WithMaxImages.
+	ImageCount int `json:"image_count"`
+
+	// AlternateLinks lists the alternate versions of the page declared in its
+	// head, such as RSS and Atom feeds, the AMP version and translations, with
+	// absolute URLs, set only when WithExtractAlternates is enabled.
+	AlternateLinks []AlternateLink `json:"alternate_links,omitempty"`
+
+	// Videos indexes the video embeds kept in Content, set only when
+	// WithExtractVideos is enabled. The embeds themselves remain in Content.
+	Videos []VideoEmbed `json:"videos,omitempty"`
+
+	// CodeBlocks indexes the preformatted code blocks kept in Content, with their
+	// language and caption, set only when WithExtractCodeBlocks is enabled. The
+	// code itself remains in Content.
+	CodeBlocks []CodeBlock `json:"code_blocks,omitempty"`
+
+	// Tables holds the data tables kept in Content as grids of cell text, with
+	// the caption and summary that describe them, set only when
+	// WithExtractTables is enabled. Layout tables are left out.
+	Tables []Table `json:"tables,omitempty"`
+
+	// Quotes holds the block and inline quotations kept in Content, with the URL
+	// of their source from the cite attribute and the name they are attributed
+	// to, set only when WithExtractQuotes is enabled.
+	Quotes []Quote `json:"quotes,omitempty"`
+
+	// IsTruncated reports that the content is only a teaser (such as an SEO stub)
+	// ending in a "Continue reading" link, and FullContentURL is that link's URL.
+	// Callers can follow FullContentURL to extract the full text. It is also set
+	// when the content was cut to the length set with WithContentMaxLength.
+	IsTruncated    bool   `json:"is_truncated,omitempty"`
+	FullContentURL string `json:"full_content_url,omitempty"`
+
+	// IsDocumentLanding reports that the page is a landing page for a linked PDF,
+	// such as a report page holding little more than a download link, and
+	// PrimaryDocumentURL is that link's URL. Both are set only when
+	// WithDetectPrimaryDocument is enabled. The PDF itself is not fetched.
+	IsDocumentLanding  bool   `json:"is_document_landing,omitempty"`
+	PrimaryDocumentURL string `json:"primary_document_url,omitempty"`
+
+	// RemovedLinks lists the links that were in the article region but were
+	// removed during cleanup, with the reason their element was removed, set
+	// only when WithTrackRemovedLinks is enabled. It is meant for auditing what
+	// the extraction discarded.
+	RemovedLinks []RemovedLink `json:"removed_links,omitempty"`
+
+	// Thread holds the posts of the page's comment thread, such as the comments
+	// under a blog post or the replies of a forum or Reddit-style discussion, in
+	// document order, set only when WithDiscussionMode is enabled. Replies keep
+	// their place in the hierarchy through Depth and ParentIndex.
+	Thread []ThreadPost `json:"thread,omitempty"`
+
+	// Updates holds the timestamped entries of a live blog in chronological
+	// order, even when the page lists the latest entry first, set only when
+	// WithLiveBlogMode is enabled.
+	Updates []LiveUpdate `json:"updates,omitempty"`
+
+	// Recipe and HowTo hold the schema.org Recipe and HowTo described by the
+	// page's JSON-LD, with their ingredients or supplies and their steps, set
+	// only when WithExtractStructuredContent is enabled and the page has them.
+	// Content is extracted as usual either way.
+	Recipe *Recipe `json:"recipe,omitempty"`
+	HowTo  *HowTo  `json:"how_to,omitempty"`
+
+	// ContactInfo holds the email addresses and phone numbers of the content,
+	// from mailto: and tel: links and from its text, set only when
+	// WithExtractContactInfo is enabled and the content has some.
+	ContactInfo *ContactInfo `json:"contact_info,omitempty"`
+
+	// PullQuotes holds the text of the pull-quotes removed from the content,
+	// the enlarged restatements of a sentence that magazine layouts set apart,
+	// in document order, set only with WithPullQuoteMode(PullQuoteExtract).
+	PullQuotes []string `json:"pull_quotes,omitempty"`
+
+	// Keywords holds the words that appear most often in the content, left
+	// out stopwords, most frequent first, set only when WithExtractKeywords is
+	// enabled. They are a heuristic for tagging untagged content.
+	Keywords []string `json:"keywords,omitempty"`
+
+	// ContentHash is the hex-encoded SHA-256 hash of the text of the content,
+	// with all whitespace normalized, for checking whether the article changed
+	// between crawls. Unlike content digests, it covers the whole article, and
+	// changes in the boilerplate that extraction removes don't affect it.
+	ContentHash string `json:"content_hash"`
+
+	// Confidence is how sure the extraction is, from 0 to 1, that Content is
+	// the real article rather than a near-empty container such as a navigation
+	// list. It is lowered when the content is mostly markup or holds only a
+	// tiny part of the page's text, and Warnings explains why.
+	Confidence float64  `json:"confidence"`
+	Warnings   []string `json:"warnings,omitempty"`
+
+	// IsPartial reports that the input appears to have been cut off, as when a
+	// connection drops mid-download: it ends inside a tag, leaves open an
+	// element whose end tag is required, or ExtractFromReader failed to read it
+	// to the end (the read error is then listed in Warnings). The article is
+	// extracted from the part of the document received.
+	IsPartial bool `json:"is_partial,omitempty"`
+
+	// DetectedCharset is the encoding ExtractFromReader decoded the document
+	// from: the one declared in the document, the sniffed one, or the one forced
+	// with WithForcedEncoding. It is empty for ExtractFromHTML, whose input is
+	// already a string.
+	DetectedCharset string `json:"detected_charset,omitempty"`
+
+	// ExtractedAt records when the extraction happened, or the reference time
+	// set with WithReferenceTime, for cache invalidation and provenance.
+	ExtractedAt time.Time `json:"extracted_at"`
+}
+
+// ArticleVariants holds the results of the strict and lenient extraction passes.
+// When the strict attempt finds too little content, extraction normally retries
+// with progressively relaxed heuristics; the lenient variant is that default
+// result, while the strict variant is the first attempt with all heuristics on.
+// Both variants are identical when the strict attempt is long enough.
+type ArticleVariants struct {
+	Strict  *Article `json:"strict,omitempty"` // First attempt; nil if it found no content
+	Lenient *Article `json:"lenient"`          // Result after any relaxed retries
+}
+
+// BatchInput is a document to extract with ExtractBatch. Open is called only
+// when the document is extracted, so that a large batch does not hold every
+// document open at once.
+type BatchInput struct {
+	Name string                       // Name identifying the document, such as its file path
+	Open func() (io.ReadCloser, error) // Opens the document for reading
+}
+
+// FileInput returns a BatchInput that reads the file at path.
+func FileInput(path string) BatchInput {
+	return BatchInput{
+		Name: path,
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}
+}
+
+// BatchResult is the result of extracting one BatchInput. Err is set when the
+// document could not be opened or extracted, in which case Article is nil.
+type BatchResult struct {
+	Index   int      // Position of the input in the batch
+	Name    string   // Name of the input
+	Article *Article // Extracted article
+	Err     error    // Error opening or extracting the input
+}
+
+// MarshalJSON encodes the article as JSON. Date and ExtractedAt are written in
+// RFC 3339 format and omitted when unset, instead of appearing as Go's zero
+// time ("0001-01-01T00:00:00Z").
+func (a Article) MarshalJSON() ([]byte, error) {
+	type article Article // Drops this method to avoid recursion
+	aux := struct {
+		article
+		Date        string `json:"date,omitempty"`
+		ExtractedAt string `json:"extracted_at,omitempty"`
+	}{article: article(a)}
+	if !a.Date.IsZero() {
+		aux.Date = a.Date.Format(time.RFC3339)
+	}
+	if !a.ExtractedAt.IsZero() {
+		aux.ExtractedAt = a.ExtractedAt.Format(time.RFC3339)
+	}
+	return json.Marshal(aux)
+}
+
+// MarshalJSONIndent is like MarshalJSON but applies indentation, with the same
+// prefix and indent semantics as json.MarshalIndent.
+func (a Article) MarshalJSONIndent(prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(a, prefix, indent)
+}
+
+// DefaultBlockSeparator is the separator PlainTextString puts between blocks: a
+// blank line, as between paragraphs
+const DefaultBlockSeparator = "\n\n"
+
+// Text returns the text of the PlainText blocks joined with separator, e.g. "\n"
+// for one block per line. Block types and levels are not rendered; see
+// StructuredText for that.
+func (a Article) Text(separator string) string {
+	texts := make([]string, len(a.PlainText))
+	for i, block := range a.PlainText {
+		texts[i] = block.Text
+	}
+	return strings.Join(texts, separator)
+}
+
+// PlainTextString returns the text of the PlainText blocks separated by blank
+// lines, as Text(DefaultBlockSeparator)
+func (a Article) PlainTextString() string {
+	return a.Text(DefaultBlockSeparator)
+}
+
+// VideoEmbed describes a video embedded in the article content.
+type VideoEmbed struct {
+	URL      string `json:"url"`              // Embed URL
+	Provider string `json:"provider"`         // Provider derived from the embed host, e.g. "youtube" or "vimeo"
+	Title    string `json:"title,omitempty"`  // Title from the embed's title attribute
+	Poster   string `json:"poster,omitempty"` // Poster image URL, if declared
+}
+
+// CodeBlock describes a preformatted code block in the article content.
+type CodeBlock struct {
+	Language string `json:"language,omitempty"` // Language from data-lang or a language-* class, lowercased, e.g. "go"
+	Caption  string `json:"caption,omitempty"`  // Caption of the enclosing figure, such as a filename
+	Code     string `json:"code"`               // Code text, with its line breaks and indentation
+}
+
+// AlternateLink describes an alternate version of the page.
+type AlternateLink struct {
+	Type     string `json:"type,omitempty"`     // MIME type, e.g. "application/rss+xml", or "amp" for the AMP version
+	Href     string `json:"href"`               // Absolute URL of the alternate version
+	Title    string `json:"title,omitempty"`    // Title from the link's title attribute
+	Hreflang string `json:"hreflang,omitempty"` // Language of a translated version, e.g. "de"
+}
+
+// Table describes a data table in the article content.
+type Table struct {
+	Caption string     `json:"caption,omitempty"` // Text of the <caption> element
+	Summary string     `json:"summary,omitempty"` // Value of the table's summary attribute
+	Headers []string   `json:"headers,omitempty"` // Header cells, from the thead or a leading row of th cells
+	Rows    [][]string `json:"rows"`              // Cell text of the other rows
+}
+
+// Quote describes a quotation in the article content.
+type Quote struct {
+	Text        string `json:"text"`                  // Text of the quotation, without its attribution
+	CiteURL     string `json:"cite_url,omitempty"`    // Absolute URL of the source, from the cite attribute
+	Attribution string `json:"attribution,omitempty"` // Who or what is quoted, e.g. from a <cite> element
+}
+
+// ThreadPost is a post of a discussion thread.
+type ThreadPost struct {
+	Author      string `json:"author,omitempty"`    // Author name
+	Timestamp   string `json:"timestamp,omitempty"` // As written: the datetime attribute when present, e.g. "2024-05-01T09:30:00Z", otherwise the text
+	Body        string `json:"body"`                // Text of the post, with paragraphs separated by blank lines
+	Depth       int    `json:"depth"`               // Nesting depth; top-level posts are at depth 0
+	ParentIndex int    `json:"parent_index"`        // Index in Thread of the post this one replies to, or -1 for top-level posts
+}
+
+// LiveUpdate is a timestamped entry of a live blog.
+type LiveUpdate struct {
+	Timestamp string `json:"timestamp,omitempty"` // As written: the datetime attribute when present, e.g. "2024-05-01T09:30:00Z", otherwise the text
+	Body      string `json:"body"`                // Text of the entry, with paragraphs separated by blank lines
+}
+
+// Recipe is a recipe described by schema.org JSON-LD.
+type Recipe struct {
+	Name        string      `json:"name,omitempty"`        // Name of the dish
+	Description string      `json:"description,omitempty"` // Short description of the recipe
+	Ingredients []string    `json:"ingredients,omitempty"` // Ingredients with their quantities, as written, e.g. "2 cups flour"
+	Steps       []HowToStep `json:"steps,omitempty"`       // Instructions in order
+	PrepTime    string      `json:"prep_time,omitempty"`   // Preparation time as an ISO 8601 duration, e.g. "PT15M"
+	CookTime    string      `json:"cook_time,omitempty"`   // Cooking time as an ISO 8601 duration
+	TotalTime   string      `json:"total_time,omitempty"`  // Total time as an ISO 8601 duration
+	Yield       string      `json:"yield,omitempty"`       // Quantity produced, e.g. "4 servings"
+}
+
+// HowTo is a set of instructions described by schema.org JSON-LD.
+type HowTo struct {
+	Name        string      `json:"name,omitempty"`        // Name of the task
+	Description string      `json:"description,omitempty"` // Short description of the task
+	Supplies    []string    `json:"supplies,omitempty"`    // Supplies consumed by the task
+	Tools       []string    `json:"tools,omitempty"`       // Tools used but not consumed by the task
+	Steps       []HowToStep `json:"steps,omitempty"`       // Steps in order
+	TotalTime   string      `json:"total_time,omitempty"`  // Total time as an ISO 8601 duration, e.g. "PT30M"
+	Yield       string      `json:"yield,omitempty"`       // Result of the task, e.g. "1 bookshelf"
+}
+
+// HowToStep is a step of a Recipe or HowTo.
+type HowToStep struct {
+	Section string `json:"section,omitempty"` // Name of the section holding the step, when steps are grouped
+	Name    string `json:"name,omitempty"`    // Short name of the step, when it isn't just the start of Text
+	Text    string `json:"text"`              // Instructions of the step
+}
+
+// ContactInfo holds the contact details found in the article content.
+type ContactInfo struct {
+	Emails []string `json:"emails,omitempty"` // Lowercase email addresses
+	Phones []string `json:"phones,omitempty"` // Phone numbers as digits, with a leading "+" when international, e.g. "+442079460958"
+}
+
+// RemovedLink describes a link removed from the article during cleanup.
+type RemovedLink struct {
+	Href   string `json:"href"`   // Link target
+	Text   string `json:"text"`   // Anchor text
+	Reason string `json:"reason"` // Why its element was removed: "footer", "aside", "nav", "share", "related", "link-density", ...
+}
+
+// MetadataExtractor extracts metadata fields from a document. Extractors are
+// given the parsed document before any cleanup, and must not modify it.
+// Register custom extractors with WithMetadataExtractor.
+type MetadataExtractor interface {
+	Extract(doc *goquery.Document) map[string]string
 }
 
 // ContentType represents the type of content in a document.
-// This type is maintained for backward compatibility but no longer affects extraction.
-// The extraction now uses Mozilla's original unified algorithm for all content types.
-// Deprecated: This type no longer affects extraction behavior.
+// Content types are not detected: Article.ContentType is ContentTypeArticle,
+// ContentTypeError for error pages without extractable content, or the content
+// type assigned with WithContentTypeRule or WithContentType. Two content types
+// change extraction: ContentTypePaywall keeps hidden paywalled text, and
+// ContentTypeMinimal keeps the login or sign-up form that is the page's
+// content. The other content types only label the page.
 type ContentType int
 
-// Content type constants - maintained for backward compatibility
-// These constants no longer affect the extraction behavior.
-// Deprecated: These constants no longer affect extraction behavior.
+// Content types
 const (
 	ContentTypeUnknown ContentType = iota
 	ContentTypeReference  // Wikipedia, documentation
@@ -73,6 +499,115 @@ func (ct ContentType) String() string {
 	}
 }
 
+// ContentTypeRule assigns a content type to pages on which Selector matches,
+// overriding the content type the page would otherwise get.
+// Register rules with WithContentTypeRule.
+type ContentTypeRule struct {
+	Selector    string      // CSS selector matched against the page before cleanup
+	ContentType ContentType // Content type applied when the selector matches
+}
+
+// QuoteStyle selects the quotation marks used when inline <q> quotations
+// are rendered as text in the simplified output.
+type QuoteStyle int
+
+// Quote style constants
+const (
+	QuoteStyleStraight   QuoteStyle = iota // "quote" (default, leaves <q> elements in PlainContent)
+	QuoteStyleCurly                        // “quote”
+	QuoteStyleGuillemets                   // «quote»
+)
+
+// String returns a string representation of the quote style
+func (qs QuoteStyle) String() string {
+	switch qs {
+	case QuoteStyleCurly:
+		return "Curly"
+	case QuoteStyleGuillemets:
+		return "Guillemets"
+	default:
+		return "Straight"
+	}
+}
+
+// PullQuoteMode selects what extraction does with pull-quotes, the enlarged
+// restatements of a sentence of the article that magazine layouts set apart
+// and that repeat it in the text.
+type PullQuoteMode int
+
+// Pull-quote mode constants
+const (
+	PullQuoteKeep    PullQuoteMode = iota // Leave pull-quotes in the content (default)
+	PullQuoteRemove                       // Remove pull-quotes from the content
+	PullQuoteExtract                      // Remove pull-quotes from the content into Article.PullQuotes
+)
+
+// String returns a string representation of the pull-quote mode
+func (m PullQuoteMode) String() string {
+	switch m {
+	case PullQuoteRemove:
+		return "Remove"
+	case PullQuoteExtract:
+		return "Extract"
+	default:
+		return "Keep"
+	}
+}
+
+// ScoringProfile names a preset of extraction settings tuned for a class of
+// content. See WithScoringProfile for the settings each profile applies.
+type ScoringProfile int
+
+// Scoring profile constants
+const (
+	ProfileDefault ScoringProfile = iota // General-purpose settings
+	ProfileNews                          // News articles
+	ProfileWiki                          // Wiki and reference pages
+	ProfileBlog                          // Blog posts
+	ProfileForum                         // Forum threads and discussions
+)
+
+// String returns a string representation of the scoring profile
+func (sp ScoringProfile) String() string {
+	switch sp {
+	case ProfileNews:
+		return "News"
+	case ProfileWiki:
+		return "Wiki"
+	case ProfileBlog:
+		return "Blog"
+	case ProfileForum:
+		return "Forum"
+	default:
+		return "Default"
+	}
+}
+
+// RetryStep is a heuristic that extraction relaxes when the article it finds is
+// shorter than CharThreshold. Each retry extracts the page again with the steps
+// relaxed so far.
+type RetryStep int
+
+const (
+	RetryStripUnlikelys    RetryStep = iota // Keep elements whose class or id suggests boilerplate, like "sidebar"
+	RetryWeightClasses                      // Stop scoring elements by the words in their class and id
+	RetryCleanConditionally                 // Stop removing elements that don't look like content
+)
+
+// String returns a string representation of the retry step
+func (rs RetryStep) String() string {
+	switch rs {
+	case RetryStripUnlikelys:
+		return "StripUnlikelys"
+	case RetryWeightClasses:
+		return "WeightClasses"
+	case RetryCleanConditionally:
+		return "CleanConditionally"
+	default:
+		return "Unknown"
+	}
+}
+
 // ExtractionOptions configures the article extraction process.
 // It controls whether to include content digests and node indexes,
 // and sets limits on buffer size and extraction timeout.
@@ -81,26 +616,182 @@ type ExtractionOptions struct {
 	NodeIndexes          bool          // Add node index attributes
 	MaxBufferSize        int           // Maximum buffer size for content processing
 	Timeout              time.Duration // Timeout for extraction process
+	PhaseTimeouts        map[Phase]time.Duration // Time budgets of individual extraction phases (none by default)
+	KeepByteOrderMark    bool          // Keep a leading byte order mark in input instead of stripping it (and transcoding UTF-16 to UTF-8)
+	ForcedEncoding       string        // Encoding ExtractFromReader decodes input from, bypassing detection ("" = detect)
+	ReferenceTime        time.Time     // Time recorded as ExtractedAt (zero = current time)
+	AssumeTimezone       *time.Location // Location of publication dates without timezone information (nil = only RFC 3339 dates)
+	MetadataExtractors   []MetadataExtractor // Custom metadata extractors, run after the built-in ones
+	PostExtractHook      func(*goquery.Selection) // Custom transformation of the article node before the final cleanup
+	Logger               *slog.Logger  // Logger of extraction debug events (nil = none)
+	BatchJobs            int           // Number of documents ExtractBatch extracts concurrently (1 = one at a time)
+	OutputCallback       func(BatchResult) // Called by ExtractBatch with each result as it completes, never concurrently
+	CharThreshold        int           // Minimum article length before relaxed heuristics are tried (0 = default of 500)
+	LinkDensityModifier  float64       // Added to the link density thresholds used to remove link-heavy elements
+	CMSContentClasses    []string      // CMS content container classes that get a scoring bonus (empty disables)
+	UnlikelyRoles        []string      // ARIA roles whose elements are dropped, in addition to DefaultUnlikelyRoles
+	AllowedRoles         []string      // ARIA roles whose elements are never dropped for their role
+	AllowedSchemes       []string      // URL schemes of the links and images kept in Content (empty allows all but javascript)
+	RetryStrategy        []RetryStep   // Heuristics relaxed in turn when the article is too short (nil = DefaultRetryStrategy, empty disables the retries)
+	KeepStructure        bool          // Keep lists and heading-plus-list sections during conditional cleaning
 	PreserveImportantLinks bool        // Preserve important links in cleaned elements (like "More information...")
-	DetectContentType    bool          // Deprecated: No longer has any effect, maintained for backward compatibility
-	ContentType          ContentType   // Deprecated: No longer has any effect, maintained for backward compatibility
+	DetectContentType    bool          // Deprecated: Has no effect, content types are never detected
+	ContentType          ContentType   // Deprecated: Content type assigned to every page; use ContentTypeRules
+	ContentTypeRules     []ContentTypeRule // Selector rules that override the content type; the first match wins
+	ExtractTemplates     bool          // Promote article-like <template> content when the visible DOM is sparse
+	UseNoscriptFallback  bool          // Promote article-like <noscript> content when the visible DOM is sparse
+	QuoteStyle           QuoteStyle    // Quotation marks used for <q> elements in PlainContent
+	ComputeReadingLevel  bool          // Compute the Flesch-Kincaid grade level of the extracted text
+	PreserveIDs          bool          // Keep element ids usable as deep-link targets (unique ids, relative in-page anchors)
+	StripAutogeneratedIDs bool         // Remove ids matching AutogeneratedIDPatterns, keeping authored ones
+	AutogeneratedIDPatterns []*regexp.Regexp // Patterns of ids generated by frameworks rather than written by authors
+	ExcludePlainTextSelectors []string // CSS selectors whose text is left out of PlainText (Content is unaffected)
+	NormalizePunctuation bool          // Canonicalize quotes, dashes and non-breaking spaces in PlainContent/PlainText
+	DashReplacement      string        // Replacement for en/em dashes when normalizing punctuation ("" keeps dashes)
+	ContentLanguage      string        // Primary content language; elements declaring another lang are dropped ("" disables)
+	ExtractAuthorImage   bool          // Extract the author's profile image URL into AuthorImageURL
+	ExtractThemeColor    bool          // Extract the page's theme color into ThemeColor
+	ExtractAlternates    bool          // List the page's feeds, AMP version and translations into Article.AlternateLinks
+	CollapseBreaks       bool          // Collapse runs of <br> shorter than ParagraphBreakThreshold into line breaks
+	ParagraphBreakThreshold int        // Minimum number of consecutive <br> treated as a paragraph break
+	MaxLineBreaks        int           // Maximum number of <br> kept from shorter runs
+	TablesVerbatim       bool          // Copy data tables into PlainContent verbatim (minus class/style)
+	ExtractVideos        bool          // Index the video embeds kept in Content into Article.Videos
+	ExtractCodeBlocks    bool          // Index the code blocks kept in Content into Article.CodeBlocks
+	ExtractTables        bool          // Index the data tables kept in Content into Article.Tables
+	ExtractQuotes        bool          // Index the quotations kept in Content into Article.Quotes
+	DetectPrimaryDocument bool         // Detect landing pages whose real content is a linked PDF
+	TrackRemovedLinks    bool          // Record the links removed from the article region into Article.RemovedLinks
+	DiscussionMode       bool          // Walk the page's comments into Article.Thread, keeping the reply hierarchy
+	LiveBlogMode         bool          // Extract the timestamped entries of a live blog into Article.Updates, oldest first
+	ExtractStructuredContent bool      // Read the schema.org Recipe and HowTo JSON-LD into Article.Recipe and Article.HowTo
+	ExtractContactInfo   bool          // Collect the email addresses and phone numbers of the content into Article.ContactInfo
+	ExtractKeywords      int           // Number of keywords ranked into Article.Keywords (0 = none)
+	ContentBoundaryMarkers bool        // Wrap Content in comments naming the node it was extracted from and its score
+	StrictTitle          bool          // Fail with ErrNoTitle when no confident title can be resolved
+	MinContentScore      float64       // Fail with ErrNoContent when the top candidate scores below it (0 = disabled)
+	StripHeaderAnchors   bool          // Remove permalink anchors (e.g. "¶") from headings
+	TrimBoilerplateHeadings bool       // Remove headings and blocks whose entire text is one of BoilerplateLabels
+	BoilerplateLabels    []string      // Advertisement labels such as "Advertisement" or "Sponsored Content" (empty disables)
+	NormalizeHeadingWhitespace bool    // Put heading text in Title and PlainText on one line, reading <br> as a space
+	HeadingBlocks        bool          // Include the headings of the content as heading blocks in PlainText
+	StripEmptyAnchors    bool          // Remove links left without text or media
+	StripHiddenText      bool          // Remove elements hidden with display:none, visibility:hidden, hidden or aria-hidden
+	StripConsentBanners  bool          // Remove cookie and GDPR consent banners
+	StripBylineFromContent bool        // Remove the byline element from Content once it is captured into Byline
+	PreserveLinks        bool          // Record the links found in each plain text block
+	MinifyOutput         bool          // Remove insignificant inter-element whitespace from Content and PlainContent
+	CollapseWhitespaceInAttributes bool   // Collapse whitespace in alt, title and aria-label values to single spaces
+	ContentMaxLength     int           // Maximum text length of Content, cut at a block boundary (0 = unlimited)
+	MaxImages            int           // Maximum number of images kept in Content, always including the lead image (0 = unlimited)
+	ImageDimensionInference bool       // Infer missing image dimensions from URLs like "photo-1200x800.jpg" or "?w=1200"
+	ProtectLeadParagraphs int          // Number of leading paragraphs that conditional cleaning never removes (0 = none)
+	PreserveMath         bool          // Keep MathML and MathJax LaTeX equations, rendered as LaTeX in PlainText
+	RenderMathAsText     bool          // Replace MathML equations with a plain text rendering such as "(a + b)/2"
+	PreserveKbdAndSamp   bool          // Keep <kbd> and <samp> in PlainContent as bare tags, with their attributes stripped
+	ReorderColumns       bool          // Put multi-column layouts whose DOM interleaves the columns into reading order
+	StripLeadingSymbols  bool          // Remove decorative emoji, bullets and arrows from the start of Title
+	ParallelScoring      bool          // Score content elements on one goroutine per CPU, with the same results as serial scoring
+	PullQuoteMode        PullQuoteMode // Keep pull-quotes, remove them, or remove them into Article.PullQuotes
+	PreserveSemanticStyles bool        // Turn bold, italic, line-through and underline inline styles into elements
+	SentenceSegmentation bool          // Split PlainText blocks into one block per sentence
+	TextOnly             bool          // Only produce PlainText and a plain text PlainContent, leaving Content empty
+	ExpandDetails        bool          // Convert <details>/<summary> into a visible section headed by the summary
+	Dehyphenate          bool          // Join words broken across lines with a hyphen, as in OCR or PDF-converted text
+	NormalizeListMarkers bool          // Turn runs of paragraphs starting with "•", "-" or "1." into real lists
+	MergeImageAltIntoCaption bool      // Add a figcaption holding the image's alt text to figures without a caption
+	PreserveGalleries    bool          // Keep containers of captioned images with little prose, as in photo essays
+	MergeListsAcrossParagraphs bool    // Rejoin adjacent lists of the same type that were split by removed elements
 }
 
 // DefaultOptions returns the default extraction options.
 // By default, the pure Go implementation is used, content digests and node indexes
 // are disabled, buffer size is limited to 1MB, and timeout is set to 30 seconds.
 // Important link preservation is disabled by default to match ReadabiliPy behavior.
-// Note: Content type detection and content type settings no longer have any effect,
-// as the implementation now uses Mozilla's unified algorithm for all content.
+// Content types are not detected, and Mozilla's unified algorithm is used for
+// all content, except that ContentTypePaywall keeps hidden paywalled text and
+// ContentTypeMinimal keeps the form that is the page's content.
 func DefaultOptions() ExtractionOptions {
 	return ExtractionOptions{
 		ContentDigests:       false,
 		NodeIndexes:          false,
 		MaxBufferSize:        1024 * 1024, // 1MB
 		Timeout:              time.Second * 30,
+		KeepByteOrderMark:    false,
+		ForcedEncoding:       "",
+		BatchJobs:            1,
 		PreserveImportantLinks: false, // Default to false to match ReadabiliPy behavior
-		DetectContentType:    false,   // No-op but set to false for clarity
-		ContentType:          ContentTypeArticle, // No-op but set to Article for clarity
+		DetectContentType:    false,   // No-op, content types are never detected
+		ContentType:          ContentTypeArticle, // Standard extraction; only Paywall and Minimal change it
+		ExtractTemplates:     false,
+		UseNoscriptFallback:  false,
+		QuoteStyle:           QuoteStyleStraight,
+		ComputeReadingLevel:  false,
+		PreserveIDs:          false,
+		StripAutogeneratedIDs: false,
+		AutogeneratedIDPatterns: DefaultAutogeneratedIDPatterns(),
+		NormalizePunctuation: false,
+		DashReplacement:      "-",
+		ContentLanguage:      "",
+		ExtractAuthorImage:   false,
+		ExtractThemeColor:    false,
+		ExtractAlternates:    false,
+		CollapseBreaks:       false,
+		ParagraphBreakThreshold: 3,
+		MaxLineBreaks:        1,
+		TablesVerbatim:       false,
+		ExtractVideos:        false,
+		ExtractCodeBlocks:    false,
+		ExtractTables:        false,
+		ExtractQuotes:        false,
+		DetectPrimaryDocument: false,
+		TrackRemovedLinks:    false,
+		DiscussionMode:       false,
+		LiveBlogMode:         false,
+		ExtractStructuredContent: false,
+		ExtractContactInfo:   false,
+		ExtractKeywords:      0,
+		ContentBoundaryMarkers: false,
+		ImageDimensionInference: false,
+		StrictTitle:          false,
+		MinContentScore:      0,
+		StripHeaderAnchors:   true,
+		TrimBoilerplateHeadings: true,
+		BoilerplateLabels:    DefaultBoilerplateLabels(),
+		NormalizeHeadingWhitespace: true,
+		HeadingBlocks:        false,
+		StripEmptyAnchors:    true,
+		StripHiddenText:      true,
+		StripConsentBanners:  true,
+		StripBylineFromContent: false,
+		PreserveLinks:        false,
+		MinifyOutput:         false,
+		CollapseWhitespaceInAttributes: false,
+		ContentMaxLength:     0,
+		MaxImages:            0,
+		ProtectLeadParagraphs: 0,
+		PreserveMath:         false,
+		RenderMathAsText:     false,
+		PreserveKbdAndSamp:   false,
+		ReorderColumns:       false,
+		StripLeadingSymbols:  false,
+		ParallelScoring:      false,
+		PullQuoteMode:        PullQuoteKeep,
+		PreserveSemanticStyles: false,
+		SentenceSegmentation: false,
+		TextOnly:             false,
+		CharThreshold:        500,
+		LinkDensityModifier:  0,
+		CMSContentClasses:    DefaultCMSContentClasses(),
+		AllowedSchemes:       DefaultAllowedSchemes(),
+		RetryStrategy:        DefaultRetryStrategy(),
+		KeepStructure:        false,
+		ExpandDetails:        false,
+		Dehyphenate:          false,
+		NormalizeListMarkers: false,
+		MergeImageAltIntoCaption: false,
+		PreserveGalleries:    false,
+		MergeListsAcrossParagraphs: true,
 	}
 }
 